@@ -1,21 +1,40 @@
 package core
 
 import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
 	"sync"
 	"time"
 )
 
+// ErrCacheItemTooLarge is returned when a value alone exceeds the cache's memory limit
+var ErrCacheItemTooLarge = errors.New("cache item is larger than the memory limit")
+
 // cacheItem represents a cached item with expiration
 type cacheItem struct {
 	value      interface{}
 	expiration time.Time
+	size       int64
+	// element is the item's place in the recency list; locks have none so
+	// they are never evicted
+	element *list.Element
 }
 
 // ArrayCacheDriver implements in-memory caching
 type ArrayCacheDriver struct {
 	*BaseCacheProvider
-	store map[string]cacheItem
-	mutex sync.RWMutex
+	store       map[string]cacheItem
+	mutex       sync.RWMutex
+	memoryBytes int64
+
+	// With a memory limit, keys are kept in recency order (front is most
+	// recently used) so the least recently used can be evicted
+	maxBytes  int64
+	recency   *list.List
+	evictions int64
 }
 
 // NewArrayCacheDriver creates a new array cache driver
@@ -23,49 +42,152 @@ func NewArrayCacheDriver(prefix string, ttl time.Duration) *ArrayCacheDriver {
 	return &ArrayCacheDriver{
 		BaseCacheProvider: NewBaseCacheProvider(prefix, ttl),
 		store:             make(map[string]cacheItem),
+		recency:           list.New(),
 	}
 }
 
+// NewArrayCacheDriverWithMemoryLimit creates an array cache driver that
+// evicts least recently used entries once its estimated size exceeds
+// maxBytes. Sizes come from estimateValueSize, which is approximate for
+// structs, maps and pointers, so treat the limit as a guide rather than a
+// hard cap on heap usage.
+func NewArrayCacheDriverWithMemoryLimit(prefix string, ttl time.Duration, maxBytes int64) *ArrayCacheDriver {
+	d := NewArrayCacheDriver(prefix, ttl)
+	d.maxBytes = maxBytes
+	return d
+}
+
 // Get retrieves a value from array cache
 func (d *ArrayCacheDriver) Get(key string) (interface{}, bool) {
 	fullKey := d.GetFullKey(key)
 
 	d.mutex.RLock()
-	defer d.mutex.RUnlock()
-
 	item, exists := d.store[fullKey]
+	d.mutex.RUnlock()
+
 	if !exists {
 		return nil, false
 	}
-
 	if time.Now().After(item.expiration) {
-		// Clean up expired item
-		d.mutex.RUnlock()
-		d.mutex.Lock()
-		delete(d.store, fullKey)
-		d.mutex.Unlock()
-		d.mutex.RLock()
+		d.removeExpired(fullKey)
 		return nil, false
 	}
-
+	if d.maxBytes > 0 {
+		d.touch(fullKey)
+	}
 	return item.value, true
 }
 
+// touch marks a key as the most recently used
+func (d *ArrayCacheDriver) touch(fullKey string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if item, exists := d.store[fullKey]; exists && item.element != nil {
+		d.recency.MoveToFront(item.element)
+	}
+}
+
+// Pull retrieves a value and deletes it under one lock, so concurrent pulls
+// of the same key never both receive it
+func (d *ArrayCacheDriver) Pull(key string) (interface{}, bool, error) {
+	fullKey := d.GetFullKey(key)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	item, exists := d.store[fullKey]
+	if !exists {
+		return nil, false, nil
+	}
+	d.removeItem(fullKey)
+	if time.Now().After(item.expiration) {
+		return nil, false, nil
+	}
+	return item.value, true, nil
+}
+
 // Set stores a value in array cache
 func (d *ArrayCacheDriver) Set(key string, value interface{}, ttl ...time.Duration) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	fullKey := d.GetFullKey(key)
-	duration := d.GetEffectiveTTL(ttl...)
+	return d.putItem(fullKey, newCacheItem(fullKey, value, d.GetEffectiveTTL(ttl...)))
+}
 
+// SetMany stores several values under one lock, so readers see all or none
+// of them. If any value is too large, none are stored.
+func (d *ArrayCacheDriver) SetMany(values map[string]interface{}, ttl ...time.Duration) error {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 
-	d.store[fullKey] = cacheItem{
+	// One roll of the jitter for the batch, so keys written together expire together
+	duration := d.GetEffectiveTTL(ttl...)
+	items := make(map[string]cacheItem, len(values))
+	for key, value := range values {
+		fullKey := d.GetFullKey(key)
+		item := newCacheItem(fullKey, value, duration)
+		if d.tooLarge(item) {
+			return fmt.Errorf("%w: %s", ErrCacheItemTooLarge, key)
+		}
+		items[fullKey] = item
+	}
+	for fullKey, item := range items {
+		d.putItem(fullKey, item)
+	}
+	return nil
+}
+
+// newCacheItem builds an item holding value, sized for the memory limit
+func newCacheItem(fullKey string, value interface{}, duration time.Duration) cacheItem {
+	return cacheItem{
 		value:      value,
 		expiration: time.Now().Add(duration),
+		size:       int64(len(fullKey)) + estimateValueSize(value),
+	}
+}
+
+// tooLarge reports whether an item alone exceeds the memory limit
+func (d *ArrayCacheDriver) tooLarge(item cacheItem) bool {
+	return d.maxBytes > 0 && item.size > d.maxBytes
+}
+
+// putItem stores an item, replacing any existing one, then evicts least
+// recently used items while over the memory limit; the write lock must be
+// held. An item too large to ever fit is refused, and the value it would
+// have replaced is dropped so a stale copy isn't served.
+func (d *ArrayCacheDriver) putItem(fullKey string, item cacheItem) error {
+	d.removeItem(fullKey)
+	if d.tooLarge(item) {
+		return fmt.Errorf("%w: %s", ErrCacheItemTooLarge, fullKey)
 	}
+
+	item.element = d.recency.PushFront(fullKey)
+	d.store[fullKey] = item
+	d.memoryBytes += item.size
+	d.evict()
 	return nil
 }
 
+// putLock stores a lock outside the recency list, so eviction can't release
+// it while its owner still holds it; the write lock must be held
+func (d *ArrayCacheDriver) putLock(fullKey string, item cacheItem) {
+	d.removeItem(fullKey)
+	d.store[fullKey] = item
+	d.memoryBytes += item.size
+	d.evict()
+}
+
+// evict removes least recently used items while over the memory limit; the
+// write lock must be held. Locks count towards the limit but are never evicted.
+func (d *ArrayCacheDriver) evict() {
+	for d.maxBytes > 0 && d.memoryBytes > d.maxBytes && d.recency.Len() > 0 {
+		d.removeItem(d.recency.Back().Value.(string))
+		d.evictions++
+	}
+}
+
 // Delete removes a value from array cache
 func (d *ArrayCacheDriver) Delete(key string) error {
 	fullKey := d.GetFullKey(key)
@@ -73,10 +195,31 @@ func (d *ArrayCacheDriver) Delete(key string) error {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 
-	delete(d.store, fullKey)
+	d.removeItem(fullKey)
 	return nil
 }
 
+// DeletePattern removes every key matching a glob pattern, e.g. "users:*",
+// returning how many were removed
+func (d *ArrayCacheDriver) DeletePattern(pattern string) (int64, error) {
+	fullPattern := d.GetFullKey(pattern)
+	if _, err := path.Match(fullPattern, ""); err != nil {
+		return 0, err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var deleted int64
+	for fullKey := range d.store {
+		if matched, _ := path.Match(fullPattern, fullKey); matched {
+			d.removeItem(fullKey)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
 // Has checks if a key exists in array cache
 func (d *ArrayCacheDriver) Has(key string) bool {
 	_, exists := d.Get(key)
@@ -89,9 +232,141 @@ func (d *ArrayCacheDriver) Flush() error {
 	defer d.mutex.Unlock()
 
 	d.store = make(map[string]cacheItem)
+	d.recency.Init()
+	d.memoryBytes = 0
+	return nil
+}
+
+// Lock attempts to acquire a lock, returning an owner token on success.
+// Locks are only visible within this process.
+func (d *ArrayCacheDriver) Lock(key string, ttl time.Duration) (string, bool, error) {
+	fullKey := d.GetFullKey(key)
+
+	owner, err := newLockOwner()
+	if err != nil {
+		return "", false, err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if item, exists := d.store[fullKey]; exists && time.Now().Before(item.expiration) {
+		return "", false, nil
+	}
+
+	d.putLock(fullKey, cacheItem{
+		value:      owner,
+		expiration: time.Now().Add(ttl),
+		size:       int64(len(fullKey) + len(owner)),
+	})
+	return owner, true, nil
+}
+
+// Unlock releases a lock if it is still held by owner
+func (d *ArrayCacheDriver) Unlock(key string, owner string) error {
+	fullKey := d.GetFullKey(key)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if item, exists := d.store[fullKey]; exists && item.value == owner {
+		d.removeItem(fullKey)
+	}
 	return nil
 }
 
+// IncrementWithTTL increments a counter that expires ttl after its first
+// increment, returning the new count and the time left until it resets
+func (d *ArrayCacheDriver) IncrementWithTTL(key string, ttl time.Duration) (int64, time.Duration, error) {
+	fullKey := d.GetFullKey(key)
+	now := time.Now()
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var current int64
+	expiration := now.Add(ttl)
+	if item, exists := d.store[fullKey]; exists && now.Before(item.expiration) {
+		switch v := item.value.(type) {
+		case int64:
+			current = v
+		case int:
+			current = int64(v)
+		default:
+			return 0, 0, fmt.Errorf("cache value for %s is not an integer", key)
+		}
+		expiration = item.expiration
+	}
+
+	current++
+	err := d.putItem(fullKey, cacheItem{
+		value:      current,
+		expiration: expiration,
+		size:       int64(len(fullKey)) + 8,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return current, expiration.Sub(now), nil
+}
+
+// EstimatedMemoryBytes returns an approximate footprint of all stored keys and values
+func (d *ArrayCacheDriver) EstimatedMemoryBytes() int64 {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	return d.memoryBytes
+}
+
+// removeExpired deletes a key if it is still expired once the write lock is
+// held, so a value stored after the expired one was read is kept
+func (d *ArrayCacheDriver) removeExpired(fullKey string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if item, exists := d.store[fullKey]; exists && time.Now().After(item.expiration) {
+		d.removeItem(fullKey)
+	}
+}
+
+// removeItem deletes a key and releases its size; the write lock must be held
+func (d *ArrayCacheDriver) removeItem(fullKey string) {
+	if item, exists := d.store[fullKey]; exists {
+		d.memoryBytes -= item.size
+		if item.element != nil {
+			d.recency.Remove(item.element)
+		}
+		delete(d.store, fullKey)
+	}
+}
+
+// estimateValueSize approximates the memory used by a cached value.
+// Strings and byte slices are measured directly, fixed-size scalars use
+// their width, and anything else falls back to its JSON-encoded length.
+func estimateValueSize(value interface{}) int64 {
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case string:
+		return int64(len(v))
+	case []byte:
+		return int64(len(v))
+	case bool, int8, uint8:
+		return 1
+	case int16, uint16:
+		return 2
+	case int32, uint32, float32:
+		return 4
+	case int, int64, uint, uint64, float64, uintptr:
+		return 8
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
+
 // GetStats returns cache statistics
 func (d *ArrayCacheDriver) GetStats() map[string]interface{} {
 	d.mutex.RLock()
@@ -110,8 +385,11 @@ func (d *ArrayCacheDriver) GetStats() map[string]interface{} {
 	}
 
 	return map[string]interface{}{
-		"total_items":   len(d.store),
-		"valid_items":   valid,
-		"expired_items": expired,
+		"total_items":            len(d.store),
+		"valid_items":            valid,
+		"expired_items":          expired,
+		"estimated_memory_bytes": d.memoryBytes,
+		"memory_limit_bytes":     d.maxBytes,
+		"evictions":              d.evictions,
 	}
 }
@@ -0,0 +1,244 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Each entry below is 10 bytes: a 3 byte full key and a 7 byte value
+const entryValue = "1234567"
+
+func TestArrayCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewArrayCacheDriverWithMemoryLimit("t:", time.Minute, 30)
+
+	for _, key := range []string{"a", "b", "c"} {
+		if err := cache.Set(key, entryValue); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := cache.EstimatedMemoryBytes(); got != 30 {
+		t.Fatalf("expected 30 bytes in use, got %d", got)
+	}
+
+	// Reading a makes b the least recently used
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("expected a to be cached")
+	}
+	if err := cache.Set("d", entryValue); err != nil {
+		t.Fatal(err)
+	}
+
+	if cache.Has("b") {
+		t.Fatal("expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c", "d"} {
+		if !cache.Has(key) {
+			t.Fatalf("expected %s to survive eviction", key)
+		}
+	}
+	stats := cache.GetStats()
+	if stats["evictions"] != int64(1) || stats["estimated_memory_bytes"] != int64(30) {
+		t.Fatalf("expected one eviction and 30 bytes in use, got %v", stats)
+	}
+}
+
+func TestArrayCacheNeverEvictsLocks(t *testing.T) {
+	cache := NewArrayCacheDriverWithMemoryLimit("t:", time.Minute, 40)
+
+	owner, acquired, err := cache.Lock("job", time.Minute)
+	if err != nil || !acquired {
+		t.Fatalf("expected to acquire the lock, got %v, %v", acquired, err)
+	}
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		if err := cache.Set(key, entryValue); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, acquired, _ := cache.Lock("job", time.Minute); acquired {
+		t.Fatal("expected the held lock to survive eviction pressure")
+	}
+	if cache.EstimatedMemoryBytes() > 40 {
+		t.Fatalf("expected values to be evicted to stay under the limit, got %d bytes", cache.EstimatedMemoryBytes())
+	}
+	if err := cache.Unlock("job", owner); err != nil {
+		t.Fatal(err)
+	}
+	if _, acquired, _ := cache.Lock("job", time.Minute); !acquired {
+		t.Fatal("expected the released lock to be free")
+	}
+}
+
+func TestArrayCacheRejectsItemsLargerThanLimit(t *testing.T) {
+	cache := NewArrayCacheDriverWithMemoryLimit("t:", time.Minute, 30)
+
+	if err := cache.Set("a", entryValue); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Set("a", make([]byte, 64)); !errors.Is(err, ErrCacheItemTooLarge) {
+		t.Fatalf("expected ErrCacheItemTooLarge, got %v", err)
+	}
+	if cache.Has("a") {
+		t.Fatal("expected the stale value to be dropped rather than served")
+	}
+	if got := cache.EstimatedMemoryBytes(); got != 0 {
+		t.Fatalf("expected nothing to be accounted for, got %d bytes", got)
+	}
+	if stats := cache.GetStats(); stats["evictions"] != int64(0) {
+		t.Fatalf("expected a refused item not to count as an eviction, got %v", stats)
+	}
+
+	err := cache.SetMany(map[string]interface{}{"b": entryValue, "c": make([]byte, 64)})
+	if !errors.Is(err, ErrCacheItemTooLarge) {
+		t.Fatalf("expected ErrCacheItemTooLarge, got %v", err)
+	}
+	if cache.Has("b") || cache.Has("c") {
+		t.Fatal("expected SetMany to store none of the values")
+	}
+}
+
+func TestArrayCacheBoundsMemoryOverManyEntries(t *testing.T) {
+	const entries, capacity = 10000, 1000
+	// Every entry is 10 bytes: a 7 byte full key and a 3 byte value
+	cache := NewArrayCacheDriverWithMemoryLimit("t:", time.Minute, capacity*10)
+	key := func(i int) string { return fmt.Sprintf("%05d", i) }
+
+	for i := 0; i < entries; i++ {
+		if err := cache.Set(key(i), "abc"); err != nil {
+			t.Fatal(err)
+		}
+		if used := cache.EstimatedMemoryBytes(); used > capacity*10 {
+			t.Fatalf("expected at most %d bytes after %d entries, got %d", capacity*10, i+1, used)
+		}
+	}
+
+	stats := cache.GetStats()
+	if stats["total_items"] != capacity || stats["evictions"] != int64(entries-capacity) {
+		t.Fatalf("expected %d items after %d evictions, got %v", capacity, entries-capacity, stats)
+	}
+	for i := 0; i < entries; i++ {
+		if want := i >= entries-capacity; cache.Has(key(i)) != want {
+			t.Fatalf("expected only the newest %d entries to be kept, got %s cached=%v", capacity, key(i), !want)
+		}
+	}
+
+	// Reading the oldest 100 survivors makes the next 100 the least recently used
+	for i := entries - capacity; i < entries-capacity+100; i++ {
+		cache.Get(key(i))
+	}
+	for i := entries; i < entries+100; i++ {
+		cache.Set(key(i), "abc")
+	}
+	for i := entries - capacity; i < entries-capacity+200; i++ {
+		if want := i < entries-capacity+100; cache.Has(key(i)) != want {
+			t.Fatalf("expected recently read entries to outlive unread ones, got %s cached=%v", key(i), !want)
+		}
+	}
+}
+
+func TestArrayCacheConcurrentReadsOfExpiringKeys(t *testing.T) {
+	cache := NewArrayCacheDriverWithMemoryLimit("t:", time.Minute, 1<<20)
+	keys := []string{"a", "b", "c", "d"}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for _, key := range keys {
+		// Writers keep replacing an already expired value with a live one,
+		// which a reader that found the expired value must not remove
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				cache.Set(key, entryValue, time.Nanosecond)
+				cache.Set(key, entryValue, time.Minute)
+				if !cache.Has(key) {
+					t.Errorf("expected the live value of %s to be kept", key)
+					return
+				}
+			}
+		}(key)
+
+		// Readers find the expired values and remove them
+		for r := 0; r < 4; r++ {
+			wg.Add(1)
+			go func(key string) {
+				defer wg.Done()
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					if value, ok := cache.Get(key); ok && value != entryValue {
+						t.Errorf("expected %q, got %v", entryValue, value)
+					}
+					cache.Has(key)
+				}
+			}(key)
+		}
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+
+	// Every removal must have released exactly the size it accounted for
+	for _, key := range keys {
+		cache.Set(key, entryValue, time.Minute)
+	}
+	if used := cache.EstimatedMemoryBytes(); used != int64(len(keys)*10) {
+		t.Fatalf("expected %d bytes in use, got %d", len(keys)*10, used)
+	}
+}
+
+func TestArrayCacheEstimatedMemoryTracksWrites(t *testing.T) {
+	cache := NewArrayCacheDriver("t:", time.Minute)
+
+	if got := cache.EstimatedMemoryBytes(); got != 0 {
+		t.Fatalf("expected an empty cache to use 0 bytes, got %d", got)
+	}
+
+	// Sizes grow with every entry: 10 bytes per string entry, a fixed width
+	// for scalars and the JSON length for anything structured
+	cache.Set("a", entryValue)
+	cache.Set("b", entryValue)
+	if got := cache.EstimatedMemoryBytes(); got != 20 {
+		t.Fatalf("expected 20 bytes after two entries, got %d", got)
+	}
+	cache.Set("n", int64(42))
+	cache.Set("m", map[string]int{"x": 1})
+	if got := cache.EstimatedMemoryBytes(); got != 20+(3+8)+(3+7) {
+		t.Fatalf("expected 41 bytes after adding a scalar and a map, got %d", got)
+	}
+
+	// Overwriting a key replaces its size rather than adding to it
+	cache.Set("a", "1")
+	if got := cache.EstimatedMemoryBytes(); got != 35 {
+		t.Fatalf("expected 35 bytes after overwriting a, got %d", got)
+	}
+
+	cache.Delete("a")
+	if got := cache.EstimatedMemoryBytes(); got != 31 {
+		t.Fatalf("expected 31 bytes after deleting a, got %d", got)
+	}
+	cache.Pull("b")
+	if got := cache.EstimatedMemoryBytes(); got != 21 {
+		t.Fatalf("expected 21 bytes after pulling b, got %d", got)
+	}
+	if stats := cache.GetStats(); stats["estimated_memory_bytes"] != int64(21) {
+		t.Fatalf("expected the stats to report 21 bytes, got %v", stats["estimated_memory_bytes"])
+	}
+
+	cache.Flush()
+	if got := cache.EstimatedMemoryBytes(); got != 0 {
+		t.Fatalf("expected a flushed cache to use 0 bytes, got %d", got)
+	}
+}
@@ -0,0 +1,198 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+)
+
+// Audit actions recorded by AuditableService
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// AuditStore persists and reads back audit log entries
+type AuditStore interface {
+	Record(entry AuditLog) error
+	ForRecord(table string, recordID uint) ([]AuditLog, error)
+	ByField(table string, field string, value interface{}) ([]AuditLog, error)
+}
+
+// AuditableService records an AuditLog entry for every create, update and
+// delete made through BaseService. The acting user is read from the context
+// passed to the WithContext variants; writes without one are logged anonymously.
+// Failing to record an entry is logged but never fails the write itself.
+type AuditableService[T any] struct {
+	*BaseService[T]
+	store AuditStore
+}
+
+// NewAuditableService creates a new auditable service
+func NewAuditableService[T any](repository RepositoryInterface, cache CacheInterface, store AuditStore, options *ServiceOptions) *AuditableService[T] {
+	return &AuditableService[T]{
+		BaseService: NewBaseService[T](repository, cache).WithOptions(options),
+		store:       store,
+	}
+}
+
+// Create creates an entity and records it in the audit log
+func (s *AuditableService[T]) Create(data map[string]interface{}) (T, error) {
+	return s.create(context.Background(), s.BaseService, data)
+}
+
+// CreateWithContext creates an entity with context
+func (s *AuditableService[T]) CreateWithContext(ctx context.Context, data map[string]interface{}) (T, error) {
+	return writeWithContext(ctx, s.BaseService, func(base *BaseService[T]) (T, error) { return s.create(ctx, base, data) })
+}
+
+// Update updates an entity and records its old and new values in the audit log
+func (s *AuditableService[T]) Update(id uint, data map[string]interface{}) (T, error) {
+	return s.update(context.Background(), s.BaseService, id, data)
+}
+
+// UpdateWithContext updates an entity with context
+func (s *AuditableService[T]) UpdateWithContext(ctx context.Context, id uint, data map[string]interface{}) (T, error) {
+	return writeWithContext(ctx, s.BaseService, func(base *BaseService[T]) (T, error) { return s.update(ctx, base, id, data) })
+}
+
+// Delete deletes an entity and records its last values in the audit log
+func (s *AuditableService[T]) Delete(id uint) error {
+	return s.delete(context.Background(), s.BaseService, id)
+}
+
+// DeleteWithContext deletes an entity with context
+func (s *AuditableService[T]) DeleteWithContext(ctx context.Context, id uint) error {
+	_, err := writeWithContext(ctx, s.BaseService, func(base *BaseService[T]) (struct{}, error) {
+		return struct{}{}, s.delete(ctx, base, id)
+	})
+	return err
+}
+
+// GetAuditLog returns the audit log of an entity, oldest entry first
+func (s *AuditableService[T]) GetAuditLog(id uint) ([]AuditLog, error) {
+	return s.store.ForRecord(s.auditTable(), id)
+}
+
+// GetAuditLogWithContext returns the audit log of an entity with context
+func (s *AuditableService[T]) GetAuditLogWithContext(ctx context.Context, id uint) ([]AuditLog, error) {
+	return withContext(ctx, func() ([]AuditLog, error) { return s.GetAuditLog(id) })
+}
+
+// GetAuditLogByField returns this service's audit entries matching a field, oldest first
+func (s *AuditableService[T]) GetAuditLogByField(field string, value interface{}) ([]AuditLog, error) {
+	return s.store.ByField(s.auditTable(), field, value)
+}
+
+// GetAuditLogByFieldWithContext returns audit entries matching a field with context
+func (s *AuditableService[T]) GetAuditLogByFieldWithContext(ctx context.Context, field string, value interface{}) ([]AuditLog, error) {
+	return withContext(ctx, func() ([]AuditLog, error) { return s.GetAuditLogByField(field, value) })
+}
+
+// create creates an entity through base and records the values it was created with
+func (s *AuditableService[T]) create(ctx context.Context, base *BaseService[T], data map[string]interface{}) (T, error) {
+	result, err := base.Create(data)
+	if err != nil {
+		return result, err
+	}
+
+	if model, ok := any(result).(ModelInterface); ok {
+		s.record(ctx, AuditActionCreate, model.GetID(), nil, s.auditValues(result))
+	}
+	return result, nil
+}
+
+// update snapshots an entity, updates it through base and records both versions
+func (s *AuditableService[T]) update(ctx context.Context, base *BaseService[T], id uint, data map[string]interface{}) (T, error) {
+	var result T
+	existing, err := base.repository.Find(id)
+	if err != nil {
+		return result, err
+	}
+	// Snapshot before the update, which may fill the very same instance
+	oldValues := s.auditValues(existing)
+
+	result, err = base.Update(id, data)
+	if err != nil {
+		return result, err
+	}
+
+	s.record(ctx, AuditActionUpdate, id, oldValues, s.auditValues(result))
+	return result, nil
+}
+
+// delete deletes an entity through base and records the values it had
+func (s *AuditableService[T]) delete(ctx context.Context, base *BaseService[T], id uint) error {
+	model, err := base.repository.Find(id)
+	if err != nil {
+		return err
+	}
+	oldValues := s.auditValues(model)
+
+	if err := base.repository.Delete(model); err != nil {
+		return err
+	}
+
+	s.record(ctx, AuditActionDelete, id, oldValues, nil)
+	return nil
+}
+
+// record stores an audit entry attributed to the user in ctx, if any
+func (s *AuditableService[T]) record(ctx context.Context, action string, recordID uint, oldValues, newValues map[string]interface{}) {
+	if s.store == nil {
+		return
+	}
+
+	entry := AuditLog{
+		Action:    action,
+		Table:     s.auditTable(),
+		RecordID:  recordID,
+		OldValues: oldValues,
+		NewValues: newValues,
+	}
+	if userID, ok := UserIDFromContext(ctx); ok {
+		entry.UserID = &userID
+	}
+
+	if err := s.store.Record(entry); err != nil {
+		log.Printf("Failed to record %s audit entry for %s %d: %v", action, entry.Table, recordID, err)
+	}
+}
+
+// auditValues converts an entity to a field map, limited to the configured
+// AuditFields when any are set
+func (s *AuditableService[T]) auditValues(entity interface{}) map[string]interface{} {
+	encoded, err := json.Marshal(entity)
+	if err != nil {
+		return nil
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(encoded, &values); err != nil {
+		return nil
+	}
+
+	if s.options == nil || len(s.options.AuditFields) == 0 {
+		return values
+	}
+	filtered := make(map[string]interface{}, len(s.options.AuditFields))
+	for _, field := range s.options.AuditFields {
+		if value, ok := values[field]; ok {
+			filtered[field] = value
+		}
+	}
+	return filtered
+}
+
+// auditTable returns the table name audit entries are filed under
+func (s *AuditableService[T]) auditTable() string {
+	if model, err := s.newModel(); err == nil {
+		if table := model.GetTableName(); table != "" {
+			return table
+		}
+	}
+	return s.typeName()
+}
+
+// Ensure AuditableService satisfies the interface it implements
+var _ AuditableServiceInterface[ModelInterface] = (*AuditableService[ModelInterface])(nil)
@@ -0,0 +1,153 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// memoryAuditStore keeps audit entries in the order they were recorded
+type memoryAuditStore struct {
+	mutex   sync.Mutex
+	entries []AuditLog
+	err     error
+}
+
+func (s *memoryAuditStore) Record(entry AuditLog) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	entry.ID = uint(len(s.entries) + 1)
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *memoryAuditStore) ForRecord(table string, recordID uint) ([]AuditLog, error) {
+	return s.filter(func(entry AuditLog) bool { return entry.Table == table && entry.RecordID == recordID }), nil
+}
+
+func (s *memoryAuditStore) ByField(table string, field string, value interface{}) ([]AuditLog, error) {
+	if field != "action" {
+		return nil, errors.New("cannot filter audit log by field " + field)
+	}
+	return s.filter(func(entry AuditLog) bool { return entry.Table == table && entry.Action == value }), nil
+}
+
+func (s *memoryAuditStore) filter(match func(AuditLog) bool) []AuditLog {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var entries []AuditLog
+	for _, entry := range s.entries {
+		if match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// entryFields returns the field map an entry holds for one side of a change
+func entryFields(values interface{}) map[string]interface{} {
+	fields, _ := values.(map[string]interface{})
+	return fields
+}
+
+func newAuditableTestService(store *memoryStore, audit AuditStore) *AuditableService[*testItem] {
+	return NewAuditableService[*testItem](&memoryRepository{store: store}, nil, audit, &ServiceOptions{EnableAudit: true})
+}
+
+func TestAuditableServiceRecordsOldAndNewValues(t *testing.T) {
+	audit := &memoryAuditStore{}
+	service := newAuditableTestService(newMemoryStore(), audit)
+	ctx := WithUserID(context.Background(), 7)
+
+	item, err := service.CreateWithContext(ctx, map[string]interface{}{"name": "draft"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.UpdateWithContext(ctx, item.ID, map[string]interface{}{"name": "final"}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := service.GetAuditLog(item.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected a create and an update entry, got %+v", entries)
+	}
+
+	created, updated := entries[0], entries[1]
+	if created.Action != AuditActionCreate || len(entryFields(created.OldValues)) != 0 {
+		t.Fatalf("expected a create entry without old values, got %+v", created)
+	}
+	if updated.Action != AuditActionUpdate || updated.Table != "test_items" || updated.RecordID != item.ID {
+		t.Fatalf("expected an update of test_items %d, got %+v", item.ID, updated)
+	}
+	wantOld := map[string]interface{}{"id": float64(item.ID), "name": "draft"}
+	wantNew := map[string]interface{}{"id": float64(item.ID), "name": "final"}
+	if !reflect.DeepEqual(updated.OldValues, wantOld) || !reflect.DeepEqual(updated.NewValues, wantNew) {
+		t.Fatalf("expected %v => %v, got %v => %v", wantOld, wantNew, updated.OldValues, updated.NewValues)
+	}
+	if updated.UserID == nil || *updated.UserID != 7 {
+		t.Fatalf("expected the update to be attributed to user 7, got %v", updated.UserID)
+	}
+}
+
+func TestAuditableServiceRecordsDeletes(t *testing.T) {
+	audit := &memoryAuditStore{}
+	store := newMemoryStore("keep", "remove")
+	service := newAuditableTestService(store, audit)
+
+	// Without a user in the context the entry is anonymous
+	if err := service.Delete(2); err != nil {
+		t.Fatal(err)
+	}
+	if store.has(2) {
+		t.Fatal("expected the row to be deleted")
+	}
+
+	deletes, err := service.GetAuditLogByField("action", AuditActionDelete)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deletes) != 1 {
+		t.Fatalf("expected one delete entry, got %+v", deletes)
+	}
+	entry := deletes[0]
+	if entry.RecordID != 2 || entry.UserID != nil || len(entryFields(entry.NewValues)) != 0 {
+		t.Fatalf("expected an anonymous delete of record 2, got %+v", entry)
+	}
+	if want := map[string]interface{}{"id": float64(2), "name": "remove"}; !reflect.DeepEqual(entry.OldValues, want) {
+		t.Fatalf("expected the last values %v, got %v", want, entry.OldValues)
+	}
+}
+
+func TestAuditableServiceLimitsValuesToAuditFields(t *testing.T) {
+	audit := &memoryAuditStore{}
+	service := NewAuditableService[*testItem](&memoryRepository{store: newMemoryStore("draft")}, nil, audit,
+		&ServiceOptions{EnableAudit: true, AuditFields: []string{"name"}})
+
+	if _, err := service.Update(1, map[string]interface{}{"name": "final"}); err != nil {
+		t.Fatal(err)
+	}
+	if entry := audit.entries[0]; !reflect.DeepEqual(entry.NewValues, map[string]interface{}{"name": "final"}) {
+		t.Fatalf("expected only the audited field, got %v", entry.NewValues)
+	}
+}
+
+func TestAuditableServiceWritesWhenRecordingFails(t *testing.T) {
+	audit := &memoryAuditStore{err: errors.New("audit table missing")}
+	store := newMemoryStore("draft")
+	service := newAuditableTestService(store, audit)
+
+	if _, err := service.Update(1, map[string]interface{}{"name": "final"}); err != nil {
+		t.Fatalf("expected the update to succeed without an audit entry, got %v", err)
+	}
+	if item, _ := service.FindByID(1); item.Name != "final" {
+		t.Fatalf("expected the update to be saved, got %q", item.Name)
+	}
+}
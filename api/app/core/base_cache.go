@@ -1,6 +1,11 @@
 package core
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	mathrand "math/rand"
 	"time"
 )
 
@@ -17,6 +22,7 @@ type CacheInterface interface {
 type BaseCacheProvider struct {
 	prefix string
 	ttl    time.Duration
+	jitter float64
 }
 
 // NewBaseCacheProvider creates a new base cache provider
@@ -42,10 +48,44 @@ func (b *BaseCacheProvider) GetFullKey(key string) string {
 	return b.prefix + key
 }
 
-// GetEffectiveTTL returns the effective TTL (default or provided)
+// SetTTLJitter enables random TTL jitter of up to ±percent (e.g. 10 for ±10%)
+// so keys written together don't all expire at once. Zero disables it. The
+// percent must be below 100, or a jittered TTL could reach zero, which some
+// drivers treat as "never expire".
+func (b *BaseCacheProvider) SetTTLJitter(percent float64) error {
+	if math.IsNaN(percent) || percent < 0 || percent >= 100 {
+		return fmt.Errorf("ttl jitter must be at least 0 and below 100 percent, got %v", percent)
+	}
+	b.jitter = percent / 100
+	return nil
+}
+
+// GetEffectiveTTL returns the effective TTL (default or provided) with any configured jitter applied
 func (b *BaseCacheProvider) GetEffectiveTTL(ttl ...time.Duration) time.Duration {
+	duration := b.ttl
 	if len(ttl) > 0 {
-		return ttl[0]
+		duration = ttl[0]
 	}
-	return b.ttl
+	return b.applyJitter(duration)
+}
+
+// applyJitter spreads a TTL randomly within the configured jitter window
+func (b *BaseCacheProvider) applyJitter(ttl time.Duration) time.Duration {
+	if b.jitter == 0 || ttl <= 0 {
+		return ttl
+	}
+	offset := (mathrand.Float64()*2 - 1) * b.jitter * float64(ttl)
+	if jittered := ttl + time.Duration(offset); jittered > 0 {
+		return jittered
+	}
+	return ttl
+}
+
+// newLockOwner generates a random token identifying the holder of a lock
+func newLockOwner() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
 }
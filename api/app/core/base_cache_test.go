@@ -0,0 +1,65 @@
+package core
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSetTTLJitterRejectsOutOfRangePercent(t *testing.T) {
+	cache := NewBaseCacheProvider("t:", time.Minute)
+	if err := cache.SetTTLJitter(10); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, percent := range []float64{-1, 100, 150, math.NaN()} {
+		if err := cache.SetTTLJitter(percent); err == nil {
+			t.Fatalf("expected %v to be rejected", percent)
+		}
+	}
+	if cache.jitter != 0.1 {
+		t.Fatalf("expected a rejected value to keep the previous jitter, got %v", cache.jitter)
+	}
+}
+
+func TestJitteredTTLStaysPositive(t *testing.T) {
+	cache := NewBaseCacheProvider("t:", time.Minute)
+	if err := cache.SetTTLJitter(99.9); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		for _, ttl := range []time.Duration{time.Nanosecond, time.Second} {
+			if got := cache.GetEffectiveTTL(ttl); got <= 0 || got >= 2*ttl {
+				t.Fatalf("expected a jittered TTL within (0, %v), got %v", 2*ttl, got)
+			}
+		}
+	}
+}
+
+func TestSetManyRollsJitterOncePerBatch(t *testing.T) {
+	cache := NewArrayCacheDriver("t:", time.Hour)
+	if err := cache.SetTTLJitter(50); err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]interface{}{}
+	for _, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		values[key] = key
+	}
+	if err := cache.SetMany(values); err != nil {
+		t.Fatal(err)
+	}
+
+	var expiration time.Time
+	for key := range values {
+		item := cache.store[cache.GetFullKey(key)]
+		if expiration.IsZero() {
+			expiration = item.expiration
+		}
+		// Items are stamped a moment apart, so allow for the time between them
+		if diff := item.expiration.Sub(expiration); diff < -time.Second || diff > time.Second {
+			t.Fatalf("expected keys written together to expire together, %s is off by %v", key, diff)
+		}
+	}
+}
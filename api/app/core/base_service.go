@@ -2,14 +2,24 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+
+	"gorm.io/gorm"
 )
 
-// BaseService provides a generic implementation of BaseServiceInterface
+// fillable is implemented by models that can be populated from a data map
+type fillable interface {
+	Fill(data map[string]interface{})
+}
+
+// BaseService provides a generic implementation of BaseServiceInterface on top
+// of a RepositoryInterface. Models built by Create must implement Fill.
 type BaseService[T any] struct {
 	repository RepositoryInterface
 	cache      CacheInterface
+	options    *ServiceOptions
 }
 
 // NewBaseService creates a new base service
@@ -17,164 +27,443 @@ func NewBaseService[T any](repository RepositoryInterface, cache CacheInterface)
 	return &BaseService[T]{
 		repository: repository,
 		cache:      cache,
+		options:    &ServiceOptions{},
+	}
+}
+
+// WithOptions sets the service options
+func (s *BaseService[T]) WithOptions(options *ServiceOptions) *BaseService[T] {
+	if options != nil {
+		s.options = options
 	}
+	return s
 }
 
 // Create creates a new entity
 func (s *BaseService[T]) Create(data map[string]interface{}) (T, error) {
-	// This would need to be implemented by specific services
-	// as the repository interface doesn't support generic types
 	var result T
-	return result, fmt.Errorf("Create method not implemented in base service")
+	model, err := s.newModel()
+	if err != nil {
+		return result, err
+	}
+
+	if err := s.fill(model, data); err != nil {
+		return result, err
+	}
+	if err := s.repository.Create(model); err != nil {
+		return result, err
+	}
+	return s.cast(model)
 }
 
 // CreateWithContext creates a new entity with context
 func (s *BaseService[T]) CreateWithContext(ctx context.Context, data map[string]interface{}) (T, error) {
-	return s.Create(data) // Repository doesn't support context yet
+	return writeWithContext(ctx, s, func(scoped *BaseService[T]) (T, error) { return scoped.Create(data) })
 }
 
 // FindByID finds an entity by ID
 func (s *BaseService[T]) FindByID(id uint) (T, error) {
-	// This would need to be implemented by specific services
-	// as the repository interface doesn't support generic types
 	var result T
-	return result, fmt.Errorf("FindByID method not implemented in base service")
+	model, err := s.repository.Find(id)
+	if err != nil {
+		return result, err
+	}
+	return s.cast(model)
 }
 
 // FindByIDWithContext finds an entity by ID with context
 func (s *BaseService[T]) FindByIDWithContext(ctx context.Context, id uint) (T, error) {
-	return s.FindByID(id) // Repository doesn't support context yet
+	return readWithContext(ctx, s, func(scoped *BaseService[T]) (T, error) { return scoped.FindByID(id) })
 }
 
 // FindByField finds an entity by field
 func (s *BaseService[T]) FindByField(field string, value interface{}) (T, error) {
-	// This would need to be implemented by specific services
-	// as the repository interface doesn't support generic types
 	var result T
-	return result, fmt.Errorf("FindByField method not implemented in base service")
+	model, err := s.repository.FindBy(field, value)
+	if err != nil {
+		return result, err
+	}
+	return s.cast(model)
 }
 
 // FindByFieldWithContext finds an entity by field with context
 func (s *BaseService[T]) FindByFieldWithContext(ctx context.Context, field string, value interface{}) (T, error) {
-	return s.FindByField(field, value) // Repository doesn't support context yet
+	return readWithContext(ctx, s, func(scoped *BaseService[T]) (T, error) { return scoped.FindByField(field, value) })
 }
 
 // All gets all entities
 func (s *BaseService[T]) All() ([]T, error) {
-	// This would need to be implemented by specific services
-	// as the repository interface doesn't support generic types
-	return nil, fmt.Errorf("All method not implemented in base service")
+	models, err := s.repository.All()
+	if err != nil {
+		return nil, err
+	}
+	return s.castAll(models)
 }
 
 // AllWithContext gets all entities with context
 func (s *BaseService[T]) AllWithContext(ctx context.Context) ([]T, error) {
-	return s.All() // Repository doesn't support context yet
+	return readWithContext(ctx, s, func(scoped *BaseService[T]) ([]T, error) { return scoped.All() })
 }
 
-// Paginate gets paginated entities
+// Paginate gets paginated entities. The repository must implement
+// PagingRepository so the page is cut in the database.
 func (s *BaseService[T]) Paginate(page, perPage int) ([]T, int64, error) {
-	// This would need to be implemented by specific services
-	// as the repository interface doesn't support generic types
-	return nil, 0, fmt.Errorf("Paginate method not implemented in base service")
+	if page < 1 || perPage < 1 {
+		return nil, 0, fmt.Errorf("invalid page %d or per page %d", page, perPage)
+	}
+	return s.paginate(s.repository, page, perPage)
 }
 
 // PaginateWithContext gets paginated entities with context
 func (s *BaseService[T]) PaginateWithContext(ctx context.Context, page, perPage int) ([]T, int64, error) {
-	return s.Paginate(page, perPage) // Repository doesn't support context yet
+	type result struct {
+		items []T
+		total int64
+	}
+	r, err := readWithContext(ctx, s, func(scoped *BaseService[T]) (result, error) {
+		items, total, err := scoped.Paginate(page, perPage)
+		return result{items, total}, err
+	})
+	return r.items, r.total, err
+}
+
+// PaginateCursor gets the entities after a cursor along with the next cursor
+func (s *BaseService[T]) PaginateCursor(cursor string, limit int) ([]T, string, error) {
+	afterID, err := DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if limit <= 0 {
+		return nil, "", fmt.Errorf("cursor page limit must be positive, got %d", limit)
+	}
+
+	pager, err := pagingRepository(s.repository)
+	if err != nil {
+		return nil, "", err
+	}
+	// One extra row tells whether there is a next page
+	models, err := pager.After(afterID, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(models) > limit {
+		models = models[:limit]
+		nextCursor = EncodeCursor(models[limit-1].GetID())
+	}
+
+	items, err := s.castAll(models)
+	return items, nextCursor, err
+}
+
+// PaginateCursorWithContext gets the entities after a cursor with context
+func (s *BaseService[T]) PaginateCursorWithContext(ctx context.Context, cursor string, limit int) ([]T, string, error) {
+	type result struct {
+		items      []T
+		nextCursor string
+	}
+	r, err := readWithContext(ctx, s, func(scoped *BaseService[T]) (result, error) {
+		items, nextCursor, err := scoped.PaginateCursor(cursor, limit)
+		return result{items, nextCursor}, err
+	})
+	return r.items, r.nextCursor, err
 }
 
 // Update updates an entity
 func (s *BaseService[T]) Update(id uint, data map[string]interface{}) (T, error) {
-	// This would need to be implemented by specific services
-	// as the repository interface doesn't support generic types
 	var result T
-	return result, fmt.Errorf("Update method not implemented in base service")
+	model, err := s.repository.Find(id)
+	if err != nil {
+		return result, err
+	}
+
+	if err := s.fill(model, data); err != nil {
+		return result, err
+	}
+	if err := s.repository.Update(model); err != nil {
+		return result, err
+	}
+	return s.cast(model)
 }
 
 // UpdateWithContext updates an entity with context
 func (s *BaseService[T]) UpdateWithContext(ctx context.Context, id uint, data map[string]interface{}) (T, error) {
-	return s.Update(id, data) // Repository doesn't support context yet
+	return writeWithContext(ctx, s, func(scoped *BaseService[T]) (T, error) { return scoped.Update(id, data) })
 }
 
 // UpdateOrCreate updates or creates an entity
 func (s *BaseService[T]) UpdateOrCreate(conditions map[string]interface{}, data map[string]interface{}) (T, error) {
-	// This would need to be implemented by specific services
-	// as the repository interface doesn't support generic types
-	var result T
-	return result, fmt.Errorf("UpdateOrCreate method not implemented in base service")
+	model, err := s.repository.Where(conditions).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		merged := make(map[string]interface{}, len(conditions)+len(data))
+		for key, value := range conditions {
+			merged[key] = value
+		}
+		for key, value := range data {
+			merged[key] = value
+		}
+		return s.Create(merged)
+	}
+	if err != nil {
+		var result T
+		return result, err
+	}
+	return s.Update(model.GetID(), data)
 }
 
 // UpdateOrCreateWithContext updates or creates an entity with context
 func (s *BaseService[T]) UpdateOrCreateWithContext(ctx context.Context, conditions map[string]interface{}, data map[string]interface{}) (T, error) {
-	return s.UpdateOrCreate(conditions, data) // Repository doesn't support context yet
+	return writeWithContext(ctx, s, func(scoped *BaseService[T]) (T, error) { return scoped.UpdateOrCreate(conditions, data) })
 }
 
 // Delete deletes an entity
 func (s *BaseService[T]) Delete(id uint) error {
-	// This would need to be implemented by specific services
-	// as the repository interface doesn't support generic types
-	return fmt.Errorf("Delete method not implemented in base service")
+	model, err := s.repository.Find(id)
+	if err != nil {
+		return err
+	}
+	return s.repository.Delete(model)
 }
 
 // DeleteWithContext deletes an entity with context
 func (s *BaseService[T]) DeleteWithContext(ctx context.Context, id uint) error {
-	return s.Delete(id) // Repository doesn't support context yet
+	_, err := writeWithContext(ctx, s, func(scoped *BaseService[T]) (struct{}, error) { return struct{}{}, scoped.Delete(id) })
+	return err
 }
 
 // DeleteWhere deletes entities by conditions
 func (s *BaseService[T]) DeleteWhere(conditions map[string]interface{}) error {
-	// This would need to be implemented by specific services
-	// as the repository interface doesn't support generic types
-	return fmt.Errorf("DeleteWhere method not implemented in base service")
+	models, err := s.repository.Where(conditions).Get()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, model := range models {
+		if err := s.repository.Delete(model); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // DeleteWhereWithContext deletes entities by conditions with context
 func (s *BaseService[T]) DeleteWhereWithContext(ctx context.Context, conditions map[string]interface{}) error {
-	return s.DeleteWhere(conditions) // Repository doesn't support context yet
+	_, err := writeWithContext(ctx, s, func(scoped *BaseService[T]) (struct{}, error) {
+		return struct{}{}, scoped.DeleteWhere(conditions)
+	})
+	return err
 }
 
 // Exists checks if an entity exists
 func (s *BaseService[T]) Exists(id uint) (bool, error) {
-	// This would need to be implemented by specific services
-	// as the repository interface doesn't support generic types
-	return false, fmt.Errorf("Exists method not implemented in base service")
+	_, err := s.repository.Find(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	return err == nil, err
 }
 
 // ExistsWithContext checks if an entity exists with context
 func (s *BaseService[T]) ExistsWithContext(ctx context.Context, id uint) (bool, error) {
-	return s.Exists(id) // Repository doesn't support context yet
+	return readWithContext(ctx, s, func(scoped *BaseService[T]) (bool, error) { return scoped.Exists(id) })
 }
 
-// Count counts all entities
+// Count counts all entities in the database
 func (s *BaseService[T]) Count() (int64, error) {
-	// This would need to be implemented by specific services
-	// as the repository interface doesn't support generic types
-	return 0, fmt.Errorf("Count method not implemented in base service")
+	pager, err := pagingRepository(s.repository)
+	if err != nil {
+		return 0, err
+	}
+	return pager.Count()
 }
 
 // CountWithContext counts all entities with context
 func (s *BaseService[T]) CountWithContext(ctx context.Context) (int64, error) {
-	return s.Count() // Repository doesn't support context yet
+	return readWithContext(ctx, s, func(scoped *BaseService[T]) (int64, error) { return scoped.Count() })
 }
 
-// CountWhere counts entities by conditions
+// CountWhere counts entities by conditions in the database
 func (s *BaseService[T]) CountWhere(conditions map[string]interface{}) (int64, error) {
-	// This would need to be implemented by specific services
-	// as the repository interface doesn't support generic types
-	return 0, fmt.Errorf("CountWhere method not implemented in base service")
+	pager, err := pagingRepository(s.repository.Where(conditions))
+	if err != nil {
+		return 0, err
+	}
+	return pager.Count()
 }
 
 // CountWhereWithContext counts entities by conditions with context
 func (s *BaseService[T]) CountWhereWithContext(ctx context.Context, conditions map[string]interface{}) (int64, error) {
-	return s.CountWhere(conditions) // Repository doesn't support context yet
+	return readWithContext(ctx, s, func(scoped *BaseService[T]) (int64, error) { return scoped.CountWhere(conditions) })
 }
 
 // GetCacheKey generates a cache key for an entity
 func (s *BaseService[T]) GetCacheKey(id uint) string {
-	return fmt.Sprintf("%s:%d", reflect.TypeOf(*new(T)).Name(), id)
+	return fmt.Sprintf("%s:%d", s.typeName(), id)
 }
 
 // GetCacheKeyByField generates a cache key for an entity by field
 func (s *BaseService[T]) GetCacheKeyByField(field string, value interface{}) string {
-	return fmt.Sprintf("%s:%s:%v", reflect.TypeOf(*new(T)).Name(), field, value)
+	return fmt.Sprintf("%s:%s:%v", s.typeName(), field, value)
+}
+
+// typeName returns the name of T, looking through pointer types
+func (s *BaseService[T]) typeName() string {
+	modelType := reflect.TypeOf((*T)(nil)).Elem()
+	for modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	return modelType.Name()
+}
+
+// newModel creates an empty model of type T
+func (s *BaseService[T]) newModel() (ModelInterface, error) {
+	modelType := reflect.TypeOf((*T)(nil)).Elem()
+
+	var value interface{}
+	if modelType.Kind() == reflect.Ptr {
+		value = reflect.New(modelType.Elem()).Interface()
+	} else {
+		value = reflect.New(modelType).Elem().Interface()
+	}
+
+	model, ok := value.(ModelInterface)
+	if !ok {
+		return nil, fmt.Errorf("%s does not implement ModelInterface", modelType)
+	}
+	return model, nil
+}
+
+// fill populates a model from a data map
+func (s *BaseService[T]) fill(model ModelInterface, data map[string]interface{}) error {
+	target, ok := model.(fillable)
+	if !ok {
+		return fmt.Errorf("%T does not implement Fill", model)
+	}
+	target.Fill(data)
+	return nil
+}
+
+// cast converts a repository model to T
+func (s *BaseService[T]) cast(model ModelInterface) (T, error) {
+	result, ok := model.(T)
+	if !ok {
+		return result, fmt.Errorf("repository returned %T, expected %s", model, reflect.TypeOf((*T)(nil)).Elem())
+	}
+	return result, nil
+}
+
+// paginate counts the models matching repository and loads one page of them
+func (s *BaseService[T]) paginate(repository RepositoryInterface, page, perPage int) ([]T, int64, error) {
+	pager, err := pagingRepository(repository)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := pager.Count()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * perPage
+	if int64(offset) >= total {
+		return []T{}, total, nil
+	}
+	models, err := pager.Page(offset, perPage)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items, err := s.castAll(models)
+	return items, total, err
+}
+
+// pagingRepository returns repository as a PagingRepository, refusing to
+// fall back to loading the whole table
+func pagingRepository(repository RepositoryInterface) (PagingRepository, error) {
+	pager, ok := repository.(PagingRepository)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrPagingUnsupported, repository)
+	}
+	return pager, nil
+}
+
+// castAll converts repository models to T
+func (s *BaseService[T]) castAll(models []ModelInterface) ([]T, error) {
+	results := make([]T, 0, len(models))
+	for _, model := range models {
+		result, err := s.cast(model)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// inContext returns a copy of the service whose repository queries are bound
+// to ctx, and false when the repository doesn't implement ContextRepository
+func (s *BaseService[T]) inContext(ctx context.Context) (*BaseService[T], bool) {
+	repository, ok := s.repository.(ContextRepository)
+	if !ok {
+		return s, false
+	}
+	scoped := *s
+	scoped.repository = repository.WithContext(ctx)
+	return &scoped, true
+}
+
+// readWithContext runs a read against s with its queries bound to ctx. A
+// repository without context support is read in the background and abandoned
+// when ctx is done, which is harmless for a read.
+func readWithContext[T any, R any](ctx context.Context, s *BaseService[T], fn func(*BaseService[T]) (R, error)) (R, error) {
+	scoped, ok := s.inContext(ctx)
+	if !ok {
+		return withContext(ctx, func() (R, error) { return fn(s) })
+	}
+	if err := ctx.Err(); err != nil {
+		var zero R
+		return zero, err
+	}
+	return fn(scoped)
+}
+
+// writeWithContext runs a write against s with its queries bound to ctx, so
+// cancelling ctx aborts the write rather than abandoning it. A repository
+// without context support only has ctx checked before the write starts; the
+// write then runs to completion and its real outcome is returned.
+func writeWithContext[T any, R any](ctx context.Context, s *BaseService[T], fn func(*BaseService[T]) (R, error)) (R, error) {
+	if err := ctx.Err(); err != nil {
+		var zero R
+		return zero, err
+	}
+	scoped, _ := s.inContext(ctx)
+	return fn(scoped)
+}
+
+// withContext runs fn and returns early with ctx's error if ctx is done first.
+// fn keeps running in the background after a timeout, so it must only be used
+// for work that is safe to abandon, such as reads and cache lookups.
+func withContext[R any](ctx context.Context, fn func() (R, error)) (R, error) {
+	var zero R
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	type outcome struct {
+		value R
+		err   error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		value, err := fn()
+		done <- outcome{value, err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.value, result.err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
 }
@@ -0,0 +1,471 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// testItem is a minimal model for service tests
+type testItem struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+func (m *testItem) GetID() uint              { return m.ID }
+func (m *testItem) GetTableName() string     { return "test_items" }
+func (m *testItem) GetCreatedAt() time.Time  { return time.Time{} }
+func (m *testItem) GetUpdatedAt() time.Time  { return time.Time{} }
+func (m *testItem) GetDeletedAt() *time.Time { return nil }
+
+func (m *testItem) Fill(data map[string]interface{}) {
+	if name, ok := data["name"].(string); ok {
+		m.Name = name
+	}
+}
+
+// memoryStore holds the rows shared by every scoped memoryRepository
+type memoryStore struct {
+	mutex  sync.Mutex
+	rows   map[uint]*testItem
+	nextID uint
+	calls  []string
+	// delay makes every call slow, to exercise context handling
+	delay time.Duration
+}
+
+func newMemoryStore(names ...string) *memoryStore {
+	store := &memoryStore{rows: make(map[uint]*testItem)}
+	for _, name := range names {
+		store.insert(name)
+	}
+	return store
+}
+
+func (s *memoryStore) insert(name string) uint {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.nextID++
+	s.rows[s.nextID] = &testItem{ID: s.nextID, Name: name}
+	return s.nextID
+}
+
+func (s *memoryStore) has(id uint) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, ok := s.rows[id]
+	return ok
+}
+
+func (s *memoryStore) called(name string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, call := range s.calls {
+		if call == name {
+			return true
+		}
+	}
+	return false
+}
+
+// memoryRepository is a RepositoryInterface over a memoryStore. It supports
+// neither paging nor contexts; pagedRepository below adds both.
+type memoryRepository struct {
+	store *memoryStore
+	name  string
+	ctx   context.Context
+}
+
+// wait simulates a slow query, giving up if the repository's context is done
+func (r *memoryRepository) wait(call string) error {
+	r.store.mutex.Lock()
+	r.store.calls = append(r.store.calls, call)
+	delay := r.store.delay
+	r.store.mutex.Unlock()
+
+	if r.ctx == nil {
+		time.Sleep(delay)
+		return nil
+	}
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-r.ctx.Done():
+		return r.ctx.Err()
+	}
+}
+
+// matching returns the rows matching the name filter, ordered by ID
+func (r *memoryRepository) matching() []*testItem {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+	rows := make([]*testItem, 0, len(r.store.rows))
+	for _, row := range r.store.rows {
+		if r.name == "" || row.Name == r.name {
+			copied := *row
+			rows = append(rows, &copied)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+	return rows
+}
+
+func (r *memoryRepository) Find(id uint) (ModelInterface, error) {
+	if err := r.wait("Find"); err != nil {
+		return nil, err
+	}
+	for _, row := range r.matching() {
+		if row.ID == id {
+			return row, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryRepository) FindBy(field string, value interface{}) (ModelInterface, error) {
+	if field != "name" {
+		return nil, errors.New("unknown field " + field)
+	}
+	return r.where(value.(string)).First()
+}
+
+func (r *memoryRepository) Create(model ModelInterface) error {
+	if err := r.wait("Create"); err != nil {
+		return err
+	}
+	item := model.(*testItem)
+	item.ID = r.store.insert(item.Name)
+	return nil
+}
+
+func (r *memoryRepository) Update(model ModelInterface) error {
+	if err := r.wait("Update"); err != nil {
+		return err
+	}
+	item := *model.(*testItem)
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+	r.store.rows[item.ID] = &item
+	return nil
+}
+
+func (r *memoryRepository) Delete(model ModelInterface) error {
+	if err := r.wait("Delete"); err != nil {
+		return err
+	}
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+	delete(r.store.rows, model.GetID())
+	return nil
+}
+
+func (r *memoryRepository) All() ([]ModelInterface, error) {
+	if err := r.wait("All"); err != nil {
+		return nil, err
+	}
+	unfiltered := &memoryRepository{store: r.store}
+	return toModelInterfaces(unfiltered.matching()), nil
+}
+
+func (r *memoryRepository) Where(query interface{}, args ...interface{}) RepositoryInterface {
+	return r.where(query.(map[string]interface{})["name"].(string))
+}
+
+func (r *memoryRepository) where(name string) RepositoryInterface {
+	return &memoryRepository{store: r.store, name: name, ctx: r.ctx}
+}
+
+func (r *memoryRepository) First() (ModelInterface, error) {
+	if err := r.wait("First"); err != nil {
+		return nil, err
+	}
+	rows := r.matching()
+	if len(rows) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return rows[0], nil
+}
+
+func (r *memoryRepository) Get() ([]ModelInterface, error) {
+	if err := r.wait("Get"); err != nil {
+		return nil, err
+	}
+	return toModelInterfaces(r.matching()), nil
+}
+
+// pagedRepository adds database-style paging and context binding
+type pagedRepository struct {
+	memoryRepository
+}
+
+func (r *pagedRepository) Where(query interface{}, args ...interface{}) RepositoryInterface {
+	scoped := r.memoryRepository.Where(query, args...).(*memoryRepository)
+	return &pagedRepository{*scoped}
+}
+
+func (r *pagedRepository) WithContext(ctx context.Context) RepositoryInterface {
+	return &pagedRepository{memoryRepository{store: r.store, name: r.name, ctx: ctx}}
+}
+
+func (r *pagedRepository) Count() (int64, error) {
+	if err := r.wait("Count"); err != nil {
+		return 0, err
+	}
+	return int64(len(r.matching())), nil
+}
+
+func (r *pagedRepository) Page(offset, limit int) ([]ModelInterface, error) {
+	if err := r.wait("Page"); err != nil {
+		return nil, err
+	}
+	rows := r.matching()
+	if offset >= len(rows) {
+		return nil, nil
+	}
+	return toModelInterfaces(rows[offset:min(offset+limit, len(rows))]), nil
+}
+
+func (r *pagedRepository) After(afterID uint, limit int) ([]ModelInterface, error) {
+	if err := r.wait("After"); err != nil {
+		return nil, err
+	}
+	var page []*testItem
+	for _, row := range r.matching() {
+		if row.ID > afterID && len(page) < limit {
+			page = append(page, row)
+		}
+	}
+	return toModelInterfaces(page), nil
+}
+
+func toModelInterfaces(rows []*testItem) []ModelInterface {
+	models := make([]ModelInterface, len(rows))
+	for i, row := range rows {
+		models[i] = row
+	}
+	return models
+}
+
+func newPagingService(store *memoryStore) *BaseService[*testItem] {
+	return NewBaseService[*testItem](&pagedRepository{memoryRepository{store: store}}, nil)
+}
+
+func itemIDs(items []*testItem) []uint {
+	ids := make([]uint, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+func TestBaseServiceCRUD(t *testing.T) {
+	store := newMemoryStore()
+	service := newPagingService(store)
+
+	created, err := service.Create(map[string]interface{}{"name": "alpha"})
+	if err != nil || created.ID != 1 || created.Name != "alpha" {
+		t.Fatalf("expected alpha with ID 1, got %+v, %v", created, err)
+	}
+
+	found, err := service.FindByField("name", "alpha")
+	if err != nil || found.ID != 1 {
+		t.Fatalf("expected to find alpha by name, got %+v, %v", found, err)
+	}
+
+	updated, err := service.Update(1, map[string]interface{}{"name": "beta"})
+	if err != nil || updated.Name != "beta" {
+		t.Fatalf("expected update to rename to beta, got %+v, %v", updated, err)
+	}
+	if found, _ := service.FindByID(1); found.Name != "beta" {
+		t.Fatalf("expected the update to be stored, got %+v", found)
+	}
+
+	upserted, err := service.UpdateOrCreate(map[string]interface{}{"name": "gamma"}, map[string]interface{}{})
+	if err != nil || upserted.ID != 2 {
+		t.Fatalf("expected UpdateOrCreate to create gamma, got %+v, %v", upserted, err)
+	}
+	upserted, err = service.UpdateOrCreate(map[string]interface{}{"name": "gamma"}, map[string]interface{}{"name": "delta"})
+	if err != nil || upserted.ID != 2 || upserted.Name != "delta" {
+		t.Fatalf("expected UpdateOrCreate to update gamma, got %+v, %v", upserted, err)
+	}
+
+	if err := service.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	if exists, err := service.Exists(1); exists || err != nil {
+		t.Fatalf("expected entity 1 to be gone, got %v, %v", exists, err)
+	}
+	if err := service.DeleteWhere(map[string]interface{}{"name": "delta"}); err != nil {
+		t.Fatal(err)
+	}
+	if count, _ := service.Count(); count != 0 {
+		t.Fatalf("expected no entities left, got %d", count)
+	}
+}
+
+func TestPaginatePagesInRepository(t *testing.T) {
+	store := newMemoryStore("a", "b", "c", "d", "e")
+	service := newPagingService(store)
+
+	items, total, err := service.Paginate(2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 5 || !reflect.DeepEqual(itemIDs(items), []uint{3, 4}) {
+		t.Fatalf("expected page [3 4] of 5, got %v of %d", itemIDs(items), total)
+	}
+
+	items, total, err = service.Paginate(4, 2)
+	if err != nil || len(items) != 0 || total != 5 {
+		t.Fatalf("expected an empty page past the end, got %v of %d, %v", itemIDs(items), total, err)
+	}
+
+	if store.called("All") || store.called("Get") {
+		t.Fatalf("pagination must not load the whole table, calls: %v", store.calls)
+	}
+}
+
+func TestCountAndCountWhereCountInRepository(t *testing.T) {
+	store := newMemoryStore("a", "b", "a")
+	service := newPagingService(store)
+
+	if count, err := service.Count(); err != nil || count != 3 {
+		t.Fatalf("expected 3 entities, got %d, %v", count, err)
+	}
+	if count, err := service.CountWhere(map[string]interface{}{"name": "a"}); err != nil || count != 2 {
+		t.Fatalf("expected 2 entities named a, got %d, %v", count, err)
+	}
+	if store.called("All") || store.called("Get") {
+		t.Fatalf("counting must not load the whole table, calls: %v", store.calls)
+	}
+}
+
+func TestPaginateCursorHasNoGapsWhenRowsAreInserted(t *testing.T) {
+	store := newMemoryStore("a", "b", "c", "d", "e")
+	service := newPagingService(store)
+
+	var seen []uint
+	cursor := ""
+	for page := 0; page < 10; page++ {
+		items, next, err := service.PaginateCursor(cursor, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen = append(seen, itemIDs(items)...)
+		if page == 0 {
+			// A row written mid-iteration is picked up once, at the end
+			store.insert("f")
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if !reflect.DeepEqual(seen, []uint{1, 2, 3, 4, 5, 6}) {
+		t.Fatalf("expected every ID exactly once, got %v", seen)
+	}
+	if store.called("All") {
+		t.Fatal("cursor pagination must not load the whole table")
+	}
+}
+
+func TestPagingRequiresPagingRepository(t *testing.T) {
+	service := NewBaseService[*testItem](&memoryRepository{store: newMemoryStore("a")}, nil)
+
+	if _, _, err := service.Paginate(1, 10); !errors.Is(err, ErrPagingUnsupported) {
+		t.Fatalf("expected ErrPagingUnsupported from Paginate, got %v", err)
+	}
+	if _, _, err := service.PaginateCursor("", 10); !errors.Is(err, ErrPagingUnsupported) {
+		t.Fatalf("expected ErrPagingUnsupported from PaginateCursor, got %v", err)
+	}
+	if _, err := service.Count(); !errors.Is(err, ErrPagingUnsupported) {
+		t.Fatalf("expected ErrPagingUnsupported from Count, got %v", err)
+	}
+	if _, err := service.CountWhere(map[string]interface{}{"name": "a"}); !errors.Is(err, ErrPagingUnsupported) {
+		t.Fatalf("expected ErrPagingUnsupported from CountWhere, got %v", err)
+	}
+}
+
+func TestWriteWithContextCancelsContextRepository(t *testing.T) {
+	store := newMemoryStore()
+	store.delay = time.Second
+	service := newPagingService(store)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := service.CreateWithContext(ctx, map[string]interface{}{"name": "slow"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to abort the create, got %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatal("expected the create to stop when the context expired")
+	}
+	if store.has(1) {
+		t.Fatal("an aborted create must not be stored")
+	}
+}
+
+func TestWriteWithContextReportsCompletedWrite(t *testing.T) {
+	store := newMemoryStore()
+	store.delay = 100 * time.Millisecond
+	service := NewBaseService[*testItem](&memoryRepository{store: store}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	created, err := service.CreateWithContext(ctx, map[string]interface{}{"name": "slow"})
+	if err != nil {
+		t.Fatalf("a write that went through must not be reported as failed, got %v", err)
+	}
+	if created.ID != 1 || !store.has(1) {
+		t.Fatalf("expected the create to be stored and returned, got %+v", created)
+	}
+}
+
+func TestWriteWithContextSkipsCancelledContext(t *testing.T) {
+	store := newMemoryStore()
+	service := NewBaseService[*testItem](&memoryRepository{store: store}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := service.CreateWithContext(ctx, map[string]interface{}{"name": "late"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a cancelled context to be refused, got %v", err)
+	}
+	if store.has(1) {
+		t.Fatal("a refused create must not be stored")
+	}
+}
+
+func TestReadWithContextReturnsWhenDone(t *testing.T) {
+	for name, repository := range map[string]func(*memoryStore) RepositoryInterface{
+		"plain":         func(store *memoryStore) RepositoryInterface { return &memoryRepository{store: store} },
+		"context aware": func(store *memoryStore) RepositoryInterface { return &pagedRepository{memoryRepository{store: store}} },
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := newMemoryStore("a")
+			store.delay = time.Second
+			service := NewBaseService[*testItem](repository(store), nil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			if _, err := service.FindByIDWithContext(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("expected a deadline error, got %v", err)
+			}
+			if time.Since(start) > 500*time.Millisecond {
+				t.Fatal("expected the read to return when the context expired")
+			}
+		})
+	}
+}
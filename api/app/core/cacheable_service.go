@@ -0,0 +1,431 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// CacheDecoder decodes a JSON cache entry into a T
+type CacheDecoder[T any] func(data []byte) (T, error)
+
+// CacheableService adds cache-through reads to BaseService. Entries are
+// stored as JSON so they survive drivers that serialize values, and every
+// write invalidates the affected entries once it has finished, whether or not
+// it succeeded, so a failed write can't leave a stale entry behind. Failing
+// to invalidate is logged rather than failing a write that went through.
+//
+// JSON can only be decoded into a concrete T. When T is an interface, call
+// WithDecoder; until then the service reads straight through to the
+// repository and caches nothing.
+type CacheableService[T any] struct {
+	*BaseService[T]
+	decode CacheDecoder[T]
+}
+
+// NewCacheableService creates a new cacheable service
+func NewCacheableService[T any](repository RepositoryInterface, cache CacheInterface, options *ServiceOptions) *CacheableService[T] {
+	service := &CacheableService[T]{
+		BaseService: NewBaseService[T](repository, cache).WithOptions(options),
+	}
+	if reflect.TypeOf((*T)(nil)).Elem().Kind() != reflect.Interface {
+		service.decode = func(data []byte) (T, error) {
+			var result T
+			err := json.Unmarshal(data, &result)
+			return result, err
+		}
+	}
+	return service
+}
+
+// WithDecoder sets the decoder for cache entries, which is required when T
+// is an interface
+func (s *CacheableService[T]) WithDecoder(decode CacheDecoder[T]) *CacheableService[T] {
+	if decode != nil {
+		s.decode = decode
+	}
+	return s
+}
+
+// FindByIDCached finds an entity by ID, reading from cache and back-filling on a miss
+func (s *CacheableService[T]) FindByIDCached(id uint) (T, error) {
+	return s.findByIDCached(s.BaseService, id)
+}
+
+// FindByIDCachedWithContext finds an entity by ID through the cache with context
+func (s *CacheableService[T]) FindByIDCachedWithContext(ctx context.Context, id uint) (T, error) {
+	return readWithContext(ctx, s.BaseService, func(base *BaseService[T]) (T, error) { return s.findByIDCached(base, id) })
+}
+
+// findByIDCached finds an entity by ID through the cache, reading misses through base
+func (s *CacheableService[T]) findByIDCached(base *BaseService[T], id uint) (T, error) {
+	if s.decode == nil {
+		return base.FindByID(id)
+	}
+
+	key := s.GetCacheKey(id)
+	if result, ok := s.readCache(key); ok {
+		return result, nil
+	}
+
+	result, err := base.FindByID(id)
+	if err != nil {
+		return result, err
+	}
+	s.writeCache(key, result)
+	return result, nil
+}
+
+// FindByFieldCached finds an entity by field, reading from cache and back-filling on a miss
+func (s *CacheableService[T]) FindByFieldCached(field string, value interface{}) (T, error) {
+	return s.findByFieldCached(s.BaseService, field, value)
+}
+
+// FindByFieldCachedWithContext finds an entity by field through the cache with context
+func (s *CacheableService[T]) FindByFieldCachedWithContext(ctx context.Context, field string, value interface{}) (T, error) {
+	return readWithContext(ctx, s.BaseService, func(base *BaseService[T]) (T, error) {
+		return s.findByFieldCached(base, field, value)
+	})
+}
+
+// findByFieldCached finds an entity by field through the cache, reading misses through base
+func (s *CacheableService[T]) findByFieldCached(base *BaseService[T], field string, value interface{}) (T, error) {
+	if s.decode == nil {
+		return base.FindByField(field, value)
+	}
+
+	key := s.GetCacheKeyByField(field, value)
+	if result, ok := s.readCache(key); ok {
+		return result, nil
+	}
+
+	result, err := base.FindByField(field, value)
+	if err != nil {
+		return result, err
+	}
+	if model, ok := any(result).(ModelInterface); ok {
+		// Index the key before writing it, so no entry is left untracked
+		if err := s.trackFieldKey(model.GetID(), key); err != nil {
+			log.Printf("Failed to index cache key %s: %v", key, err)
+			return result, nil
+		}
+	}
+	s.writeCache(key, result)
+	return result, nil
+}
+
+// AllCached gets all entities, reading from cache and back-filling on a miss
+func (s *CacheableService[T]) AllCached() ([]T, error) {
+	return s.allCached(s.BaseService)
+}
+
+// AllCachedWithContext gets all entities through the cache with context
+func (s *CacheableService[T]) AllCachedWithContext(ctx context.Context) ([]T, error) {
+	return readWithContext(ctx, s.BaseService, s.allCached)
+}
+
+// allCached gets all entities through the cache, reading a miss through base
+func (s *CacheableService[T]) allCached(base *BaseService[T]) ([]T, error) {
+	if s.decode == nil {
+		return base.All()
+	}
+
+	key := s.allCacheKey()
+	if results, ok := s.readCachedList(key); ok {
+		return results, nil
+	}
+
+	results, err := base.All()
+	if err != nil {
+		return nil, err
+	}
+	s.writeCache(key, results)
+	return results, nil
+}
+
+// InvalidateCache removes the cached entity, its field lookups and the
+// cached list. Every entry is attempted and all failures are returned.
+func (s *CacheableService[T]) InvalidateCache(id uint) error {
+	errs := []error{s.cache.Delete(s.GetCacheKey(id))}
+
+	// The field key index lives in the cache, so lookups cached by any
+	// instance are found. It is only dropped once its keys are gone.
+	fieldKeys, err := s.fieldKeys(id)
+	errs = append(errs, err)
+	keysDeleted := err == nil
+	for _, key := range fieldKeys {
+		if err := s.cache.Delete(key); err != nil {
+			errs = append(errs, err)
+			keysDeleted = false
+		}
+	}
+	if keysDeleted {
+		errs = append(errs, s.cache.Delete(s.fieldIndexKey(id)))
+	}
+
+	errs = append(errs, s.cache.Delete(s.allCacheKey()))
+	return errors.Join(errs...)
+}
+
+// InvalidateCacheWithContext invalidates an entity's cache entries with context
+func (s *CacheableService[T]) InvalidateCacheWithContext(ctx context.Context, id uint) error {
+	_, err := withContext(ctx, func() (struct{}, error) { return struct{}{}, s.InvalidateCache(id) })
+	return err
+}
+
+// InvalidateAllCache removes the cache entries of every stored entity
+func (s *CacheableService[T]) InvalidateAllCache() error {
+	models, err := s.repository.All()
+	if err != nil {
+		return errors.Join(err, s.cache.Delete(s.allCacheKey()))
+	}
+
+	var errs []error
+	for _, model := range models {
+		errs = append(errs, s.InvalidateCache(model.GetID()))
+	}
+	errs = append(errs, s.cache.Delete(s.allCacheKey()))
+	return errors.Join(errs...)
+}
+
+// InvalidateAllCacheWithContext removes every cached entry with context
+func (s *CacheableService[T]) InvalidateAllCacheWithContext(ctx context.Context) error {
+	_, err := withContext(ctx, func() (struct{}, error) { return struct{}{}, s.InvalidateAllCache() })
+	return err
+}
+
+// Create creates an entity and invalidates the cached list
+func (s *CacheableService[T]) Create(data map[string]interface{}) (T, error) {
+	return s.create(s.BaseService, data)
+}
+
+// CreateWithContext creates an entity with context
+func (s *CacheableService[T]) CreateWithContext(ctx context.Context, data map[string]interface{}) (T, error) {
+	return writeWithContext(ctx, s.BaseService, func(base *BaseService[T]) (T, error) { return s.create(base, data) })
+}
+
+// Update updates an entity and invalidates its cache entries
+func (s *CacheableService[T]) Update(id uint, data map[string]interface{}) (T, error) {
+	return s.update(s.BaseService, id, data)
+}
+
+// UpdateWithContext updates an entity with context
+func (s *CacheableService[T]) UpdateWithContext(ctx context.Context, id uint, data map[string]interface{}) (T, error) {
+	return writeWithContext(ctx, s.BaseService, func(base *BaseService[T]) (T, error) { return s.update(base, id, data) })
+}
+
+// UpdateOrCreate updates or creates an entity and invalidates its cache entries
+func (s *CacheableService[T]) UpdateOrCreate(conditions map[string]interface{}, data map[string]interface{}) (T, error) {
+	return s.updateOrCreate(s.BaseService, conditions, data)
+}
+
+// UpdateOrCreateWithContext updates or creates an entity with context
+func (s *CacheableService[T]) UpdateOrCreateWithContext(ctx context.Context, conditions map[string]interface{}, data map[string]interface{}) (T, error) {
+	return writeWithContext(ctx, s.BaseService, func(base *BaseService[T]) (T, error) {
+		return s.updateOrCreate(base, conditions, data)
+	})
+}
+
+// Delete deletes an entity and invalidates its cache entries
+func (s *CacheableService[T]) Delete(id uint) error {
+	return s.delete(s.BaseService, id)
+}
+
+// DeleteWithContext deletes an entity with context
+func (s *CacheableService[T]) DeleteWithContext(ctx context.Context, id uint) error {
+	_, err := writeWithContext(ctx, s.BaseService, func(base *BaseService[T]) (struct{}, error) {
+		return struct{}{}, s.delete(base, id)
+	})
+	return err
+}
+
+// DeleteWhere deletes entities by conditions and invalidates the cache
+func (s *CacheableService[T]) DeleteWhere(conditions map[string]interface{}) error {
+	return s.deleteWhere(s.BaseService, conditions)
+}
+
+// DeleteWhereWithContext deletes entities by conditions with context
+func (s *CacheableService[T]) DeleteWhereWithContext(ctx context.Context, conditions map[string]interface{}) error {
+	_, err := writeWithContext(ctx, s.BaseService, func(base *BaseService[T]) (struct{}, error) {
+		return struct{}{}, s.deleteWhere(base, conditions)
+	})
+	return err
+}
+
+// The writes below run through base, which may be bound to a context, and
+// are shared by the plain and WithContext variants above so both invalidate.
+
+// create creates an entity through base and invalidates the cached list
+func (s *CacheableService[T]) create(base *BaseService[T], data map[string]interface{}) (T, error) {
+	result, err := base.Create(data)
+	s.logInvalidation("list", s.cache.Delete(s.allCacheKey()))
+	return result, err
+}
+
+// update updates an entity through base and invalidates its cache entries
+func (s *CacheableService[T]) update(base *BaseService[T], id uint, data map[string]interface{}) (T, error) {
+	result, err := base.Update(id, data)
+	s.logInvalidation(id, s.InvalidateCache(id))
+	return result, err
+}
+
+// updateOrCreate updates or creates an entity through base and invalidates its cache entries
+func (s *CacheableService[T]) updateOrCreate(base *BaseService[T], conditions map[string]interface{}, data map[string]interface{}) (T, error) {
+	result, err := base.UpdateOrCreate(conditions, data)
+	if model, ok := any(result).(ModelInterface); ok && err == nil {
+		s.logInvalidation(model.GetID(), s.InvalidateCache(model.GetID()))
+	} else {
+		s.logInvalidation("all", s.InvalidateAllCache())
+	}
+	return result, err
+}
+
+// delete deletes an entity through base and invalidates its cache entries
+func (s *CacheableService[T]) delete(base *BaseService[T], id uint) error {
+	err := base.Delete(id)
+	s.logInvalidation(id, s.InvalidateCache(id))
+	return err
+}
+
+// deleteWhere deletes entities by conditions through base and invalidates the cache
+func (s *CacheableService[T]) deleteWhere(base *BaseService[T], conditions map[string]interface{}) error {
+	// Collect IDs first since the entities are gone after the delete
+	models, lookupErr := base.repository.Where(conditions).Get()
+	err := base.DeleteWhere(conditions)
+
+	if lookupErr != nil {
+		// Which entities went is unknown, so nothing cached can be trusted
+		s.logInvalidation("all", s.InvalidateAllCache())
+		return err
+	}
+	for _, model := range models {
+		s.logInvalidation(model.GetID(), s.InvalidateCache(model.GetID()))
+	}
+	s.logInvalidation("list", s.cache.Delete(s.allCacheKey()))
+	return err
+}
+
+// logInvalidation logs a failure to invalidate cache entries after a write.
+// The write itself already happened, so it isn't failed over stale entries.
+func (s *CacheableService[T]) logInvalidation(target interface{}, err error) {
+	if err != nil {
+		log.Printf("Failed to invalidate %s cache entries for %v: %v", s.typeName(), target, err)
+	}
+}
+
+// allCacheKey is the key under which AllCached stores the full list
+func (s *CacheableService[T]) allCacheKey() string {
+	return s.typeName() + ":all"
+}
+
+// fieldIndexKey is the key under which an entity's field lookup keys are listed
+func (s *CacheableService[T]) fieldIndexKey(id uint) string {
+	return fmt.Sprintf("%s:%d:fields", s.typeName(), id)
+}
+
+// cacheTTL returns the configured TTL, or none to use the driver default
+func (s *CacheableService[T]) cacheTTL() []time.Duration {
+	if s.options != nil && s.options.CacheTTL > 0 {
+		return []time.Duration{time.Duration(s.options.CacheTTL) * time.Second}
+	}
+	return nil
+}
+
+// readCache returns the cached entity under key, if present and decodable
+func (s *CacheableService[T]) readCache(key string) (T, bool) {
+	var result T
+	data, ok := s.cachedBytes(key)
+	if !ok {
+		return result, false
+	}
+
+	result, err := s.decode(data)
+	return result, err == nil
+}
+
+// readCachedList returns the cached list under key, if present and decodable
+func (s *CacheableService[T]) readCachedList(key string) ([]T, bool) {
+	data, ok := s.cachedBytes(key)
+	if !ok {
+		return nil, false
+	}
+
+	var entries []json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, false
+	}
+	results := make([]T, 0, len(entries))
+	for _, entry := range entries {
+		result, err := s.decode(entry)
+		if err != nil {
+			return nil, false
+		}
+		results = append(results, result)
+	}
+	return results, true
+}
+
+// cachedBytes returns the raw JSON stored under key
+func (s *CacheableService[T]) cachedBytes(key string) ([]byte, bool) {
+	cached, ok := s.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	switch v := cached.(type) {
+	case string:
+		return []byte(v), true
+	case []byte:
+		return v, true
+	}
+	return nil, false
+}
+
+// writeCache stores value as JSON under key; failures only cost a cache miss
+func (s *CacheableService[T]) writeCache(key string, value interface{}) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	s.cache.Set(key, string(encoded), s.cacheTTL()...)
+}
+
+// fieldKeys returns the field lookup keys indexed for an entity
+func (s *CacheableService[T]) fieldKeys(id uint) ([]string, error) {
+	data, ok := s.cachedBytes(s.fieldIndexKey(id))
+	if !ok {
+		return nil, nil
+	}
+	var keys []string
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("decoding field key index of %s %d: %w", s.typeName(), id, err)
+	}
+	return keys, nil
+}
+
+// trackFieldKey adds a field lookup key to an entity's index in the cache.
+// The index is rewritten with the entries' TTL on every addition, so it lives
+// as long as the newest key it lists. Two instances adding keys at once can
+// lose one; that key is then only dropped by its TTL.
+func (s *CacheableService[T]) trackFieldKey(id uint, key string) error {
+	keys, err := s.fieldKeys(id)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(keys, key) {
+		keys = append(keys, key)
+	}
+
+	encoded, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	return s.cache.Set(s.fieldIndexKey(id), string(encoded), s.cacheTTL()...)
+}
+
+// Ensure CacheableService satisfies the interface it implements
+var _ CacheableServiceInterface[ModelInterface] = (*CacheableService[ModelInterface])(nil)
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// failingDeleteCache is a cache whose deletes always fail
+type failingDeleteCache struct {
+	*ArrayCacheDriver
+}
+
+func (c failingDeleteCache) Delete(key string) error {
+	return errors.New("cache unavailable")
+}
+
+func (s *memoryStore) count(call string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	n := 0
+	for _, c := range s.calls {
+		if c == call {
+			n++
+		}
+	}
+	return n
+}
+
+func newCacheableTestService(store *memoryStore, cache CacheInterface) *CacheableService[*testItem] {
+	return NewCacheableService[*testItem](&pagedRepository{memoryRepository{store: store}}, cache, nil)
+}
+
+func TestCacheableServiceReadsThroughCache(t *testing.T) {
+	store := newMemoryStore("a")
+	service := newCacheableTestService(store, NewArrayCacheDriver("test:", time.Minute))
+
+	for i := 0; i < 2; i++ {
+		item, err := service.FindByIDCached(1)
+		if err != nil || item.Name != "a" {
+			t.Fatalf("expected entity a, got %+v, %v", item, err)
+		}
+	}
+	if finds := store.count("Find"); finds != 1 {
+		t.Fatalf("expected the second read to hit the cache, got %d repository reads", finds)
+	}
+}
+
+func TestCacheableServiceInvalidatesFieldLookupsAcrossInstances(t *testing.T) {
+	store := newMemoryStore("a")
+	cache := NewArrayCacheDriver("test:", time.Minute)
+	reader := newCacheableTestService(store, cache)
+	writer := newCacheableTestService(store, cache)
+
+	if _, err := reader.FindByFieldCached("name", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if !cache.Has(reader.GetCacheKeyByField("name", "a")) {
+		t.Fatal("expected the field lookup to be cached")
+	}
+
+	// Another instance renames the entity, which must drop the lookup
+	if _, err := writer.Update(1, map[string]interface{}{"name": "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if cache.Has(reader.GetCacheKeyByField("name", "a")) {
+		t.Fatal("expected the field lookup to be invalidated by the other instance")
+	}
+	if cache.Has(reader.fieldIndexKey(1)) {
+		t.Fatal("expected the field key index to be dropped with its keys")
+	}
+	if _, err := reader.FindByFieldCached("name", "a"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected the stale lookup to miss, got %v", err)
+	}
+}
+
+func TestCacheableServiceInterfaceTypeNeedsDecoder(t *testing.T) {
+	store := newMemoryStore("a")
+	cache := NewArrayCacheDriver("test:", time.Minute)
+	repository := &pagedRepository{memoryRepository{store: store}}
+
+	service := NewCacheableService[ModelInterface](repository, cache, nil)
+	if model, err := service.FindByIDCached(1); err != nil || model.GetID() != 1 {
+		t.Fatalf("expected entity 1, got %v, %v", model, err)
+	}
+	if cache.Has(service.GetCacheKey(1)) {
+		t.Fatal("an entry that can't be decoded must not be written")
+	}
+
+	service.WithDecoder(func(data []byte) (ModelInterface, error) {
+		item := &testItem{}
+		err := json.Unmarshal(data, item)
+		return item, err
+	})
+	for i := 0; i < 2; i++ {
+		if model, err := service.FindByIDCached(1); err != nil || model.(*testItem).Name != "a" {
+			t.Fatalf("expected entity a, got %v, %v", model, err)
+		}
+	}
+	if finds := store.count("Find"); finds != 2 {
+		t.Fatalf("expected the decoder to serve the last read from cache, got %d repository reads", finds)
+	}
+
+	all, err := service.AllCached()
+	if err != nil || len(all) != 1 {
+		t.Fatal(err)
+	}
+	if all, err = service.AllCached(); err != nil || len(all) != 1 || store.count("All") != 1 {
+		t.Fatalf("expected the list to be served from cache, got %v, %v", all, err)
+	}
+}
+
+func TestCacheableServiceLogsInvalidationFailures(t *testing.T) {
+	store := newMemoryStore("a")
+	service := newCacheableTestService(store, failingDeleteCache{NewArrayCacheDriver("test:", time.Minute)})
+
+	var output bytes.Buffer
+	log.SetOutput(&output)
+	defer log.SetOutput(os.Stderr)
+
+	if _, err := service.Update(1, map[string]interface{}{"name": "b"}); err != nil {
+		t.Fatalf("a stored update must not be failed by the cache, got %v", err)
+	}
+	if !strings.Contains(output.String(), "Failed to invalidate testItem cache entries for 1") {
+		t.Fatalf("expected the invalidation failure to be logged, got %q", output.String())
+	}
+	if err := service.InvalidateCache(1); err == nil {
+		t.Fatal("expected InvalidateCache to return the delete failure")
+	}
+}
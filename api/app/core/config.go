@@ -1,66 +1,407 @@
 package core
 
 import (
-	"os"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
-var configRegistry = map[string]interface{}{}
+var (
+	configRegistry = map[string]interface{}{}
+	configMutex    sync.RWMutex
+
+	// loadedConfig holds a copy of every config as loaded from config funcs,
+	// files and overlays, without runtime changes, for Reload
+	loadedConfig = map[string]interface{}{}
+
+	// sensitiveConfigKeys matches the keys whose values All redacts
+	sensitiveConfigKeys = regexp.MustCompile(`(?i)password|secret|^token$|_token$|^key$|_key$`)
+
+	// frozenConfig holds every resolved value keyed by its full dotted key
+	// while the config is frozen; nil otherwise
+	frozenConfig map[string]interface{}
+)
 
 // LoadConfig loads all config maps into the registry
 func LoadConfig(configs map[string]map[string]interface{}) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
 	for k, v := range configs {
 		configRegistry[k] = v
+		loadedConfig[k] = cloneConfig(v)
+	}
+	frozenConfig = nil
+}
+
+// registerConfig registers a single named config map
+func registerConfig(name string, values map[string]interface{}) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	configRegistry[name] = values
+	loadedConfig[name] = cloneConfig(values)
+	frozenConfig = nil
+}
+
+// Freeze snapshots the registry into a flat map so reads on hot paths are a
+// single lookup. Any write (Set, Push, Prepend, Reload, LoadConfig,
+// LoadConfigFile) or ClearCache discards the snapshot.
+func Freeze() {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	freezeLocked()
+}
+
+// freezeLocked rebuilds the frozen snapshot; the write lock must be held
+func freezeLocked() {
+	frozen := make(map[string]interface{})
+	flattenConfig("", configRegistry, frozen)
+	frozenConfig = frozen
+}
+
+// ClearCache discards the frozen snapshot so reads walk the registry again
+func ClearCache() {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	frozenConfig = nil
+}
+
+// IsFrozen reports whether reads are currently served from a snapshot
+func IsFrozen() bool {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+
+	return frozenConfig != nil
+}
+
+// flattenConfig records every node of values, including nested maps, under
+// its dotted key
+func flattenConfig(prefix string, values map[string]interface{}, flat map[string]interface{}) {
+	for key, value := range values {
+		fullKey := key
+		if prefix != "" {
+			fullKey = prefix + "." + key
+		}
+		flat[fullKey] = value
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenConfig(fullKey, nested, flat)
+		}
+	}
+}
+
+// redactedValue replaces sensitive values in All
+const redactedValue = "[REDACTED]"
+
+// All returns a deep copy of every registered config tree, safe to mutate or
+// dump, with the values of keys matching the sensitive key pattern redacted
+func All() map[string]interface{} {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+
+	return copyConfig(configRegistry)
+}
+
+// SetSensitiveKeyPattern replaces the pattern of keys whose values All redacts
+func SetSensitiveKeyPattern(pattern *regexp.Regexp) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	sensitiveConfigKeys = pattern
+}
+
+// copyConfig deep-copies a config map, redacting sensitive keys
+func copyConfig(values map[string]interface{}) map[string]interface{} {
+	return copyConfigTree(values, true)
+}
+
+// cloneConfig deep-copies a config map as is
+func cloneConfig(values map[string]interface{}) map[string]interface{} {
+	return copyConfigTree(values, false)
+}
+
+// copyConfigTree deep-copies a config map, redacting sensitive keys if asked
+func copyConfigTree(values map[string]interface{}, redact bool) map[string]interface{} {
+	copied := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		if _, nested := value.(map[string]interface{}); redact && !nested && sensitiveConfigKeys.MatchString(key) {
+			copied[key] = redactedValue
+			continue
+		}
+		copied[key] = copyConfigValue(value, redact)
 	}
+	return copied
+}
+
+// copyConfigValue deep-copies maps and slices within a config value
+func copyConfigValue(value interface{}, redact bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return copyConfigTree(v, redact)
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = copyConfigValue(item, redact)
+		}
+		return copied
+	case []string:
+		return append([]string{}, v...)
+	}
+	return value
 }
 
 // Get retrieves a config value using dot notation (e.g. "database.username")
 func Get(key string, defaultValue ...interface{}) interface{} {
-	value := os.Getenv(key)
-	if value == "" {
-		if len(defaultValue) > 0 {
-			return defaultValue[0]
-		}
-		return nil
+	if value, ok := lookup(key); ok {
+		return value
 	}
-	parts := strings.Split(key, ".")
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return nil
+}
+
+// lookup resolves a dotted key from the frozen snapshot or the registry
+func lookup(key string) (interface{}, bool) {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+
+	if frozenConfig != nil {
+		value, ok := frozenConfig[key]
+		return value, ok
+	}
+	return lookupLocked(key)
+}
+
+// lookupLocked resolves a dotted key from the registry; the lock must be held
+func lookupLocked(key string) (interface{}, bool) {
 	var current interface{} = configRegistry
-	for _, part := range parts {
+	for _, part := range strings.Split(key, ".") {
 		m, ok := current.(map[string]interface{})
 		if !ok {
-			if len(defaultValue) > 0 {
-				return defaultValue[0]
-			}
-			return nil
+			return nil, false
 		}
 		current, ok = m[part]
 		if !ok {
-			if len(defaultValue) > 0 {
-				return defaultValue[0]
-			}
-			return nil
+			return nil, false
 		}
 	}
-	return current
+	return current, true
 }
 
-// Set sets a config value using dot notation (e.g. "app.debug")
+// GetString retrieves a config value as a string
+func GetString(key string, defaultValue ...string) string {
+	value, ok := lookup(key)
+	if !ok || value == nil {
+		if len(defaultValue) > 0 {
+			return defaultValue[0]
+		}
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
+
+// GetInt retrieves a config value as an int, parsing numeric strings.
+// Fractional values are truncated toward zero (4.99 becomes 4 and -2.7
+// becomes -2); use GetIntRounded to round to the nearest integer instead.
+func GetInt(key string, defaultValue ...int) int {
+	if value, ok := lookup(key); ok {
+		if f, err := toFloat(value); err == nil {
+			return int(f)
+		}
+	}
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return 0
+}
+
+// GetIntRounded retrieves a config value as an int, rounding fractional
+// values to the nearest integer with halves away from zero (4.99 becomes 5
+// and -2.5 becomes -3)
+func GetIntRounded(key string, defaultValue ...int) int {
+	if value, ok := lookup(key); ok {
+		if f, err := toFloat(value); err == nil {
+			return int(math.Round(f))
+		}
+	}
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return 0
+}
+
+// GetBool retrieves a config value as a bool, parsing strings like "true" or "1"
+func GetBool(key string, defaultValue ...bool) bool {
+	value, _ := lookup(key)
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+			return b
+		}
+	}
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return false
+}
+
+// GetFloat retrieves a config value as a float64, parsing numeric strings
+func GetFloat(key string, defaultValue ...float64) float64 {
+	if value, ok := lookup(key); ok {
+		if f, err := toFloat(value); err == nil {
+			return f
+		}
+	}
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return 0
+}
+
+// GetStringSlice retrieves a config value as a []string. Both []string and
+// []interface{} values are accepted; comma-separated strings are split.
+func GetStringSlice(key string, defaultValue ...[]string) []string {
+	value, _ := lookup(key)
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			result = append(result, fmt.Sprint(item))
+		}
+		return result
+	case string:
+		if v != "" {
+			return splitList(v)
+		}
+	}
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return nil
+}
+
+// GetDuration retrieves a config value as a time.Duration. Strings such as
+// "30s" are parsed as durations and bare integers are treated as seconds.
+func GetDuration(key string, defaultValue ...time.Duration) time.Duration {
+	if value, ok := lookup(key); ok {
+		if d, err := toDuration(value); err == nil {
+			return d
+		}
+	}
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return 0
+}
+
+// splitList splits a comma-separated string into trimmed items
+func splitList(value string) []string {
+	parts := strings.Split(value, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+// Set sets a config value using dot notation (e.g. "app.debug"). The last
+// write wins when paths conflict: setting "a.b.c" after "a.b" was a scalar
+// replaces that scalar with a map holding c, and setting "a.b" to a scalar
+// after "a.b.c" discards the map under "a.b".
 func Set(key string, value interface{}) {
-	parts := strings.Split(key, ".")
-	last := len(parts) - 1
-	var current interface{} = configRegistry
-	for i, part := range parts {
-		m, ok := current.(map[string]interface{})
-		if !ok {
-			return
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	setLocked(key, value)
+}
+
+// Push appends values to the list at key, creating it if missing
+// (e.g. Push("logging.channels", "slack"))
+func Push(key string, values ...interface{}) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	existing, _ := lookupLocked(key)
+	setLocked(key, append(toList(existing), values...))
+}
+
+// Prepend inserts values at the front of the list at key, creating it if missing
+func Prepend(key string, values ...interface{}) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	existing, _ := lookupLocked(key)
+	list := make([]interface{}, 0, len(values))
+	list = append(list, values...)
+	setLocked(key, append(list, toList(existing)...))
+}
+
+// Reload restores every config to its value as last loaded from config
+// funcs, files and environment overlays, discarding runtime changes made with
+// Set, Push and Prepend. Configs that were only ever Set are removed.
+func Reload() {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	configRegistry = make(map[string]interface{}, len(loadedConfig))
+	for name, values := range loadedConfig {
+		configRegistry[name] = copyConfigValue(values, false)
+	}
+	frozenConfig = nil
+}
+
+// toList copies a list value into a new []interface{}. Comma-separated
+// strings are split as GetStringSlice does and other scalars become a
+// single item.
+func toList(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case nil:
+		return []interface{}{}
+	case []interface{}:
+		return append([]interface{}{}, v...)
+	case []string:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = item
 		}
-		if i == last {
-			m[part] = value
-			return
+		return list
+	case string:
+		if v == "" {
+			return []interface{}{}
 		}
-		if _, exists := m[part]; !exists {
-			m[part] = map[string]interface{}{}
+		return toList(splitList(v))
+	}
+	return []interface{}{value}
+}
+
+// setLocked sets a dotted key in the registry, creating intermediate maps
+// and replacing any scalar in their way; the write lock must be held
+func setLocked(key string, value interface{}) {
+	frozenConfig = nil
+
+	parts := strings.Split(key, ".")
+	last := len(parts) - 1
+	current := configRegistry
+	for _, part := range parts[:last] {
+		next, isMap := current[part].(map[string]interface{})
+		if !isMap {
+			next = map[string]interface{}{}
+			current[part] = next
 		}
-		current = m[part]
+		current = next
 	}
+	current[parts[last]] = value
 }
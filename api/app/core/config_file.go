@@ -0,0 +1,105 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envTokenPattern matches ${ENV} and ${ENV:default} tokens in config values
+var envTokenPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::([^}]*))?\}`)
+
+// configFiles maps each config loaded from a file to its path, for WatchConfig
+var configFiles = map[string]string{}
+
+// LoadConfigFile reads a .json, .yaml or .yml file and registers its contents
+// under name. ${ENV:default} tokens in string values are replaced with the
+// environment variable, or the default when it is unset.
+func LoadConfigFile(name, path string) error {
+	values, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	registerConfig(name, values)
+
+	configMutex.Lock()
+	configFiles[name] = path
+	configMutex.Unlock()
+	return nil
+}
+
+// readConfigFile parses a config file, interpolating environment tokens
+func readConfigFile(path string) (map[string]interface{}, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	values := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(contents, &values)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(contents, &values)
+	default:
+		return nil, fmt.Errorf("unsupported config file type: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return interpolateEnv(values).(map[string]interface{}), nil
+}
+
+// LoadConfigDir registers every config file in dir under its base name,
+// e.g. services.yaml is registered as "services"
+func LoadConfigDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read config directory %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".json" && ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		if err := LoadConfigFile(name, filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// interpolateEnv replaces environment tokens in every string within value
+func interpolateEnv(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return envTokenPattern.ReplaceAllStringFunc(v, func(token string) string {
+			match := envTokenPattern.FindStringSubmatch(token)
+			if env, ok := os.LookupEnv(match[1]); ok && env != "" {
+				return env
+			}
+			return match[2]
+		})
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = interpolateEnv(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = interpolateEnv(item)
+		}
+		return v
+	}
+	return value
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// forgetConfig removes configs loaded by a test once it ends
+func forgetConfig(t *testing.T, names ...string) {
+	t.Helper()
+	t.Cleanup(func() {
+		configMutex.Lock()
+		defer configMutex.Unlock()
+		for _, name := range names {
+			delete(configRegistry, name)
+			delete(loadedConfig, name)
+			delete(configFiles, name)
+		}
+		frozenConfig = nil
+	})
+}
+
+func TestLoadConfigFileReadsNestedJSON(t *testing.T) {
+	forgetConfig(t, "filed")
+	t.Setenv("FILED_HOST", "db.internal")
+	t.Setenv("FILED_EMPTY", "")
+
+	path := filepath.Join(t.TempDir(), "filed.json")
+	writeConfigFile(t, path, `{
+		"connections": {
+			"mysql": {"host": "${FILED_HOST:localhost}", "port": "${FILED_PORT:3306}", "user": "${FILED_EMPTY:root}"}
+		},
+		"replicas": ["${FILED_HOST}", "backup"]
+	}`)
+
+	if err := LoadConfigFile("filed", path); err != nil {
+		t.Fatal(err)
+	}
+
+	// Set variables win, and unset or empty ones fall back to their default
+	want := map[string]string{
+		"filed.connections.mysql.host": "db.internal",
+		"filed.connections.mysql.port": "3306",
+		"filed.connections.mysql.user": "root",
+	}
+	for key, value := range want {
+		if got := GetString(key); got != value {
+			t.Errorf("%s: expected %q, got %q", key, value, got)
+		}
+	}
+	if got := GetInt("filed.connections.mysql.port"); got != 3306 {
+		t.Errorf("expected the port to read as an int, got %d", got)
+	}
+	if replicas := GetStringSlice("filed.replicas"); len(replicas) != 2 || replicas[0] != "db.internal" {
+		t.Errorf("expected tokens inside lists to be replaced, got %v", replicas)
+	}
+}
+
+func TestLoadConfigFileReadsYAML(t *testing.T) {
+	forgetConfig(t, "filed")
+
+	path := filepath.Join(t.TempDir(), "filed.yaml")
+	writeConfigFile(t, path, "queue:\n  driver: sqs\n  workers: 4\n")
+
+	if err := LoadConfigFile("filed", path); err != nil {
+		t.Fatal(err)
+	}
+	if GetString("filed.queue.driver") != "sqs" || GetInt("filed.queue.workers") != 4 {
+		t.Fatalf("expected the yaml values, got %v", Get("filed.queue"))
+	}
+}
+
+func TestLoadConfigFileRejectsBadFiles(t *testing.T) {
+	forgetConfig(t, "filed")
+	dir := t.TempDir()
+
+	writeConfigFile(t, filepath.Join(dir, "filed.toml"), "driver = \"sqs\"")
+	writeConfigFile(t, filepath.Join(dir, "broken.json"), "{")
+
+	for _, path := range []string{
+		filepath.Join(dir, "missing.json"),
+		filepath.Join(dir, "filed.toml"),
+		filepath.Join(dir, "broken.json"),
+	} {
+		if err := LoadConfigFile("filed", path); err == nil {
+			t.Errorf("expected loading %s to fail", filepath.Base(path))
+		}
+	}
+	if Get("filed") != nil {
+		t.Fatalf("expected nothing to be registered, got %v", Get("filed"))
+	}
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"slices"
+	"strings"
+)
+
+// MergeConfig deep-merges overlay into the config registered under name.
+// Nested maps are merged recursively; scalars and slices in the overlay
+// replace the existing value.
+func MergeConfig(name string, overlay map[string]interface{}) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	mergeConfigLocked(name, overlay)
+	frozenConfig = nil
+}
+
+// overlayEnvironments are the APP_ENV values whose "<name>.<env>" configs are
+// treated as overlays, along with whichever environment is active
+var overlayEnvironments = []string{"local", "development", "testing", "staging", "production"}
+
+// appliedOverlay records an environment overlay merged into a config, with
+// both sides as they were loaded, so a reload of either can merge them again
+type appliedOverlay struct {
+	target  string
+	name    string
+	base    map[string]interface{}
+	overlay map[string]interface{}
+}
+
+// merged returns a fresh copy of the base with the overlay merged in
+func (a *appliedOverlay) merged() map[string]interface{} {
+	merged := cloneConfig(a.base)
+	deepMerge(merged, cloneConfig(a.overlay))
+	return merged
+}
+
+// appliedOverlays maps each config an overlay was merged into to that overlay
+var appliedOverlays = map[string]*appliedOverlay{}
+
+// ApplyEnvironmentOverlays merges every config registered as "<name>.<env>"
+// into "<name>", e.g. "app.production" into "app" when env is production.
+// Overlays for every known environment are then removed from the registry so
+// they are never read directly, and overlays for other environments are no
+// longer watched. Other dotted names, and overlays that aren't maps, are
+// left alone.
+func ApplyEnvironmentOverlays(env string) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	for key, value := range configRegistry {
+		overlay, ok := value.(map[string]interface{})
+		dot := strings.LastIndex(key, ".")
+		if !ok || dot < 0 {
+			continue
+		}
+
+		switch suffix := key[dot+1:]; {
+		case suffix == env:
+			target := key[:dot]
+			base, _ := configRegistry[target].(map[string]interface{})
+			appliedOverlays[target] = &appliedOverlay{target: target, name: key, base: cloneConfig(base), overlay: cloneConfig(overlay)}
+			mergeConfigLocked(target, overlay)
+		case slices.Contains(overlayEnvironments, suffix):
+			delete(configFiles, key)
+		default:
+			continue
+		}
+		delete(configRegistry, key)
+		delete(loadedConfig, key)
+	}
+	frozenConfig = nil
+}
+
+// mergeConfigLocked merges overlay into the named config, and into the copy
+// Reload restores; configMutex must be held
+func mergeConfigLocked(name string, overlay map[string]interface{}) {
+	for _, registry := range []map[string]interface{}{configRegistry, loadedConfig} {
+		base, ok := registry[name].(map[string]interface{})
+		if !ok {
+			base = make(map[string]interface{})
+			registry[name] = base
+		}
+		deepMerge(base, cloneConfig(overlay))
+	}
+}
+
+// deepMerge merges src into dst in place
+func deepMerge(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			deepMerge(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
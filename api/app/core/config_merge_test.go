@@ -0,0 +1,85 @@
+package core
+
+import "testing"
+
+// registerTestConfig registers configs under their exact names, removing
+// them again when the test ends
+func registerTestConfig(t *testing.T, configs map[string]map[string]interface{}) {
+	t.Helper()
+	LoadConfig(configs)
+	t.Cleanup(func() {
+		configMutex.Lock()
+		defer configMutex.Unlock()
+		for name := range configs {
+			delete(configRegistry, name)
+			delete(loadedConfig, name)
+		}
+		frozenConfig = nil
+	})
+}
+
+func TestMergeConfigKeepsNestedBaseKeys(t *testing.T) {
+	registerTestConfig(t, map[string]map[string]interface{}{
+		"merged": {
+			"name": "app",
+			"mail": map[string]interface{}{"host": "localhost", "port": 25},
+			"tags": []interface{}{"a", "b"},
+		},
+	})
+
+	MergeConfig("merged", map[string]interface{}{
+		"mail": map[string]interface{}{"port": 587},
+		"tags": []interface{}{"c"},
+	})
+
+	if GetString("merged.mail.host") != "localhost" || GetString("merged.name") != "app" {
+		t.Fatal("expected base keys the overlay doesn't set to survive")
+	}
+	if GetInt("merged.mail.port") != 587 {
+		t.Fatalf("expected the overlay leaf to win, got %d", GetInt("merged.mail.port"))
+	}
+	if tags := GetStringSlice("merged.tags"); len(tags) != 1 || tags[0] != "c" {
+		t.Fatalf("expected the overlay slice to replace the base slice, got %v", tags)
+	}
+}
+
+func TestApplyEnvironmentOverlays(t *testing.T) {
+	registerTestConfig(t, map[string]map[string]interface{}{
+		"site":            {"debug": true, "db": map[string]interface{}{"host": "localhost", "pool": 5}},
+		"site.production": {"debug": false, "db": map[string]interface{}{"pool": 50}},
+		"site.staging":    {"debug": "staging"},
+	})
+
+	ApplyEnvironmentOverlays("production")
+
+	if GetBool("site.debug") || GetInt("site.db.pool") != 50 {
+		t.Fatal("expected the production overlay to be merged")
+	}
+	if GetString("site.db.host") != "localhost" {
+		t.Fatal("expected nested base keys to survive the overlay")
+	}
+	for _, name := range []string{"site.production", "site.staging"} {
+		if _, ok := All()[name]; ok {
+			t.Fatalf("expected overlay %s to be removed from the registry", name)
+		}
+	}
+}
+
+func TestApplyEnvironmentOverlaysKeepsOtherDottedNames(t *testing.T) {
+	registerTestConfig(t, map[string]map[string]interface{}{
+		"feature.flags": {"beta": true},
+		"api.v2":        {"enabled": true},
+	})
+
+	ApplyEnvironmentOverlays("production")
+
+	all := All()
+	for _, name := range []string{"feature.flags", "api.v2"} {
+		if _, ok := all[name]; !ok {
+			t.Fatalf("expected %s, which is not an environment overlay, to be kept", name)
+		}
+	}
+	if _, ok := all["feature"]; ok {
+		t.Fatal("expected nothing to be merged into the dotted name's prefix")
+	}
+}
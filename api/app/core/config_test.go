@@ -0,0 +1,295 @@
+package core
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// useBenchConfig registers a nested config block like the app's own
+func useBenchConfig(tb testing.TB) {
+	tb.Helper()
+	Set("bench", map[string]interface{}{
+		"name": "app",
+		"rate_limits": map[string]interface{}{
+			"api": map[string]interface{}{"rate": "10", "burst": 20},
+		},
+	})
+	tb.Cleanup(func() {
+		Set("bench", map[string]interface{}{})
+		ClearCache()
+	})
+}
+
+func TestFrozenConfigMatchesLiveConfig(t *testing.T) {
+	useBenchConfig(t)
+
+	live := []interface{}{GetString("bench.name"), GetInt("bench.rate_limits.api.burst"), GetFloat("bench.rate_limits.api.rate")}
+	Freeze()
+	if !IsFrozen() {
+		t.Fatal("expected the config to be frozen")
+	}
+	frozen := []interface{}{GetString("bench.name"), GetInt("bench.rate_limits.api.burst"), GetFloat("bench.rate_limits.api.rate")}
+
+	for i := range live {
+		if live[i] != frozen[i] {
+			t.Fatalf("expected frozen reads to match live reads, got %v and %v", frozen, live)
+		}
+	}
+
+	Set("bench.name", "changed")
+	if IsFrozen() || GetString("bench.name") != "changed" {
+		t.Fatal("expected a write to discard the snapshot")
+	}
+}
+
+func BenchmarkConfigRead(b *testing.B) {
+	useBenchConfig(b)
+
+	for _, mode := range []struct {
+		name   string
+		freeze bool
+	}{{"live", false}, {"frozen", true}} {
+		b.Run(mode.name, func(b *testing.B) {
+			ClearCache()
+			if mode.freeze {
+				Freeze()
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				GetInt("bench.rate_limits.api.burst")
+			}
+		})
+	}
+}
+
+func TestPushAndPrepend(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial interface{}
+		push    func(key string)
+		want    []string
+	}{
+		{"push onto missing key", nil, func(key string) { Push(key, "slack") }, []string{"slack"}},
+		{"prepend onto missing key", nil, func(key string) { Prepend(key, "slack") }, []string{"slack"}},
+		{"push onto scalar", "file", func(key string) { Push(key, "slack") }, []string{"file", "slack"}},
+		{"prepend onto scalar", "file", func(key string) { Prepend(key, "slack") }, []string{"slack", "file"}},
+		{"push onto comma list", "file,stderr", func(key string) { Push(key, "slack") }, []string{"file", "stderr", "slack"}},
+		{"push onto slice", []interface{}{"file", "stderr"}, func(key string) { Push(key, "slack", "sentry") }, []string{"file", "stderr", "slack", "sentry"}},
+		{"prepend onto slice", []string{"file", "stderr"}, func(key string) { Prepend(key, "slack", "sentry") }, []string{"slack", "sentry", "file", "stderr"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			channels := map[string]interface{}{}
+			if test.initial != nil {
+				channels["list"] = test.initial
+			}
+			Set("pushed", channels)
+			defer Set("pushed", map[string]interface{}{})
+
+			test.push("pushed.list")
+			if got := GetStringSlice("pushed.list"); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestPushDoesNotShareSliceWithCaller(t *testing.T) {
+	original := []interface{}{"file"}
+	Set("pushed", map[string]interface{}{"list": original})
+	defer Set("pushed", map[string]interface{}{})
+
+	Push("pushed.list", "slack")
+	if len(original) != 1 {
+		t.Fatalf("expected the original slice to be left alone, got %v", original)
+	}
+}
+
+func TestReloadRevertsRuntimeChanges(t *testing.T) {
+	registerTestConfig(t, map[string]map[string]interface{}{
+		"reloaded": {"channels": []interface{}{"file"}, "level": "info"},
+	})
+
+	Push("reloaded.channels", "slack")
+	Prepend("reloaded.channels", "stderr")
+	Set("reloaded.level", "debug")
+	Set("runtime_only", map[string]interface{}{"enabled": true})
+
+	Reload()
+
+	if got := GetStringSlice("reloaded.channels"); !reflect.DeepEqual(got, []string{"file"}) {
+		t.Fatalf("expected Reload to revert pushed values, got %v", got)
+	}
+	if GetString("reloaded.level") != "info" {
+		t.Fatalf("expected Reload to revert Set, got %q", GetString("reloaded.level"))
+	}
+	if Get("runtime_only") != nil {
+		t.Fatal("expected a config that was only ever Set to be removed")
+	}
+
+	// A second round of changes must not leak into what Reload restores
+	Push("reloaded.channels", "slack")
+	Reload()
+	if got := GetStringSlice("reloaded.channels"); len(got) != 1 {
+		t.Fatalf("expected Reload to restore the loaded copy again, got %v", got)
+	}
+}
+
+func TestReloadKeepsMergedOverlays(t *testing.T) {
+	registerTestConfig(t, map[string]map[string]interface{}{
+		"reloaded":            {"level": "info"},
+		"reloaded.production": {"level": "warning"},
+	})
+	ApplyEnvironmentOverlays("production")
+
+	Set("reloaded.level", "debug")
+	Reload()
+	if GetString("reloaded.level") != "warning" {
+		t.Fatalf("expected Reload to restore the merged overlay, got %q", GetString("reloaded.level"))
+	}
+}
+
+func TestAllReturnsRedactedDeepCopy(t *testing.T) {
+	Set("snapshot", map[string]interface{}{
+		"name": "app",
+		"database": map[string]interface{}{
+			"host":     "db",
+			"password": "hunter2",
+			"replicas": []interface{}{map[string]interface{}{"host": "replica", "secret": "s3"}},
+		},
+		"sqs":    map[string]interface{}{"access_key": "AKIA", "region": "eu-west-1"},
+		"tokens": map[string]interface{}{"ttl": 60},
+		"queues": []string{"high", "low"},
+	})
+	t.Cleanup(func() {
+		Set("snapshot", map[string]interface{}{})
+		ClearCache()
+	})
+
+	all := All()
+	snapshot := all["snapshot"].(map[string]interface{})
+	database := snapshot["database"].(map[string]interface{})
+
+	replica := database["replicas"].([]interface{})[0].(map[string]interface{})
+	if snapshot["name"] != "app" || database["host"] != "db" || replica["host"] != "replica" {
+		t.Fatalf("expected nested values in the snapshot, got %v", snapshot)
+	}
+	sqs := snapshot["sqs"].(map[string]interface{})
+	redacted := map[string]interface{}{
+		"database.password":          database["password"],
+		"database.replicas.0.secret": replica["secret"],
+		"sqs.access_key":             sqs["access_key"],
+	}
+	for key, value := range redacted {
+		if value != redactedValue {
+			t.Errorf("expected %s to be redacted, got %v", key, value)
+		}
+	}
+	// A sensitive name on a nested block redacts the values inside, not the block
+	if tokens, ok := snapshot["tokens"].(map[string]interface{}); !ok || tokens["ttl"] != 60 {
+		t.Errorf("expected the tokens block to be kept, got %v", snapshot["tokens"])
+	}
+
+	// Mutating the copy leaves the registered config alone
+	database["host"] = "changed"
+	snapshot["queues"].([]string)[0] = "changed"
+	if GetString("snapshot.database.host") != "db" || GetStringSlice("snapshot.queues")[0] != "high" {
+		t.Fatal("expected the snapshot not to share state with the config")
+	}
+	if GetString("snapshot.database.password") != "hunter2" {
+		t.Fatal("expected redaction to leave the config value intact")
+	}
+}
+
+func TestAllUsesConfiguredSensitiveKeyPattern(t *testing.T) {
+	Set("snapshot", map[string]interface{}{"password": "hunter2", "dsn": "mysql://user:pass@db"})
+	t.Cleanup(func() {
+		Set("snapshot", map[string]interface{}{})
+		ClearCache()
+	})
+
+	previous := sensitiveConfigKeys
+	SetSensitiveKeyPattern(regexp.MustCompile(`^dsn$`))
+	t.Cleanup(func() { SetSensitiveKeyPattern(previous) })
+
+	snapshot := All()["snapshot"].(map[string]interface{})
+	if snapshot["dsn"] != redactedValue || snapshot["password"] != "hunter2" {
+		t.Fatalf("expected only dsn to be redacted, got %v", snapshot)
+	}
+}
+
+func TestGetIntTruncatesAndGetIntRoundedRounds(t *testing.T) {
+	Set("ints", map[string]interface{}{
+		"almost_five": 4.99,
+		"negative":    -2.7,
+		"half":        2.5,
+		"neg_half":    -2.5,
+		"string":      "4.99",
+		"whole":       7,
+		"invalid":     "many",
+	})
+	t.Cleanup(func() {
+		Set("ints", map[string]interface{}{})
+		ClearCache()
+	})
+
+	cases := []struct {
+		key                string
+		truncated, rounded int
+	}{
+		{"ints.almost_five", 4, 5},
+		{"ints.negative", -2, -3},
+		{"ints.half", 2, 3},
+		{"ints.neg_half", -2, -3},
+		{"ints.string", 4, 5},
+		{"ints.whole", 7, 7},
+		{"ints.invalid", 9, 9},
+		{"ints.missing", 9, 9},
+	}
+	for _, tc := range cases {
+		if got := GetInt(tc.key, 9); got != tc.truncated {
+			t.Errorf("GetInt(%s): expected %d, got %d", tc.key, tc.truncated, got)
+		}
+		if got := GetIntRounded(tc.key, 9); got != tc.rounded {
+			t.Errorf("GetIntRounded(%s): expected %d, got %d", tc.key, tc.rounded, got)
+		}
+	}
+}
+
+func TestSetResolvesConflictingPaths(t *testing.T) {
+	t.Cleanup(func() {
+		Set("paths", map[string]interface{}{})
+		ClearCache()
+	})
+
+	// A scalar in the way of a deeper key is replaced by a map holding it
+	Set("paths", map[string]interface{}{})
+	Set("paths.a.b", 1)
+	Set("paths.a.b.c", 2)
+	if got := Get("paths.a.b"); !reflect.DeepEqual(got, map[string]interface{}{"c": 2}) {
+		t.Fatalf("expected a.b to become a map holding c, got %#v", got)
+	}
+	if GetInt("paths.a.b.c") != 2 {
+		t.Fatalf("expected a.b.c to be 2, got %v", Get("paths.a.b.c"))
+	}
+
+	// A scalar written over a nested map discards the map
+	Set("paths.a.b", 3)
+	if got := Get("paths.a.b"); got != 3 {
+		t.Fatalf("expected a.b to be replaced by 3, got %#v", got)
+	}
+	if got := Get("paths.a.b.c"); got != nil {
+		t.Fatalf("expected a.b.c to be gone, got %#v", got)
+	}
+
+	// Siblings are left alone either way
+	Set("paths.a.keep", "yes")
+	Set("paths.a.b.d", 4)
+	if GetString("paths.a.keep") != "yes" || GetInt("paths.a.b.d") != 4 {
+		t.Fatalf("expected siblings to survive, got %#v", Get("paths.a"))
+	}
+}
@@ -0,0 +1,239 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Unmarshal maps the config sub-tree at key into target, which must be a
+// pointer. Struct fields are matched by their config tag, then json tag, then
+// a case-insensitive field name. Values are coerced the same way as GetInt,
+// GetBool and GetDuration, so "30" fills an int and "true" fills a bool.
+func Unmarshal(key string, target interface{}) error {
+	value, ok := lookup(key)
+	if !ok {
+		return fmt.Errorf("config key %s not found", key)
+	}
+
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("config target must be a non-nil pointer, got %T", target)
+	}
+
+	return decodeConfigValue(key, value, rv.Elem())
+}
+
+// decodeConfigValue assigns value to target, converting where needed
+func decodeConfigValue(path string, value interface{}, target reflect.Value) error {
+	if value == nil {
+		return nil
+	}
+
+	if target.Type() == durationType {
+		d, err := toDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		target.SetInt(int64(d))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Ptr:
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return decodeConfigValue(path, value, target.Elem())
+	case reflect.Interface:
+		target.Set(reflect.ValueOf(value))
+		return nil
+	case reflect.Struct:
+		return decodeConfigStruct(path, value, target)
+	case reflect.Map:
+		return decodeConfigMap(path, value, target)
+	case reflect.Slice:
+		return decodeConfigSlice(path, value, target)
+	case reflect.String:
+		target.SetString(fmt.Sprint(value))
+		return nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(fmt.Sprint(value)))
+		if err != nil {
+			return fmt.Errorf("%s: cannot use %v as bool", path, value)
+		}
+		target.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f, err := toFloat(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		target.SetInt(int64(f))
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f, err := toFloat(value)
+		if err != nil || f < 0 {
+			return fmt.Errorf("%s: cannot use %v as unsigned integer", path, value)
+		}
+		target.SetUint(uint64(f))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		target.SetFloat(f)
+		return nil
+	}
+
+	return fmt.Errorf("%s: unsupported target type %s", path, target.Type())
+}
+
+// decodeConfigStruct fills a struct's fields from a config map
+func decodeConfigStruct(path string, value interface{}, target reflect.Value) error {
+	values, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: cannot use %T as %s", path, value, target.Type())
+	}
+
+	targetType := target.Type()
+	for i := 0; i < targetType.NumField(); i++ {
+		field := targetType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := decodeConfigStruct(path, values, target.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := configFieldName(field)
+		if name == "-" {
+			continue
+		}
+
+		fieldValue, found := values[name]
+		if !found {
+			for key, v := range values {
+				if strings.EqualFold(key, name) {
+					fieldValue, found = v, true
+					break
+				}
+			}
+		}
+		if !found {
+			continue
+		}
+
+		if err := decodeConfigValue(path+"."+name, fieldValue, target.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeConfigMap fills a string-keyed map from a config map
+func decodeConfigMap(path string, value interface{}, target reflect.Value) error {
+	values, ok := value.(map[string]interface{})
+	if !ok || target.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("%s: cannot use %T as %s", path, value, target.Type())
+	}
+
+	if target.IsNil() {
+		target.Set(reflect.MakeMapWithSize(target.Type(), len(values)))
+	}
+	for key, item := range values {
+		element := reflect.New(target.Type().Elem()).Elem()
+		if err := decodeConfigValue(path+"."+key, item, element); err != nil {
+			return err
+		}
+		target.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), element)
+	}
+	return nil
+}
+
+// decodeConfigSlice fills a slice from a config list or comma-separated string
+func decodeConfigSlice(path string, value interface{}, target reflect.Value) error {
+	var items []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		items = v
+	case []string:
+		for _, item := range v {
+			items = append(items, item)
+		}
+	case string:
+		for _, item := range splitList(v) {
+			items = append(items, item)
+		}
+	default:
+		return fmt.Errorf("%s: cannot use %T as %s", path, value, target.Type())
+	}
+
+	slice := reflect.MakeSlice(target.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := decodeConfigValue(fmt.Sprintf("%s.%d", path, i), item, slice.Index(i)); err != nil {
+			return err
+		}
+	}
+	target.Set(slice)
+	return nil
+}
+
+// configFieldName returns the config key for a struct field
+func configFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"config", "json"} {
+		if name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]; name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// toFloat converts numeric values and numeric strings to float64
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("cannot use %q as number", v)
+		}
+		return f, nil
+	}
+	return 0, fmt.Errorf("cannot use %T as number", value)
+}
+
+// toDuration converts integer seconds or duration strings to time.Duration
+func toDuration(value interface{}) (time.Duration, error) {
+	switch v := value.(type) {
+	case time.Duration:
+		return v, nil
+	case string:
+		v = strings.TrimSpace(v)
+		if seconds, err := strconv.Atoi(v); err == nil {
+			return time.Duration(seconds) * time.Second, nil
+		}
+		return time.ParseDuration(v)
+	}
+	seconds, err := toFloat(value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
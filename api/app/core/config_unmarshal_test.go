@@ -0,0 +1,85 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type redisDriverConfig struct {
+	Host     string        `config:"host"`
+	Port     int           `json:"port"`
+	Database uint          `config:"database"`
+	Cluster  bool          `config:"cluster"`
+	Timeout  time.Duration `config:"timeout"`
+	Ratio    float64       `config:"ratio"`
+	Nodes    []string      `config:"nodes"`
+	Prefix   *string       `config:"prefix"`
+	Password string        // matched by field name regardless of case
+	Ignored  string        `config:"-"`
+	Options  map[string]int
+}
+
+func useRedisDriverConfig(t *testing.T, redis map[string]interface{}) {
+	t.Helper()
+	Set("unmarshal", map[string]interface{}{
+		"stores": map[string]interface{}{"redis": redis},
+	})
+	t.Cleanup(func() {
+		Set("unmarshal", map[string]interface{}{})
+		ClearCache()
+	})
+}
+
+func TestUnmarshalNestedDriverConfig(t *testing.T) {
+	useRedisDriverConfig(t, map[string]interface{}{
+		"host":     "redis",
+		"port":     "6379",
+		"database": float64(2),
+		"cluster":  "true",
+		"timeout":  "5s",
+		"ratio":    "0.5",
+		"nodes":    "a:7000, b:7001",
+		"prefix":   "app:",
+		"PASSWORD": "secret",
+		"Ignored":  "kept out",
+		"options":  map[string]interface{}{"pool": "10", "retries": 3},
+	})
+
+	var cfg redisDriverConfig
+	if err := Unmarshal("unmarshal.stores.redis", &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	prefix := "app:"
+	want := redisDriverConfig{
+		Host:     "redis",
+		Port:     6379,
+		Database: 2,
+		Cluster:  true,
+		Timeout:  5 * time.Second,
+		Ratio:    0.5,
+		Nodes:    []string{"a:7000", "b:7001"},
+		Prefix:   &prefix,
+		Password: "secret",
+		Options:  map[string]int{"pool": 10, "retries": 3},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("expected %+v, got %+v", want, cfg)
+	}
+}
+
+func TestUnmarshalReportsBadValues(t *testing.T) {
+	useRedisDriverConfig(t, map[string]interface{}{"port": "not-a-port"})
+
+	var cfg redisDriverConfig
+	if err := Unmarshal("unmarshal.stores.redis", &cfg); err == nil {
+		t.Fatal("expected an error for a non-numeric port")
+	}
+	if err := Unmarshal("unmarshal.stores.missing", &cfg); err == nil {
+		t.Fatal("expected an error for a missing key")
+	}
+	if err := Unmarshal("unmarshal.stores.redis", cfg); err == nil {
+		t.Fatal("expected an error for a non-pointer target")
+	}
+}
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// configWatchInterval is how often watched config files are checked
+var configWatchInterval = time.Second
+
+// ConfigReloaded is dispatched after a watched config file has been reloaded
+type ConfigReloaded struct {
+	Name string `json:"name"`
+}
+
+// GetEventName returns the event name
+func (e *ConfigReloaded) GetEventName() string {
+	return "config.reloaded"
+}
+
+// fileState identifies a version of a file on disk
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// WatchConfig reloads the config registered under name whenever the file it
+// was loaded from changes, then dispatches a ConfigReloaded event if the
+// event dispatcher is running. A change is only loaded once the file has
+// stopped changing for a full check interval, so half-written files are
+// skipped. Call the returned func to stop.
+//
+// Files are polled rather than watched with fsnotify: polling needs no extra
+// dependency, and it still sees changes that inotify misses, such as files
+// on bind mounts or in Kubernetes ConfigMaps that are swapped by a symlink
+// rename. A check every second is cheap for a handful of config files.
+func WatchConfig(name string) (func(), error) {
+	configMutex.RLock()
+	path, ok := configFiles[name]
+	configMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("config %s was not loaded from a file", name)
+	}
+
+	loaded, err := statFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	stop := make(chan struct{})
+	interval := configWatchInterval
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		pending := loaded
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			current, err := statFile(path)
+			if err != nil || current == loaded {
+				continue
+			}
+			// Wait for the file to settle before reading it
+			if current != pending {
+				pending = current
+				continue
+			}
+
+			loaded = current
+			if err := reloadConfigFile(name, path); err != nil {
+				log.Printf("Failed to reload config %s: %v", name, err)
+				continue
+			}
+			log.Printf("Reloaded config %s from %s", name, path)
+
+			if EventDispatcherInstance != nil {
+				if err := EventDispatcherInstance.DispatchSync(&ConfigReloaded{Name: name}); err != nil {
+					log.Printf("Error handling config.reloaded for %s: %v", name, err)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(stop) }) }, nil
+}
+
+// WatchConfigFiles watches every config that was loaded from a file,
+// returning a func that stops all of the watchers
+func WatchConfigFiles() (func(), error) {
+	configMutex.RLock()
+	names := make([]string, 0, len(configFiles))
+	for name := range configFiles {
+		names = append(names, name)
+	}
+	configMutex.RUnlock()
+
+	stops := make([]func(), 0, len(names))
+	stopAll := func() {
+		for _, stop := range stops {
+			stop()
+		}
+	}
+	for _, name := range names {
+		stop, err := WatchConfig(name)
+		if err != nil {
+			stopAll()
+			return nil, err
+		}
+		stops = append(stops, stop)
+	}
+	return stopAll, nil
+}
+
+// reloadConfigFile re-reads a watched config file. An active environment
+// overlay, or the config one was merged into, is merged again rather than
+// replacing the block, and a frozen config is frozen again so reads stay on
+// the snapshot.
+func reloadConfigFile(name, path string) error {
+	values, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	target := name
+	for _, applied := range appliedOverlays {
+		if applied.name == name {
+			applied.overlay = values
+		} else if applied.target == name {
+			applied.base = values
+		} else {
+			continue
+		}
+		target, values = applied.target, applied.merged()
+		break
+	}
+
+	configRegistry[target] = values
+	loadedConfig[target] = cloneConfig(values)
+	if frozenConfig != nil {
+		freezeLocked()
+	}
+	return nil
+}
+
+// statFile returns the modification time and size of a file
+func statFile(path string) (fileState, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}, err
+	}
+	return fileState{modTime: info.ModTime(), size: info.Size()}, nil
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// watchTestDir loads the given files from a temp dir, applies overlays for
+// production and forgets everything the test registered when it ends
+func watchTestDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, contents := range files {
+		writeConfigFile(t, filepath.Join(dir, name), contents)
+	}
+
+	previous := configWatchInterval
+	configWatchInterval = 10 * time.Millisecond
+	t.Cleanup(func() {
+		configWatchInterval = previous
+		configMutex.Lock()
+		defer configMutex.Unlock()
+		for _, name := range []string{"watched", "watched.production", "watched.staging"} {
+			delete(configRegistry, name)
+			delete(loadedConfig, name)
+			delete(configFiles, name)
+			delete(appliedOverlays, name)
+		}
+		frozenConfig = nil
+	})
+
+	if err := LoadConfigDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	ApplyEnvironmentOverlays("production")
+	return dir
+}
+
+func writeConfigFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitForConfig polls until key reads as want
+func waitForConfig(t *testing.T, key, want string) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for GetString(key) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %s to become %q, still %q", key, want, GetString(key))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWatchConfigReappliesOverlays(t *testing.T) {
+	dir := watchTestDir(t, map[string]string{
+		"watched.yaml":            "level: info\nname: app\n",
+		"watched.production.yaml": "level: warning\n",
+	})
+	if GetString("watched.level") != "warning" {
+		t.Fatalf("expected the overlay at boot, got %q", GetString("watched.level"))
+	}
+
+	stop, err := WatchConfigFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	writeConfigFile(t, filepath.Join(dir, "watched.production.yaml"), "level: error\n")
+	waitForConfig(t, "watched.level", "error")
+	if GetString("watched.name") != "app" {
+		t.Fatal("expected base keys to survive an overlay reload")
+	}
+
+	writeConfigFile(t, filepath.Join(dir, "watched.yaml"), "level: debug\nname: renamed\n")
+	waitForConfig(t, "watched.name", "renamed")
+	if GetString("watched.level") != "error" {
+		t.Fatalf("expected the overlay to be merged into a reloaded base, got %q", GetString("watched.level"))
+	}
+}
+
+func TestWatchConfigKeepsConfigFrozen(t *testing.T) {
+	dir := watchTestDir(t, map[string]string{"watched.json": `{"level": "info"}`})
+	Freeze()
+
+	stop, err := WatchConfig("watched")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	writeConfigFile(t, filepath.Join(dir, "watched.json"), `{"level": "warning"}`)
+	waitForConfig(t, "watched.level", "warning")
+	if !IsFrozen() {
+		t.Fatal("expected a reload to keep the config frozen")
+	}
+}
+
+func TestApplyEnvironmentOverlaysStopsWatchingOtherEnvironments(t *testing.T) {
+	watchTestDir(t, map[string]string{
+		"watched.yaml":         "level: info\n",
+		"watched.staging.yaml": "level: debug\n",
+	})
+
+	if _, err := WatchConfig("watched.staging"); err == nil {
+		t.Fatal("expected an inactive overlay not to be watchable")
+	}
+	if GetString("watched.level") != "info" {
+		t.Fatalf("expected the staging overlay to be ignored, got %q", GetString("watched.level"))
+	}
+}
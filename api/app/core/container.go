@@ -0,0 +1,127 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrServiceNotFound is returned when nothing is registered under a name
+var ErrServiceNotFound = errors.New("service not found")
+
+// ErrDependencyCycle is returned when services depend on each other in a loop
+var ErrDependencyCycle = errors.New("dependency cycle")
+
+// injectTag is the struct tag AutoResolve reads service names from
+const injectTag = "inject"
+
+// Container is implemented by the service and repository containers
+type Container interface {
+	Register(name string, service interface{})
+	Get(name string) (interface{}, bool)
+}
+
+// ResolveAs gets a service by name as T, returning an error rather than
+// panicking when it is missing or registered with a different type
+func ResolveAs[T any](c Container, name string) (T, error) {
+	var zero T
+	service, exists := c.Get(name)
+	if !exists {
+		return zero, fmt.Errorf("%w: %s", ErrServiceNotFound, name)
+	}
+
+	typed, ok := service.(T)
+	if !ok {
+		return zero, fmt.Errorf("service %s is %T, not %s", name, service, reflect.TypeOf((*T)(nil)).Elem())
+	}
+	return typed, nil
+}
+
+// MustResolveAs gets a service by name as T and panics with a descriptive
+// message if it can't; use it during bootstrap where a missing service is fatal
+func MustResolveAs[T any](c Container, name string) T {
+	service, err := ResolveAs[T](c, name)
+	if err != nil {
+		panic(err)
+	}
+	return service
+}
+
+// BindSingleton registers a single shared instance under name, fixing its
+// type at compile time
+func BindSingleton[T any](c Container, name string, service T) {
+	c.Register(name, service)
+}
+
+// AutoResolve fills the fields of the struct target points to that carry an
+// inject tag with the services registered under the tagged names, e.g.
+//
+//	type ReportService struct {
+//		Users *services.UserService `inject:"user"`
+//		Cache facades.CacheInterface `inject:""` // name defaults to "cache"
+//	}
+//
+// An empty tag uses the field name with its first letter lowercased. Fields
+// that are already set are left alone. Injected services that are struct
+// pointers are wired the same way first, so dependencies of dependencies are
+// resolved too; a loop returns ErrDependencyCycle. It mutates shared
+// services, so call it during bootstrap rather than per request.
+func AutoResolve(c Container, target interface{}) error {
+	value := reflect.ValueOf(target)
+	if !isStructPointer(value) {
+		return fmt.Errorf("auto-resolve needs a non-nil struct pointer, got %T", target)
+	}
+	return autoResolve(c, value, nil)
+}
+
+// isStructPointer reports whether value is a non-nil pointer to a struct
+func isStructPointer(value reflect.Value) bool {
+	return value.IsValid() && value.Kind() == reflect.Pointer && !value.IsNil() && value.Elem().Kind() == reflect.Struct
+}
+
+// autoResolve wires the fields of the struct target points to; stack holds
+// the names of the services currently being resolved
+func autoResolve(c Container, target reflect.Value, stack []string) error {
+	elem := target.Elem()
+	typ := elem.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, tagged := field.Tag.Lookup(injectTag)
+		if !tagged {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name[:1]) + field.Name[1:]
+		}
+		if !field.IsExported() {
+			return fmt.Errorf("cannot inject %s into unexported field %s.%s", name, typ, field.Name)
+		}
+		if !elem.Field(i).IsZero() {
+			continue
+		}
+
+		path := append(stack[:len(stack):len(stack)], name)
+		if slices.Contains(stack, name) {
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(path, " -> "))
+		}
+
+		service, exists := c.Get(name)
+		if !exists {
+			return fmt.Errorf("%w: %s (needed by %s.%s)", ErrServiceNotFound, name, typ, field.Name)
+		}
+		value := reflect.ValueOf(service)
+		if !value.IsValid() || !value.Type().AssignableTo(field.Type) {
+			return fmt.Errorf("service %s is %T, not assignable to %s.%s of type %s", name, service, typ, field.Name, field.Type)
+		}
+
+		if isStructPointer(value) {
+			if err := autoResolve(c, value, path); err != nil {
+				return err
+			}
+		}
+		elem.Field(i).Set(value)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// mapContainer is a minimal name-based Container
+type mapContainer map[string]interface{}
+
+func (c mapContainer) Register(name string, service interface{}) { c[name] = service }
+
+func (c mapContainer) Get(name string) (interface{}, bool) {
+	service, exists := c[name]
+	return service, exists
+}
+
+type alphaService struct {
+	Beta *betaService `inject:"beta"`
+}
+
+type betaService struct {
+	Alpha *alphaService `inject:"alpha"`
+}
+
+type gammaService struct {
+	Gamma *gammaService `inject:"gamma"`
+}
+
+type leafService struct{ Name string }
+
+type rootService struct {
+	Middle *middleService `inject:""`
+}
+
+type middleService struct {
+	Leaf *leafService `inject:"leaf"`
+}
+
+func TestAutoResolveReportsCycles(t *testing.T) {
+	c := mapContainer{"alpha": &alphaService{}, "beta": &betaService{}}
+
+	var target struct {
+		Alpha *alphaService `inject:"alpha"`
+	}
+	err := AutoResolve(c, &target)
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("expected ErrDependencyCycle, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "alpha -> beta -> alpha") {
+		t.Fatalf("expected the cycle path in the error, got %v", err)
+	}
+}
+
+func TestAutoResolveReportsSelfCycles(t *testing.T) {
+	c := mapContainer{"gamma": &gammaService{}}
+
+	var target struct {
+		Gamma *gammaService `inject:"gamma"`
+	}
+	err := AutoResolve(c, &target)
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("expected ErrDependencyCycle, got %v", err)
+	}
+}
+
+func TestAutoResolveWiresNestedServices(t *testing.T) {
+	leaf := &leafService{Name: "leaf"}
+	c := mapContainer{"middle": &middleService{}, "leaf": leaf}
+
+	root := &rootService{}
+	if err := AutoResolve(c, root); err != nil {
+		t.Fatal(err)
+	}
+	if root.Middle == nil || root.Middle.Leaf != leaf {
+		t.Fatal("expected the dependency's own dependencies to be wired")
+	}
+}
+
+func TestAutoResolveReportsMissingServices(t *testing.T) {
+	err := AutoResolve(mapContainer{}, &rootService{})
+	if !errors.Is(err, ErrServiceNotFound) {
+		t.Fatalf("expected ErrServiceNotFound, got %v", err)
+	}
+}
+
+// greeter lets ResolveAs be tested against an interface type
+type greeter interface{ Greet() string }
+
+func (s *leafService) Greet() string { return "hello " + s.Name }
+
+func TestResolveAsReturnsTypedService(t *testing.T) {
+	container := mapContainer{}
+	leaf := &leafService{Name: "leaf"}
+	BindSingleton(container, "leaf", leaf)
+
+	resolved, err := ResolveAs[*leafService](container, "leaf")
+	if err != nil || resolved != leaf {
+		t.Fatalf("expected the registered instance, got %v, %v", resolved, err)
+	}
+	asInterface, err := ResolveAs[greeter](container, "leaf")
+	if err != nil || asInterface.Greet() != "hello leaf" {
+		t.Fatalf("expected the service as an interface it implements, got %v, %v", asInterface, err)
+	}
+	if MustResolveAs[*leafService](container, "leaf") != leaf {
+		t.Fatal("expected MustResolveAs to return the registered instance")
+	}
+}
+
+func TestResolveAsReportsWrongType(t *testing.T) {
+	container := mapContainer{"leaf": &leafService{}}
+
+	resolved, err := ResolveAs[*middleService](container, "leaf")
+	if err == nil || resolved != nil {
+		t.Fatalf("expected an error and a zero value, got %v, %v", resolved, err)
+	}
+	if !strings.Contains(err.Error(), "service leaf is *core.leafService, not *core.middleService") {
+		t.Fatalf("expected the error to name both types, got %v", err)
+	}
+	if _, err := ResolveAs[greeter](mapContainer{"leaf": "not a greeter"}, "leaf"); err == nil {
+		t.Fatal("expected an error for a service not implementing the interface")
+	}
+}
+
+func TestResolveAsReportsMissingService(t *testing.T) {
+	_, err := ResolveAs[*leafService](mapContainer{}, "leaf")
+	if !errors.Is(err, ErrServiceNotFound) {
+		t.Fatalf("expected ErrServiceNotFound, got %v", err)
+	}
+
+	defer func() {
+		recovered, _ := recover().(error)
+		if !errors.Is(recovered, ErrServiceNotFound) {
+			t.Fatalf("expected MustResolveAs to panic with ErrServiceNotFound, got %v", recovered)
+		}
+	}()
+	MustResolveAs[*leafService](mapContainer{}, "leaf")
+}
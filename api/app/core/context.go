@@ -0,0 +1,62 @@
+package core
+
+import (
+	"context"
+	"time"
+)
+
+// ctxKey is the type used for all context keys owned by this package.
+// Using a distinct type instead of bare strings prevents collisions with
+// values stored by other packages.
+type ctxKey string
+
+const (
+	eventNameKey      ctxKey = "event_name"
+	operationStartKey ctxKey = "operation_start"
+	userIDKey         ctxKey = "user_id"
+	requestIDKey      ctxKey = "request_id"
+)
+
+// WithEventName returns a copy of ctx carrying the given event name
+func WithEventName(ctx context.Context, eventName string) context.Context {
+	return context.WithValue(ctx, eventNameKey, eventName)
+}
+
+// EventNameFromContext returns the event name stored in ctx, if any
+func EventNameFromContext(ctx context.Context) (string, bool) {
+	eventName, ok := ctx.Value(eventNameKey).(string)
+	return eventName, ok
+}
+
+// WithOperationStart returns a copy of ctx carrying the time an operation started
+func WithOperationStart(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, operationStartKey, start)
+}
+
+// OperationStartFromContext returns the operation start time stored in ctx, if any
+func OperationStartFromContext(ctx context.Context) (time.Time, bool) {
+	start, ok := ctx.Value(operationStartKey).(time.Time)
+	return start, ok
+}
+
+// WithUserID returns a copy of ctx carrying the authenticated user's ID
+func WithUserID(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserIDFromContext returns the authenticated user's ID stored in ctx, if any
+func UserIDFromContext(ctx context.Context) (uint, bool) {
+	userID, ok := ctx.Value(userIDKey).(uint)
+	return userID, ok
+}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request being handled
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, if any
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey).(string)
+	return requestID, ok
+}
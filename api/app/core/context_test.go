@@ -0,0 +1,77 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// captureLog points the global logger at a buffer of JSON entries
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var output bytes.Buffer
+	previous := LoggerInstance
+	SetLogger(NewLogger(&output, LogLevelDebug, true))
+	t.Cleanup(func() { SetLogger(previous) })
+	return &output
+}
+
+// logEntries decodes every JSON entry written to output
+func logEntries(t *testing.T, output *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		entry := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log entry %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// findContext returns the context fields of the first entry with message
+func findContext(t *testing.T, output *bytes.Buffer, message string) map[string]interface{} {
+	t.Helper()
+	for _, entry := range logEntries(t, output) {
+		if entry["message"] == message {
+			fields, _ := entry["context"].(map[string]interface{})
+			return fields
+		}
+	}
+	t.Fatalf("expected a %q entry, got %q", message, output.String())
+	return nil
+}
+
+type namedEvent struct{ name string }
+
+func (e namedEvent) GetEventName() string { return e.name }
+
+type failingListener struct{}
+
+func (failingListener) Handle(mailService interface{}) error { return errors.New("listener broke") }
+
+func TestDispatchSyncLogsWithEventContext(t *testing.T) {
+	previous := GlobalRegistry
+	InitializeRegistry()
+	defer func() { GlobalRegistry = previous }()
+	output := captureLog(t)
+
+	GlobalRegistry.RegisterListener("orders.placed", func(EventInterface) ListenerInterface { return failingListener{} })
+	if err := NewEventDispatcher().DispatchSync(namedEvent{"orders.placed"}); err == nil {
+		t.Fatal("expected the listener failure to be returned")
+	}
+
+	fields := findContext(t, output, "Event listener failed")
+	if fields["event"] != "orders.placed" {
+		t.Fatalf("expected the entry to name the event, got %v", fields)
+	}
+	if _, ok := fields["elapsed_ms"]; !ok {
+		t.Fatalf("expected the entry to carry the elapsed time, got %v", fields)
+	}
+}
@@ -12,6 +12,7 @@ type DatabaseInterface interface {
 	Find(dest interface{}, conds ...interface{}) error
 	Save(value interface{}) error
 	Delete(value interface{}, conds ...interface{}) error
+	Count(count *int64) error
 
 	// Query builder
 	Table(tableName string) DatabaseInterface
@@ -71,6 +72,10 @@ func (d *DatabaseProvider) Delete(value interface{}, conds ...interface{}) error
 	return d.db.Delete(value, conds...).Error
 }
 
+func (d *DatabaseProvider) Count(count *int64) error {
+	return d.db.Count(count).Error
+}
+
 // Query builder methods that are used by the facade
 func (d *DatabaseProvider) Table(tableName string) DatabaseInterface {
 	return &DatabaseProvider{db: d.db.Table(tableName)}
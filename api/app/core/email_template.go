@@ -2,14 +2,23 @@ package core
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"html/template"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
+// ErrTemplateNotFound is returned when no template file exists for a name
+var ErrTemplateNotFound = errors.New("email template not found")
+
+// partialsDir is the template subdirectory whose files are available to
+// every template through {{template "name" .}}
+const partialsDir = "partials"
+
 // EmailTemplateData represents the data structure for email templates
 type EmailTemplateData struct {
 	Subject        string
@@ -18,6 +27,8 @@ type EmailTemplateData struct {
 	RecipientEmail string
 	User           interface{}
 	LoginURL       string
+	// Data carries template-specific values, available as {{.Data.key}}
+	Data map[string]interface{}
 	// Add more fields as needed for different email types
 }
 
@@ -25,6 +36,7 @@ type EmailTemplateData struct {
 type EmailTemplateEngine struct {
 	templateDir string
 	templates   map[string]*template.Template
+	mutex       sync.RWMutex
 }
 
 // NewEmailTemplateEngine creates a new email template engine
@@ -40,7 +52,7 @@ func (e *EmailTemplateEngine) Render(templateName string, data EmailTemplateData
 	// Get or load template
 	tmpl, err := e.getTemplate(templateName)
 	if err != nil {
-		return "", fmt.Errorf("failed to get template %s: %v", templateName, err)
+		return "", fmt.Errorf("failed to get template %s: %w", templateName, err)
 	}
 
 	// Set default values
@@ -63,10 +75,19 @@ func (e *EmailTemplateEngine) Render(templateName string, data EmailTemplateData
 // getTemplate loads and caches a template
 func (e *EmailTemplateEngine) getTemplate(templateName string) (*template.Template, error) {
 	// Check if template is already cached
-	if tmpl, exists := e.templates[templateName]; exists {
+	e.mutex.RLock()
+	tmpl, exists := e.templates[templateName]
+	e.mutex.RUnlock()
+	if exists {
 		return tmpl, nil
 	}
 
+	// Load specific template
+	specificTemplatePath := filepath.Join(e.templateDir, templateName+".html")
+	if _, err := os.Stat(specificTemplatePath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, templateName)
+	}
+
 	// Load base template
 	baseTemplatePath := filepath.Join(e.templateDir, "base.html")
 	baseTemplate, err := template.ParseFiles(baseTemplatePath)
@@ -74,20 +95,27 @@ func (e *EmailTemplateEngine) getTemplate(templateName string) (*template.Templa
 		return nil, fmt.Errorf("failed to parse base template: %v", err)
 	}
 
-	// Load specific template
-	specificTemplatePath := filepath.Join(e.templateDir, templateName+".html")
-	if _, err := os.Stat(specificTemplatePath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("template %s not found", templateName)
+	// Load partials shared by every template
+	partials, err := filepath.Glob(filepath.Join(e.templateDir, partialsDir, "*.html"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partials: %v", err)
+	}
+	if len(partials) > 0 {
+		if baseTemplate, err = baseTemplate.ParseFiles(partials...); err != nil {
+			return nil, fmt.Errorf("failed to parse partials: %v", err)
+		}
 	}
 
 	// Parse specific template into base template
-	tmpl, err := baseTemplate.ParseFiles(specificTemplatePath)
+	tmpl, err = baseTemplate.ParseFiles(specificTemplatePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse template %s: %v", templateName, err)
 	}
 
 	// Cache the template
+	e.mutex.Lock()
 	e.templates[templateName] = tmpl
+	e.mutex.Unlock()
 
 	return tmpl, nil
 }
@@ -100,6 +128,11 @@ func (e *EmailTemplateEngine) PreloadTemplates() error {
 			return err
 		}
 
+		// Partials are only rendered through other templates
+		if info.IsDir() && info.Name() == partialsDir {
+			return filepath.SkipDir
+		}
+
 		// Skip directories, base template, and non-HTML files
 		if info.IsDir() ||
 			filepath.Base(path) == "base.html" ||
@@ -114,7 +147,7 @@ func (e *EmailTemplateEngine) PreloadTemplates() error {
 		}
 
 		// Remove .html extension to get template name
-		templateName := relPath[:len(relPath)-5] // Remove .html
+		templateName := filepath.ToSlash(relPath[:len(relPath)-5]) // Remove .html
 
 		// Load template
 		_, err = e.getTemplate(templateName)
@@ -133,7 +166,7 @@ var EmailTemplateEngineInstance *EmailTemplateEngine
 
 // InitializeEmailTemplateEngine initializes the global email template engine
 func InitializeEmailTemplateEngine() error {
-	templateDir := "views/templates/mail"
+	templateDir := GetString("mail.template_path", "views/templates/mail")
 	EmailTemplateEngineInstance = NewEmailTemplateEngine(templateDir)
 
 	// Preload all templates
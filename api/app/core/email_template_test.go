@@ -0,0 +1,96 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// templateDir writes a layout, a shared partial and a nested template
+func templateDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"base.html":            `<h1>{{.AppName}}</h1>{{template "content" .}}<footer>{{.Year}}</footer>`,
+		"partials/button.html": `{{define "button"}}<a href="{{.url}}">{{.label}}</a>{{end}}`,
+		"orders/shipped.html": `{{define "content"}}<p>Hi {{.User.Name}}, order {{.Data.order.id}} shipped:</p>` +
+			`<ul>{{range .Data.order.items}}<li>{{.}}</li>{{end}}</ul>{{template "button" .Data.action}}{{end}}`,
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		writeConfigFile(t, path, contents)
+	}
+	return dir
+}
+
+func shippedData() EmailTemplateData {
+	return EmailTemplateData{
+		AppName: "Shop",
+		Year:    2026,
+		User:    struct{ Name string }{"Ann"},
+		Data: map[string]interface{}{
+			"order":  map[string]interface{}{"id": 42, "items": []string{"Book", "<Pen>"}},
+			"action": map[string]string{"url": "https://example.com/orders/42", "label": "Track"},
+		},
+	}
+}
+
+func TestRenderTemplateWithNestedDataAndPartials(t *testing.T) {
+	engine := NewEmailTemplateEngine(templateDir(t))
+
+	html, err := engine.Render("orders/shipped", shippedData())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<h1>Shop</h1><p>Hi Ann, order 42 shipped:</p><ul><li>Book</li><li>&lt;Pen&gt;</li></ul>` +
+		`<a href="https://example.com/orders/42">Track</a><footer>2026</footer>`
+	if html != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, html)
+	}
+}
+
+func TestRenderMissingTemplateReturnsNotFound(t *testing.T) {
+	engine := NewEmailTemplateEngine(templateDir(t))
+
+	_, err := engine.Render("orders/refunded", EmailTemplateData{})
+	if !errors.Is(err, ErrTemplateNotFound) || !strings.Contains(err.Error(), "orders/refunded") {
+		t.Fatalf("expected a not found error naming the template, got %v", err)
+	}
+}
+
+func TestPreloadTemplatesCachesEveryTemplate(t *testing.T) {
+	dir := templateDir(t)
+	engine := NewEmailTemplateEngine(dir)
+	if err := engine.PreloadTemplates(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Partials and the layout are not templates of their own
+	if len(engine.templates) != 1 || engine.templates["orders/shipped"] == nil {
+		t.Fatalf("expected only orders/shipped to be cached, got %v", engine.templates)
+	}
+
+	// Cached templates are rendered without reading the files again
+	if err := os.Remove(filepath.Join(dir, "orders", "shipped.html")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.Render("orders/shipped", shippedData()); err != nil {
+		t.Fatalf("expected the cached template to render, got %v", err)
+	}
+}
+
+func TestPreloadTemplatesParsesTheMailViews(t *testing.T) {
+	engine := NewEmailTemplateEngine(filepath.Join("..", "..", "views", "templates", "mail"))
+	if err := engine.PreloadTemplates(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := engine.templates["auth/welcome"]; !ok {
+		t.Fatal("expected auth/welcome to be preloaded")
+	}
+}
@@ -2,8 +2,10 @@ package core
 
 import (
 	"base_lara_go_project/config"
-	"encoding/json"
+	"context"
+	"fmt"
 	"log"
+	"time"
 )
 
 // EventDispatcherService defines the interface for event dispatching operations
@@ -24,15 +26,8 @@ func NewEventDispatcherProvider() *EventDispatcherProvider {
 
 // DispatchAsync dispatches an event asynchronously via queue
 func (d *EventDispatcherProvider) DispatchAsync(event EventInterface) error {
-	// Queue the event for async processing
-	eventData := map[string]interface{}{
-		"job_type":  "event",
-		"eventName": event.GetEventName(),
-		"event":     event,
-	}
-
-	// Serialize event data to JSON
-	jsonData, err := json.Marshal(eventData)
+	// Serialize the event with its dispatch metadata
+	jsonData, err := MarshalEvent(event)
 	if err != nil {
 		log.Printf("Error marshaling event data: %v", err)
 		return err
@@ -72,25 +67,66 @@ func NewEventDispatcher() *EventDispatcher {
 	return &EventDispatcher{}
 }
 
-// Register registers an event handler
-func (d *EventDispatcher) Register(eventName string, handlerFactory func(EventInterface) ListenerInterface) {
-	GlobalRegistry.RegisterListener(eventName, handlerFactory)
+// Register registers an event handler, returning a handle to remove it with
+func (d *EventDispatcher) Register(eventName string, handlerFactory func(EventInterface) ListenerInterface) ListenerHandle {
+	return GlobalRegistry.RegisterListener(eventName, handlerFactory)
+}
+
+// Unregister removes the handler registration the handle was returned for
+func (d *EventDispatcher) Unregister(handle ListenerHandle) bool {
+	return GlobalRegistry.RemoveListenerByHandle(handle)
 }
 
 // DispatchSync dispatches an event to all its handlers (SYNCHRONOUS - immediate)
 func (d *EventDispatcher) DispatchSync(event EventInterface) error {
 	eventName := event.GetEventName()
+	ctx := WithOperationStart(WithEventName(context.Background(), eventName), time.Now())
 
 	handlers := GlobalRegistry.GetListeners(eventName)
 	for _, handlerFactory := range handlers {
 		handler := handlerFactory(event)
 		if err := handler.Handle(GetMailService()); err != nil {
+			LoggerInstance.WithContext(ctx).Error("Event listener failed", map[string]interface{}{
+				"listener": fmt.Sprintf("%T", handler),
+				"error":    err.Error(),
+			})
 			return err
 		}
 	}
+
+	LoggerInstance.WithContext(ctx).Debug("Event dispatched", map[string]interface{}{"listeners": len(handlers)})
 	return nil
 }
 
+// DispatchIdempotent dispatches an event synchronously unless an event with
+// the same name and ID was already dispatched within window, by this or any
+// other process sharing the cache. It reports whether the event was
+// dispatched. A failed dispatch releases its claim so a retry can run.
+func (d *EventDispatcher) DispatchIdempotent(event IdentifiableEvent, window time.Duration) (bool, error) {
+	locker, ok := CacheInstance.(interface {
+		Lock(key string, ttl time.Duration) (string, bool, error)
+		Unlock(key string, owner string) error
+	})
+	if !ok {
+		return false, fmt.Errorf("cache driver does not support the atomic claims needed to deduplicate events")
+	}
+
+	key := fmt.Sprintf("events:dispatched:%s:%s", event.GetEventName(), event.GetEventID())
+	owner, claimed, err := locker.Lock(key, window)
+	if err != nil {
+		return false, err
+	}
+	if !claimed {
+		return false, nil
+	}
+
+	if err := d.DispatchSync(event); err != nil {
+		locker.Unlock(key, owner)
+		return false, err
+	}
+	return true, nil
+}
+
 // MailServiceAdapter adapts the mail provider to the listener interface
 type MailServiceAdapter struct{}
 
@@ -136,6 +172,11 @@ func DispatchEventSync(event EventInterface) error {
 	return EventDispatcherServiceInstance.DispatchSync(event)
 }
 
+// DispatchEventIdempotent dispatches an event synchronously at most once per window
+func DispatchEventIdempotent(event IdentifiableEvent, window time.Duration) (bool, error) {
+	return EventDispatcherInstance.DispatchIdempotent(event, window)
+}
+
 // InitializeEventDispatcher initializes the event dispatcher
 func InitializeEventDispatcher() {
 	EventDispatcherInstance = NewEventDispatcher()
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// orderPlaced is an identifiable event for dispatch tests
+type orderPlaced struct {
+	ID string
+}
+
+func (e orderPlaced) GetEventName() string { return "orders.placed" }
+func (e orderPlaced) GetEventID() string   { return e.ID }
+
+// handlerFunc adapts a func to ListenerInterface
+type handlerFunc func() error
+
+func (f handlerFunc) Handle(mailService interface{}) error { return f() }
+
+// withIdempotentDispatch gives the test a fresh listener registry and an
+// array cache, returning a count of listener runs. The listener fails while
+// fail is set.
+func withIdempotentDispatch(t *testing.T, fail *bool) *int {
+	t.Helper()
+	previousRegistry, previousCache := GlobalRegistry, CacheInstance
+	InitializeRegistry()
+	CacheInstance = NewArrayCacheDriver("test", time.Minute)
+	captureLog(t)
+	t.Cleanup(func() { GlobalRegistry, CacheInstance = previousRegistry, previousCache })
+
+	runs := 0
+	GlobalRegistry.RegisterListener("orders.placed", func(EventInterface) ListenerInterface {
+		return handlerFunc(func() error {
+			runs++
+			if *fail {
+				return errors.New("listener broke")
+			}
+			return nil
+		})
+	})
+	return &runs
+}
+
+func TestDispatchIdempotentRunsListenerOnceWithinWindow(t *testing.T) {
+	fail := false
+	runs := withIdempotentDispatch(t, &fail)
+	dispatcher := NewEventDispatcher()
+
+	for i, want := range []bool{true, false} {
+		dispatched, err := dispatcher.DispatchIdempotent(orderPlaced{ID: "order-1"}, time.Minute)
+		if err != nil || dispatched != want {
+			t.Fatalf("dispatch %d: expected dispatched=%v, got %v, %v", i+1, want, dispatched, err)
+		}
+	}
+	if *runs != 1 {
+		t.Fatalf("expected the listener to run once, ran %d times", *runs)
+	}
+
+	if dispatched, _ := dispatcher.DispatchIdempotent(orderPlaced{ID: "order-2"}, time.Minute); !dispatched || *runs != 2 {
+		t.Fatalf("expected another ID to be dispatched, got %v after %d runs", dispatched, *runs)
+	}
+}
+
+func TestDispatchIdempotentRunsAgainAfterWindow(t *testing.T) {
+	fail := false
+	runs := withIdempotentDispatch(t, &fail)
+	dispatcher := NewEventDispatcher()
+
+	dispatcher.DispatchIdempotent(orderPlaced{ID: "order-1"}, 20*time.Millisecond)
+	time.Sleep(40 * time.Millisecond)
+	if dispatched, err := dispatcher.DispatchIdempotent(orderPlaced{ID: "order-1"}, 20*time.Millisecond); !dispatched || err != nil {
+		t.Fatalf("expected the event to dispatch once the window passed, got %v, %v", dispatched, err)
+	}
+	if *runs != 2 {
+		t.Fatalf("expected two runs, got %d", *runs)
+	}
+}
+
+func TestDispatchIdempotentReleasesClaimOnFailure(t *testing.T) {
+	fail := true
+	runs := withIdempotentDispatch(t, &fail)
+	dispatcher := NewEventDispatcher()
+
+	if dispatched, err := dispatcher.DispatchIdempotent(orderPlaced{ID: "order-1"}, time.Minute); dispatched || err == nil {
+		t.Fatalf("expected the failure to be returned, got %v, %v", dispatched, err)
+	}
+
+	fail = false
+	if dispatched, err := dispatcher.DispatchIdempotent(orderPlaced{ID: "order-1"}, time.Minute); !dispatched || err != nil {
+		t.Fatalf("expected the retry to dispatch, got %v, %v", dispatched, err)
+	}
+	if *runs != 2 {
+		t.Fatalf("expected the listener to run for the retry, got %d runs", *runs)
+	}
+}
@@ -1,20 +1,126 @@
 package core
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"reflect"
+	"time"
 )
 
 type EventFactory func(data map[string]interface{}) (EventInterface, error)
 
 var eventRegistry = map[string]EventFactory{}
 
+// eventTypes maps event names to the concrete types their payloads decode into
+var eventTypes = map[string]reflect.Type{}
+
 func RegisterEventFactory(eventName string, factory EventFactory) {
 	eventRegistry[eventName] = factory
 }
 
+// RegisterEventType registers an event's concrete type so queued payloads can
+// be decoded into it without a hand-written factory
+func RegisterEventType(prototype EventInterface) {
+	eventType := reflect.TypeOf(prototype)
+	if eventType.Kind() == reflect.Ptr {
+		eventType = eventType.Elem()
+	}
+	eventTypes[prototype.GetEventName()] = eventType
+}
+
 func CreateEvent(eventName string, data map[string]interface{}) (EventInterface, error) {
 	if factory, ok := eventRegistry[eventName]; ok {
 		return factory(data)
 	}
+	if _, ok := eventTypes[eventName]; ok {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		return decodeEventPayload(eventName, payload)
+	}
 	return nil, fmt.Errorf("no factory registered for event: %s", eventName)
 }
+
+// eventEnvelope is the wire format of a queued event. The job_type, eventName
+// and event keys are what the event job processor has always read.
+type eventEnvelope struct {
+	JobType      string          `json:"job_type"`
+	EventName    string          `json:"eventName"`
+	Event        json.RawMessage `json:"event"`
+	DispatchedAt time.Time       `json:"dispatched_at"`
+	Source       string          `json:"source,omitempty"`
+}
+
+// QueuedEvent is an event read back from the queue with its dispatch metadata
+type QueuedEvent struct {
+	Event        EventInterface
+	DispatchedAt time.Time
+	Source       string
+}
+
+// MarshalEvent serializes an event for dispatch to another process, stamping
+// it with the dispatch time and the dispatching host
+func MarshalEvent(event EventInterface) ([]byte, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event %s: %w", event.GetEventName(), err)
+	}
+
+	source, _ := os.Hostname()
+	return json.Marshal(eventEnvelope{
+		JobType:      "event",
+		EventName:    event.GetEventName(),
+		Event:        payload,
+		DispatchedAt: time.Now().UTC(),
+		Source:       source,
+	})
+}
+
+// UnmarshalEvent rebuilds an event serialized by MarshalEvent, using the type
+// registered with RegisterEventType or else the event's factory
+func UnmarshalEvent(data []byte) (*QueuedEvent, error) {
+	var envelope eventEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode event envelope: %w", err)
+	}
+	if envelope.EventName == "" {
+		return nil, fmt.Errorf("event envelope has no event name")
+	}
+
+	var event EventInterface
+	var err error
+	if _, ok := eventTypes[envelope.EventName]; ok {
+		event, err = decodeEventPayload(envelope.EventName, envelope.Event)
+	} else {
+		var payload map[string]interface{}
+		if err := json.Unmarshal(envelope.Event, &payload); err != nil {
+			return nil, fmt.Errorf("failed to decode event %s: %w", envelope.EventName, err)
+		}
+		event, err = CreateEvent(envelope.EventName, payload)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueuedEvent{
+		Event:        event,
+		DispatchedAt: envelope.DispatchedAt,
+		Source:       envelope.Source,
+	}, nil
+}
+
+// decodeEventPayload decodes a payload into a new instance of a registered event type
+func decodeEventPayload(eventName string, payload []byte) (EventInterface, error) {
+	value := reflect.New(eventTypes[eventName])
+	if err := json.Unmarshal(payload, value.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to decode event %s: %w", eventName, err)
+	}
+
+	event, ok := value.Interface().(EventInterface)
+	if !ok {
+		return nil, fmt.Errorf("registered type for event %s does not implement EventInterface", eventName)
+	}
+	return event, nil
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// accountOpened is an event decoded through its registered type
+type accountOpened struct {
+	AccountID uint              `json:"account_id"`
+	Owner     accountOwner      `json:"owner"`
+	Tags      []string          `json:"tags"`
+	Meta      map[string]string `json:"meta"`
+}
+
+type accountOwner struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func (e *accountOpened) GetEventName() string { return "accounts.opened" }
+
+// accountClosed is an event decoded through its registered factory
+type accountClosed struct {
+	AccountID string
+	Reason    string
+}
+
+func (e accountClosed) GetEventName() string { return "accounts.closed" }
+
+func TestMarshalEventRoundTripsRegisteredType(t *testing.T) {
+	RegisterEventType(&accountOpened{})
+	t.Cleanup(func() { delete(eventTypes, "accounts.opened") })
+
+	event := &accountOpened{
+		AccountID: 42,
+		Owner:     accountOwner{Name: "Ada", Email: "ada@example.com"},
+		Tags:      []string{"premium"},
+		Meta:      map[string]string{"plan": "yearly"},
+	}
+
+	before := time.Now().UTC()
+	data, err := MarshalEvent(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	queued, err := UnmarshalEvent(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(queued.Event, event) {
+		t.Fatalf("expected %+v, got %+v", event, queued.Event)
+	}
+	if queued.DispatchedAt.Before(before.Add(-time.Second)) || queued.DispatchedAt.After(time.Now().Add(time.Second)) {
+		t.Fatalf("expected the dispatch time to be stamped, got %v", queued.DispatchedAt)
+	}
+}
+
+func TestMarshalEventRoundTripsThroughFactory(t *testing.T) {
+	RegisterEventFactory("accounts.closed", func(data map[string]interface{}) (EventInterface, error) {
+		return accountClosed{
+			AccountID: fmt.Sprint(data["AccountID"]),
+			Reason:    fmt.Sprint(data["Reason"]),
+		}, nil
+	})
+	t.Cleanup(func() { delete(eventRegistry, "accounts.closed") })
+
+	var event EventInterface = accountClosed{AccountID: "acc-1", Reason: "requested"}
+	data, err := MarshalEvent(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	queued, err := UnmarshalEvent(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if queued.Event != event {
+		t.Fatalf("expected %+v, got %+v", event, queued.Event)
+	}
+}
+
+func TestUnmarshalEventRejectsUnknownEvent(t *testing.T) {
+	data, err := MarshalEvent(accountClosed{AccountID: "acc-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UnmarshalEvent(data); err == nil {
+		t.Fatal("expected an event without a type or factory to be rejected")
+	}
+}
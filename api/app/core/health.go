@@ -0,0 +1,145 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Health statuses reported for components and the overall report
+const (
+	HealthStatusUp       = "up"
+	HealthStatusDown     = "down"
+	HealthStatusDegraded = "degraded"
+)
+
+// HealthCheck reports whether a component is reachable, returning early if ctx expires
+type HealthCheck func(ctx context.Context) error
+
+// ComponentHealth is the result of checking a single component
+type ComponentHealth struct {
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// HealthReport summarizes the health of every registered component. Status is
+// down if a critical component is down and degraded if only informational ones are.
+type HealthReport struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentHealth `json:"components"`
+}
+
+// Healthy reports whether every critical component is up
+func (r HealthReport) Healthy() bool {
+	return r.Status != HealthStatusDown
+}
+
+// healthComponent is a registered health check
+type healthComponent struct {
+	check    HealthCheck
+	critical bool
+}
+
+// HealthRegistry runs connectivity checks against registered components
+type HealthRegistry struct {
+	components map[string]healthComponent
+	mutex      sync.RWMutex
+}
+
+// NewHealthRegistry creates a new health registry
+func NewHealthRegistry() *HealthRegistry {
+	return &HealthRegistry{
+		components: make(map[string]healthComponent),
+	}
+}
+
+// Register adds a component check. A critical component being down marks the
+// whole application down; an informational one only degrades it.
+func (r *HealthRegistry) Register(name string, critical bool, check HealthCheck) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.components[name] = healthComponent{check: check, critical: critical}
+}
+
+// Check runs every component check concurrently, giving each up to timeout
+func (r *HealthRegistry) Check(ctx context.Context, timeout time.Duration) HealthReport {
+	r.mutex.RLock()
+	names := make([]string, 0, len(r.components))
+	components := make(map[string]healthComponent, len(r.components))
+	for name, component := range r.components {
+		names = append(names, name)
+		components[name] = component
+	}
+	r.mutex.RUnlock()
+	sort.Strings(names)
+
+	results := make([]ComponentHealth, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, component healthComponent) {
+			defer wg.Done()
+			results[i] = runHealthCheck(ctx, timeout, component)
+		}(i, components[name])
+	}
+	wg.Wait()
+
+	report := HealthReport{
+		Status:     HealthStatusUp,
+		Components: make(map[string]ComponentHealth, len(names)),
+	}
+	for i, name := range names {
+		result := results[i]
+		report.Components[name] = result
+		if result.Status == HealthStatusUp {
+			continue
+		}
+		if result.Critical {
+			report.Status = HealthStatusDown
+		} else if report.Status == HealthStatusUp {
+			report.Status = HealthStatusDegraded
+		}
+	}
+	return report
+}
+
+// runHealthCheck runs a single check bounded by timeout
+func runHealthCheck(ctx context.Context, timeout time.Duration, component healthComponent) ComponentHealth {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- component.check(checkCtx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-checkCtx.Done():
+		err = checkCtx.Err()
+	}
+
+	result := ComponentHealth{
+		Status:    HealthStatusUp,
+		Critical:  component.critical,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = HealthStatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Global health registry instance
+var HealthInstance = NewHealthRegistry()
+
+// RegisterHealthCheck registers a component with the global health registry
+func RegisterHealthCheck(name string, critical bool, check HealthCheck) {
+	HealthInstance.Register(name, critical, check)
+}
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HTTPClientConfig holds the settings for an outbound HTTP client
+type HTTPClientConfig struct {
+	BaseURL    string
+	Timeout    time.Duration
+	Retries    int
+	RetryDelay time.Duration
+	Headers    map[string]string
+}
+
+// HTTPResponse is the result of an HTTP request with the body already read
+type HTTPResponse struct {
+	StatusCode int
+	Headers    http.Header
+	Body       []byte
+}
+
+// HTTPStatusError is returned when the final attempt gets a 5xx response
+type HTTPStatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("%s %s returned status %d", e.Method, e.URL, e.StatusCode)
+}
+
+// idempotentMethods are the methods that are safe to retry
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// HTTPClient sends requests over a reusable transport, retrying idempotent
+// requests with exponential backoff on connection errors and 5xx responses
+type HTTPClient struct {
+	config HTTPClientConfig
+	client *http.Client
+	mutex  sync.RWMutex
+}
+
+// NewHTTPClient creates a new HTTP client
+func NewHTTPClient(config HTTPClientConfig) *HTTPClient {
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = 100 * time.Millisecond
+	}
+
+	return &HTTPClient{
+		config: config,
+		client: &http.Client{
+			Timeout:   config.Timeout,
+			Transport: http.DefaultTransport.(*http.Transport).Clone(),
+		},
+	}
+}
+
+// SetTimeout sets the per-attempt timeout. The *http.Client is replaced
+// rather than modified, since requests in flight read its fields without
+// holding the lock; the new client shares the transport and its connections.
+func (c *HTTPClient) SetTimeout(timeout time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.config.Timeout = timeout
+	c.client = &http.Client{
+		Timeout:   timeout,
+		Transport: c.client.Transport,
+	}
+}
+
+// SetRetries sets how many times a failed idempotent request is retried
+func (c *HTTPClient) SetRetries(retries int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.config.Retries = retries
+}
+
+// Get sends a GET request
+func (c *HTTPClient) Get(ctx context.Context, url string, headers ...map[string]string) (*HTTPResponse, error) {
+	return c.Request(ctx, http.MethodGet, url, nil, headers...)
+}
+
+// Post sends a POST request. POST is never retried.
+func (c *HTTPClient) Post(ctx context.Context, url string, body []byte, headers ...map[string]string) (*HTTPResponse, error) {
+	return c.Request(ctx, http.MethodPost, url, body, headers...)
+}
+
+// Put sends a PUT request
+func (c *HTTPClient) Put(ctx context.Context, url string, body []byte, headers ...map[string]string) (*HTTPResponse, error) {
+	return c.Request(ctx, http.MethodPut, url, body, headers...)
+}
+
+// Delete sends a DELETE request
+func (c *HTTPClient) Delete(ctx context.Context, url string, headers ...map[string]string) (*HTTPResponse, error) {
+	return c.Request(ctx, http.MethodDelete, url, nil, headers...)
+}
+
+// Request sends a request, retrying idempotent methods on failure
+func (c *HTTPClient) Request(ctx context.Context, method, url string, body []byte, headers ...map[string]string) (*HTTPResponse, error) {
+	c.mutex.RLock()
+	config := c.config
+	client := c.client
+	c.mutex.RUnlock()
+
+	if config.BaseURL != "" && !strings.Contains(url, "://") {
+		url = strings.TrimRight(config.BaseURL, "/") + "/" + strings.TrimLeft(url, "/")
+	}
+
+	attempts := 1
+	if idempotentMethods[method] && config.Retries > 0 {
+		attempts += config.Retries
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			delay := config.RetryDelay << (attempt - 1)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return nil, fmt.Errorf("%s %s: %w (last error: %v)", method, url, ctx.Err(), lastErr)
+			}
+		}
+
+		response, err := sendAttempt(ctx, client, method, url, body, config.Headers, headers)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if response.StatusCode >= 500 {
+			lastErr = &HTTPStatusError{Method: method, URL: url, StatusCode: response.StatusCode}
+			if attempt < attempts-1 {
+				continue
+			}
+			return response, lastErr
+		}
+		return response, nil
+	}
+
+	return nil, lastErr
+}
+
+// sendAttempt performs a single attempt and reads the whole response body
+func sendAttempt(ctx context.Context, client *http.Client, method, url string, body []byte, defaults map[string]string, headers []map[string]string) (*HTTPResponse, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	for key, value := range defaults {
+		request.Header.Set(key, value)
+	}
+	for _, set := range headers {
+		for key, value := range set {
+			request.Header.Set(key, value)
+		}
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, url, err)
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: failed to read response: %w", method, url, err)
+	}
+
+	return &HTTPResponse{
+		StatusCode: response.StatusCode,
+		Headers:    response.Header,
+		Body:       responseBody,
+	}, nil
+}
+
+// Close releases idle connections held by the client's transport
+func (c *HTTPClient) Close() {
+	c.mutex.RLock()
+	client := c.client
+	c.mutex.RUnlock()
+	client.CloseIdleConnections()
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHTTPClientSetTimeoutDuringRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(HTTPClientConfig{BaseURL: server.URL})
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Get(context.Background(), "/"); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			client.SetTimeout(time.Duration(i+1) * time.Second)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestHTTPClientSetTimeoutAppliesToLaterRequests(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewHTTPClient(HTTPClientConfig{BaseURL: server.URL})
+	defer client.Close()
+	transport := client.client.Transport
+
+	client.SetTimeout(20 * time.Millisecond)
+	if client.client.Transport != transport {
+		t.Fatal("expected the new client to keep the pooled transport")
+	}
+
+	started := time.Now()
+	if _, err := client.Get(context.Background(), "/"); err == nil {
+		t.Fatal("expected the request to time out")
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Fatalf("expected the new timeout to apply, took %v", elapsed)
+	}
+}
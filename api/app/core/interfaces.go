@@ -5,6 +5,13 @@ type EventInterface interface {
 	GetEventName() string
 }
 
+// IdentifiableEvent is implemented by events that carry a unique ID, which
+// lets them be deduplicated across workers
+type IdentifiableEvent interface {
+	EventInterface
+	GetEventID() string
+}
+
 // ListenerInterface defines the interface for all listeners
 type ListenerInterface interface {
 	Handle(mailService interface{}) error
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// chainJobType is the job_type of a message carrying a job chain
+const chainJobType = "chain"
+
+// ChainLink is one job in a chain, processed by the processor for JobType
+type ChainLink struct {
+	JobType string          `json:"job_type"`
+	Queue   string          `json:"queue"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// NewChainLink marshals job into a link run by the processor for jobType on queueName
+func NewChainLink(jobType string, job interface{}, queueName string) (ChainLink, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return ChainLink{}, fmt.Errorf("failed to marshal job data: %v", err)
+	}
+	return ChainLink{JobType: jobType, Queue: queueName, Data: data}, nil
+}
+
+// chainEnvelope is the wire format of a chain: the link to run now followed
+// by the links still waiting on it
+type chainEnvelope struct {
+	Links []ChainLink `json:"links"`
+}
+
+// Chain queues links so each runs only after the previous one succeeded.
+// Only the first link is queued; each following link is queued by the worker
+// once the one before it has been processed, so a link that keeps failing
+// stops the rest of the chain from ever running.
+func (j *JobDispatcherProvider) Chain(links []ChainLink) error {
+	if len(links) == 0 {
+		return errors.New("cannot dispatch an empty job chain")
+	}
+	return j.dispatchChain(links)
+}
+
+// dispatchChain queues the first link of links, carrying the rest along
+func (j *JobDispatcherProvider) dispatchChain(links []ChainLink) error {
+	attributes := map[string]string{
+		"job_type": chainJobType,
+		"queue":    links[0].Queue,
+	}
+	return j.DispatchJobWithAttributes(chainEnvelope{Links: links}, attributes, links[0].Queue)
+}
+
+// processChain runs the current link of a chain and queues the next one
+func (j *JobDispatcherProvider) processChain(jobData []byte) error {
+	var envelope chainEnvelope
+	if err := json.Unmarshal(jobData, &envelope); err != nil {
+		return fmt.Errorf("failed to unmarshal job chain: %v", err)
+	}
+	if len(envelope.Links) == 0 {
+		return errors.New("job chain has no links")
+	}
+
+	current := envelope.Links[0]
+	if current.JobType == chainJobType {
+		return errors.New("job chains cannot be nested")
+	}
+	if err := j.ProcessJobFromQueue(current.Data, current.JobType); err != nil {
+		return fmt.Errorf("chained %s job failed: %w", current.JobType, err)
+	}
+
+	if remaining := envelope.Links[1:]; len(remaining) > 0 {
+		if err := j.dispatchChain(remaining); err != nil {
+			return fmt.Errorf("failed to dispatch next job in chain: %w", err)
+		}
+	}
+	return nil
+}
+
+// DispatchChain queues a job chain with the global job dispatcher
+func DispatchChain(links []ChainLink) error {
+	return JobDispatcherServiceInstance.Chain(links)
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// sentMessages is a queue service keeping every message sent to it
+type sentMessages struct {
+	QueueService
+	bodies     []string
+	attributes []map[string]string
+}
+
+func (s *sentMessages) SendMessageToQueueWithAttributes(messageBody string, attributes map[string]string, queueName string) error {
+	s.bodies = append(s.bodies, messageBody)
+	s.attributes = append(s.attributes, attributes)
+	return nil
+}
+
+// recordingProcessor runs jobs of any type, failing the types in fail
+type recordingProcessor struct {
+	ran  []string
+	fail map[string]bool
+}
+
+func (p *recordingProcessor) CanProcess(jobType string) bool { return true }
+
+func (p *recordingProcessor) Process(jobData []byte) error {
+	var job struct{ Name string }
+	if err := json.Unmarshal(jobData, &job); err != nil {
+		return err
+	}
+	p.ran = append(p.ran, job.Name)
+	if p.fail[job.Name] {
+		return errors.New(job.Name + " failed")
+	}
+	return nil
+}
+
+// runChain dispatches a three link chain and works through its queued
+// messages the way the worker would, stopping at the first failed message
+func runChain(t *testing.T, fail map[string]bool) (*recordingProcessor, *sentMessages, error) {
+	t.Helper()
+	queue := &sentMessages{}
+	withQueueService(t, queue)
+
+	processor := &recordingProcessor{fail: fail}
+	dispatcher := NewJobDispatcherProvider()
+	dispatcher.RegisterJobProcessor(processor)
+
+	var links []ChainLink
+	for _, name := range []string{"first", "second", "third"} {
+		link, err := NewChainLink("step", struct{ Name string }{name}, "jobs")
+		if err != nil {
+			t.Fatal(err)
+		}
+		links = append(links, link)
+	}
+	if err := dispatcher.Chain(links); err != nil {
+		t.Fatal(err)
+	}
+
+	for processed := 0; processed < len(queue.bodies); processed++ {
+		jobType := queue.attributes[processed]["job_type"]
+		if err := dispatcher.ProcessJobFromQueue([]byte(queue.bodies[processed]), jobType); err != nil {
+			return processor, queue, err
+		}
+	}
+	return processor, queue, nil
+}
+
+func TestChainRunsLinksInOrder(t *testing.T) {
+	processor, queue, err := runChain(t, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(processor.ran) != 3 || processor.ran[0] != "first" || processor.ran[1] != "second" || processor.ran[2] != "third" {
+		t.Fatalf("expected every link in order, got %v", processor.ran)
+	}
+	if len(queue.bodies) != 3 {
+		t.Fatalf("expected one message per link, got %d", len(queue.bodies))
+	}
+}
+
+func TestChainFailureStopsLaterLinks(t *testing.T) {
+	processor, queue, err := runChain(t, map[string]bool{"second": true})
+	if err == nil {
+		t.Fatal("expected the failed link to fail its message so it is retried")
+	}
+	if len(processor.ran) != 2 || processor.ran[1] != "second" {
+		t.Fatalf("expected the chain to stop at the second link, got %v", processor.ran)
+	}
+	if len(queue.bodies) != 2 {
+		t.Fatalf("expected the third link never to be queued, got %d messages", len(queue.bodies))
+	}
+}
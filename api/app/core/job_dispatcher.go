@@ -18,6 +18,7 @@ type JobDispatcherService interface {
 	DispatchSync(job JobInterface) (any, error)
 	DispatchJob(job interface{}, queueName string) error
 	DispatchJobWithAttributes(job interface{}, attributes map[string]string, queueName string) error
+	Chain(links []ChainLink) error
 	ProcessJobFromQueue(jobData []byte, jobType string) error
 	RegisterJobProcessor(processor JobProcessor)
 }
@@ -80,6 +81,10 @@ func (j *JobDispatcherProvider) DispatchJobWithAttributes(job interface{}, attri
 func (j *JobDispatcherProvider) ProcessJobFromQueue(jobData []byte, jobType string) error {
 	log.Printf("Processing job of type: %s", jobType)
 
+	if jobType == chainJobType {
+		return j.processChain(jobData)
+	}
+
 	// Try to find a processor for this job type
 	for _, processor := range j.processors {
 		if processor.CanProcess(jobType) {
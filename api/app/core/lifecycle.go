@@ -0,0 +1,205 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Shutdown priorities. Components with a lower priority are shut down first,
+// so intake stops before in-flight work drains and connections close last.
+// Log output is flushed after everything else so shutdown errors are kept.
+const (
+	ShutdownPriorityIntake      = 0
+	ShutdownPriorityDrain       = 100
+	ShutdownPriorityConnections = 200
+	ShutdownPriorityLogging     = 300
+)
+
+// ShutdownGrace is the time each connections or logging band is guaranteed
+// even when earlier bands used up the whole shutdown deadline
+const ShutdownGrace = 2 * time.Second
+
+// minShutdownBand is the least time an intake or drain band gets when the
+// grace reserved for later bands uses up the rest of the shutdown deadline
+const minShutdownBand = 250 * time.Millisecond
+
+// ShutdownFunc releases a resource, returning early if ctx expires
+type ShutdownFunc func(ctx context.Context) error
+
+// lifecycleComponent is a registered resource awaiting shutdown
+type lifecycleComponent struct {
+	name     string
+	priority int
+	shutdown ShutdownFunc
+}
+
+// LifecycleRegistry coordinates ordered shutdown of application resources
+type LifecycleRegistry struct {
+	components   []lifecycleComponent
+	mutex        sync.Mutex
+	shuttingDown atomic.Bool
+}
+
+// NewLifecycleRegistry creates a new lifecycle registry
+func NewLifecycleRegistry() *LifecycleRegistry {
+	return &LifecycleRegistry{
+		components: make([]lifecycleComponent, 0),
+	}
+}
+
+// Register adds a component to be shut down at the given priority.
+// Components sharing a priority shut down in registration order.
+func (r *LifecycleRegistry) Register(name string, priority int, shutdown ShutdownFunc) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.components = append(r.components, lifecycleComponent{
+		name:     name,
+		priority: priority,
+		shutdown: shutdown,
+	})
+}
+
+// ShutdownAll shuts every component down in priority order. Each priority
+// band gets its own deadline: bands before ShutdownPriorityConnections share
+// what is left of ctx's deadline minus ShutdownGrace for every connections
+// and logging band still to come, and those final bands always run with at
+// least ShutdownGrace, even once ctx has expired, so connections are closed
+// and logs flushed after a slow drain. A component that outlives its band's
+// deadline is reported and skipped. The outcome is logged before the logging
+// band runs, so the line isn't lost with the log output. All failures are
+// returned together.
+func (r *LifecycleRegistry) ShutdownAll(ctx context.Context) error {
+	r.shuttingDown.Store(true)
+
+	r.mutex.Lock()
+	components := make([]lifecycleComponent, len(r.components))
+	copy(components, r.components)
+	r.mutex.Unlock()
+
+	sort.SliceStable(components, func(i, j int) bool {
+		return components[i].priority < components[j].priority
+	})
+
+	var errs []error
+	reported := false
+	for start := 0; start < len(components); {
+		end := start
+		for end < len(components) && components[end].priority == components[start].priority {
+			end++
+		}
+
+		if components[start].priority >= ShutdownPriorityLogging && !reported {
+			logShutdownOutcome(errs)
+			reported = true
+		}
+
+		bandCtx, cancel := bandContext(ctx, components[start].priority, criticalBands(components[start:]))
+		for _, component := range components[start:end] {
+			log.Printf("Shutting down %s", component.name)
+			if err := r.shutdownComponent(bandCtx, component); err != nil {
+				log.Printf("Error shutting down %s: %v", component.name, err)
+				errs = append(errs, err)
+			}
+		}
+		cancel()
+		start = end
+	}
+
+	if !reported {
+		logShutdownOutcome(errs)
+	}
+	return errors.Join(errs...)
+}
+
+// logShutdownOutcome reports whether shutdown so far has succeeded
+func logShutdownOutcome(errs []error) {
+	if len(errs) > 0 {
+		log.Printf("Shutdown completed with errors: %v", errors.Join(errs...))
+		return
+	}
+	log.Println("Shutdown completed")
+}
+
+// bandContext returns the context a priority band shuts down with, given how
+// many connections and logging bands remain including this one
+func bandContext(ctx context.Context, priority int, criticalLeft int) (context.Context, context.CancelFunc) {
+	deadline, hasDeadline := ctx.Deadline()
+
+	if priority < ShutdownPriorityConnections {
+		if !hasDeadline {
+			return context.WithCancel(ctx)
+		}
+		// A deadline too short to reserve the grace periods would leave this
+		// band already expired, so it always gets at least minShutdownBand
+		reserved := time.Duration(criticalLeft) * ShutdownGrace
+		return context.WithTimeout(ctx, max(time.Until(deadline)-reserved, minShutdownBand))
+	}
+
+	// Closing connections and flushing logs must run even if the caller's
+	// deadline has passed or it was cancelled
+	detached := context.WithoutCancel(ctx)
+	if !hasDeadline {
+		return context.WithCancel(detached)
+	}
+	return context.WithTimeout(detached, max(time.Until(deadline)/time.Duration(criticalLeft), ShutdownGrace))
+}
+
+// criticalBands counts the distinct connections and logging priorities in
+// the sorted components
+func criticalBands(components []lifecycleComponent) int {
+	count := 0
+	for i, component := range components {
+		if component.priority >= ShutdownPriorityConnections && (i == 0 || components[i-1].priority != component.priority) {
+			count++
+		}
+	}
+	return count
+}
+
+// IsShuttingDown reports whether ShutdownAll has been called
+func (r *LifecycleRegistry) IsShuttingDown() bool {
+	return r.shuttingDown.Load()
+}
+
+// shutdownComponent runs a single shutdown func bounded by ctx
+func (r *LifecycleRegistry) shutdownComponent(ctx context.Context, component lifecycleComponent) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- component.shutdown(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("%s: %w", component.name, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%s: did not shut down before deadline: %w", component.name, ctx.Err())
+	}
+}
+
+// Global lifecycle registry instance
+var LifecycleInstance = NewLifecycleRegistry()
+
+// RegisterShutdown registers a component with the global lifecycle registry
+func RegisterShutdown(name string, priority int, shutdown ShutdownFunc) {
+	LifecycleInstance.Register(name, priority, shutdown)
+}
+
+// ShutdownAll shuts down every component registered with the global lifecycle registry
+func ShutdownAll(ctx context.Context) error {
+	return LifecycleInstance.ShutdownAll(ctx)
+}
+
+// IsShuttingDown reports whether the application has started shutting down
+func IsShuttingDown() bool {
+	return LifecycleInstance.IsShuttingDown()
+}
@@ -0,0 +1,179 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordShutdown returns a shutdown func that appends name to order
+func recordShutdown(mutex *sync.Mutex, order *[]string, name string) ShutdownFunc {
+	return func(ctx context.Context) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		*order = append(*order, name)
+		return nil
+	}
+}
+
+func TestShutdownAllRunsInPriorityOrder(t *testing.T) {
+	registry := NewLifecycleRegistry()
+	var mutex sync.Mutex
+	var order []string
+
+	registry.Register("logging", ShutdownPriorityLogging, recordShutdown(&mutex, &order, "logging"))
+	registry.Register("database", ShutdownPriorityConnections, recordShutdown(&mutex, &order, "database"))
+	registry.Register("worker", ShutdownPriorityDrain, recordShutdown(&mutex, &order, "worker"))
+	registry.Register("http server", ShutdownPriorityIntake, recordShutdown(&mutex, &order, "http server"))
+	registry.Register("cache", ShutdownPriorityConnections, recordShutdown(&mutex, &order, "cache"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := registry.ShutdownAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"http server", "worker", "database", "cache", "logging"}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("expected order %v, got %v", want, order)
+	}
+	if !registry.IsShuttingDown() {
+		t.Fatal("expected the registry to report shutting down")
+	}
+}
+
+func TestShutdownAllBoundsSlowComponentAndStillClosesConnections(t *testing.T) {
+	registry := NewLifecycleRegistry()
+	var mutex sync.Mutex
+	var order []string
+
+	registry.Register("slow drain", ShutdownPriorityDrain, func(ctx context.Context) error {
+		<-time.After(time.Minute)
+		return nil
+	})
+	registry.Register("database", ShutdownPriorityConnections, func(ctx context.Context) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return recordShutdown(&mutex, &order, "database")(ctx)
+	})
+	registry.Register("logging", ShutdownPriorityLogging, recordShutdown(&mutex, &order, "logging"))
+
+	// The whole budget is less than the slow component would take
+	ctx, cancel := context.WithTimeout(context.Background(), 2*ShutdownGrace+200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := registry.ShutdownAll(ctx)
+	elapsed := time.Since(start)
+
+	if err == nil || !strings.Contains(err.Error(), "slow drain: did not shut down before deadline") {
+		t.Fatalf("expected the slow component to be reported, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "database") || strings.Contains(err.Error(), "logging") {
+		t.Fatalf("connections and logging should still shut down cleanly, got %v", err)
+	}
+	if !reflect.DeepEqual(order, []string{"database", "logging"}) {
+		t.Fatalf("expected database and logging to shut down, got %v", order)
+	}
+	if elapsed > time.Second {
+		t.Fatalf("the slow component should only get its share of the budget, took %v", elapsed)
+	}
+}
+
+func TestShutdownAllRunsFinalBandsAfterDeadline(t *testing.T) {
+	registry := NewLifecycleRegistry()
+	var mutex sync.Mutex
+	var order []string
+
+	registry.Register("http server", ShutdownPriorityIntake, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	registry.Register("logging", ShutdownPriorityLogging, recordShutdown(&mutex, &order, "logging"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := registry.ShutdownAll(ctx)
+	if err == nil || !strings.Contains(err.Error(), "http server") {
+		t.Fatalf("expected the intake band to fail on a cancelled context, got %v", err)
+	}
+	if !reflect.DeepEqual(order, []string{"logging"}) {
+		t.Fatalf("expected logging to be flushed regardless, got %v", order)
+	}
+}
+
+func TestShutdownAllLogsOutcomeBeforeLoggingBand(t *testing.T) {
+	var output strings.Builder
+	log.SetOutput(&output)
+	defer log.SetOutput(os.Stderr)
+
+	registry := NewLifecycleRegistry()
+	registry.Register("worker", ShutdownPriorityDrain, func(ctx context.Context) error {
+		return errors.New("stuck job")
+	})
+	var seen string
+	registry.Register("logging", ShutdownPriorityLogging, func(ctx context.Context) error {
+		seen = output.String()
+		log.SetOutput(io.Discard)
+		return nil
+	})
+
+	if err := registry.ShutdownAll(context.Background()); err == nil {
+		t.Fatal("expected the worker failure to be returned")
+	}
+	if !strings.Contains(seen, "Shutdown completed with errors: worker: stuck job") {
+		t.Fatalf("expected the outcome to be logged before the logging band, got %q", seen)
+	}
+}
+
+func TestShutdownAllLogsOutcomeWithoutLoggingBand(t *testing.T) {
+	var output strings.Builder
+	log.SetOutput(&output)
+	defer log.SetOutput(os.Stderr)
+
+	registry := NewLifecycleRegistry()
+	registry.Register("worker", ShutdownPriorityDrain, func(ctx context.Context) error { return nil })
+	if err := registry.ShutdownAll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output.String(), "Shutdown completed\n") {
+		t.Fatalf("expected the outcome to be logged, got %q", output.String())
+	}
+}
+
+func TestShutdownAllGivesEarlyBandsTimeUnderShortDeadline(t *testing.T) {
+	registry := NewLifecycleRegistry()
+	var intakeErr, drainErr error
+	registry.Register("http server", ShutdownPriorityIntake, func(ctx context.Context) error {
+		intakeErr = ctx.Err()
+		return nil
+	})
+	registry.Register("worker", ShutdownPriorityDrain, func(ctx context.Context) error {
+		drainErr = ctx.Err()
+		return nil
+	})
+	registry.Register("database", ShutdownPriorityConnections, func(ctx context.Context) error { return nil })
+	registry.Register("logging", ShutdownPriorityLogging, func(ctx context.Context) error { return nil })
+
+	// Far less than the two grace periods the final bands reserve
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if err := registry.ShutdownAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if intakeErr != nil || drainErr != nil {
+		t.Fatalf("expected early bands to start with time left, got %v and %v", intakeErr, drainErr)
+	}
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// AsyncWriter moves log writes off the caller's goroutine. Entries are queued
+// on a buffered channel and written to the underlying writer in batches,
+// either when the batch grows past batchSize bytes or when the flush
+// interval elapses.
+type AsyncWriter struct {
+	writer    io.Writer
+	entries   chan []byte
+	flushes   chan chan struct{}
+	done      chan struct{}
+	batchSize int
+	interval  time.Duration
+	closed    bool
+	mutex     sync.RWMutex
+}
+
+// NewAsyncWriter starts an async writer in front of writer
+func NewAsyncWriter(writer io.Writer, bufferSize int, batchSize int, interval time.Duration) *AsyncWriter {
+	w := &AsyncWriter{
+		writer:    writer,
+		entries:   make(chan []byte, bufferSize),
+		flushes:   make(chan chan struct{}),
+		done:      make(chan struct{}),
+		batchSize: batchSize,
+		interval:  interval,
+	}
+	go w.run()
+	return w
+}
+
+// Write queues a copy of p. It only blocks when the buffer is full.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	entry := make([]byte, len(p))
+	copy(entry, p)
+	w.entries <- entry
+	return len(p), nil
+}
+
+// Flush blocks until every entry queued before the call has been written
+func (w *AsyncWriter) Flush() {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	if w.closed {
+		return
+	}
+
+	flushed := make(chan struct{})
+	w.flushes <- flushed
+	<-flushed
+}
+
+// Close writes every queued entry, stops the background goroutine and closes
+// the underlying writer if it can be closed
+func (w *AsyncWriter) Close() error {
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.entries)
+	w.mutex.Unlock()
+
+	<-w.done
+
+	if closer, ok := w.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// run batches queued entries and writes them until the queue is closed
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	var batch bytes.Buffer
+	write := func() {
+		if batch.Len() > 0 {
+			w.writer.Write(batch.Bytes())
+			batch.Reset()
+		}
+	}
+
+	for {
+		select {
+		case entry, ok := <-w.entries:
+			if !ok {
+				write()
+				return
+			}
+			batch.Write(entry)
+			if batch.Len() >= w.batchSize {
+				write()
+			}
+		case flushed := <-w.flushes:
+			// Entries queued before Flush was called are already in the channel
+			for drained := false; !drained; {
+				select {
+				case entry := <-w.entries:
+					batch.Write(entry)
+				default:
+					drained = true
+				}
+			}
+			write()
+			close(flushed)
+		case <-ticker.C:
+			write()
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatedTimeFormat is the timestamp appended to rotated log file names. It
+// has nanosecond precision and fixed width so names sort chronologically.
+const rotatedTimeFormat = "2006-01-02T15-04-05.000000000"
+
+// renameFile renames rotated log files; tests replace it to simulate failures
+var renameFile = os.Rename
+
+// RotatingFileWriter writes log output to a file, rotating it once it grows
+// past maxSize bytes or, when daily is set, when the date changes. Only the
+// newest maxFiles rotated files are kept.
+type RotatingFileWriter struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+	daily    bool
+
+	file     *os.File
+	size     int64
+	openedOn string
+	mutex    sync.Mutex
+}
+
+// NewRotatingFileWriter opens (or creates) the log file at path
+func NewRotatingFileWriter(path string, maxSize int64, maxFiles int, daily bool) (*RotatingFileWriter, error) {
+	w := &RotatingFileWriter{
+		path:     path,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+		daily:    daily,
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write appends p to the log file, rotating first if p would exceed the size
+// limit or the day has changed
+func (w *RotatingFileWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if w.shouldRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			// Keep logging to the current file rather than losing entries;
+			// stderr is used since this writer may be the log output itself
+			fmt.Fprintf(os.Stderr, "log rotation failed: %v\n", err)
+			if w.file == nil {
+				return 0, err
+			}
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close flushes and releases the log file
+func (w *RotatingFileWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Sync()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	w.file = nil
+	return err
+}
+
+// shouldRotate reports whether the current file must be rotated before writing
+func (w *RotatingFileWriter) shouldRotate(incoming int64) bool {
+	if w.maxSize > 0 && w.size > 0 && w.size+incoming > w.maxSize {
+		return true
+	}
+	return w.daily && time.Now().Format("2006-01-02") != w.openedOn
+}
+
+// open opens the log file for appending and records its current size
+func (w *RotatingFileWriter) open() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	w.file = file
+	w.size = info.Size()
+	w.openedOn = info.ModTime().Format("2006-01-02")
+	if w.size == 0 {
+		w.openedOn = time.Now().Format("2006-01-02")
+	}
+	return nil
+}
+
+// rotate renames the current file with a timestamp suffix, opens a fresh one
+// and prunes old rotated files. If the rename fails the original file is
+// reopened so later writes still succeed.
+func (w *RotatingFileWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	w.file = nil
+
+	if err := renameFile(w.path, w.rotatedPath(time.Now())); err != nil {
+		if openErr := w.open(); openErr != nil {
+			return errors.Join(fmt.Errorf("failed to rotate log file: %w", err), openErr)
+		}
+		// Retry on a later write instead of on every one
+		w.openedOn = time.Now().Format("2006-01-02")
+		w.size = 0
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	if err := w.open(); err != nil {
+		return err
+	}
+	return w.prune()
+}
+
+// rotatedPath returns an unused name for a rotated file, moving the
+// timestamp forward if an earlier rotation already took it
+func (w *RotatingFileWriter) rotatedPath(now time.Time) string {
+	ext := filepath.Ext(w.path)
+	base := strings.TrimSuffix(w.path, ext)
+	for {
+		rotated := fmt.Sprintf("%s.%s%s", base, now.Format(rotatedTimeFormat), ext)
+		if _, err := os.Stat(rotated); os.IsNotExist(err) {
+			return rotated
+		}
+		now = now.Add(time.Nanosecond)
+	}
+}
+
+// prune deletes rotated files beyond maxFiles, oldest first
+func (w *RotatingFileWriter) prune() error {
+	if w.maxFiles <= 0 {
+		return nil
+	}
+
+	ext := filepath.Ext(w.path)
+	matches, err := filepath.Glob(strings.TrimSuffix(w.path, ext) + ".*" + ext)
+	if err != nil {
+		return err
+	}
+
+	// Timestamp suffixes sort chronologically
+	sort.Strings(matches)
+	for len(matches) > w.maxFiles {
+		if err := os.Remove(matches[0]); err != nil {
+			return fmt.Errorf("failed to remove old log file: %w", err)
+		}
+		matches = matches[1:]
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package core
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// logFiles returns the active log file and its rotated siblings
+func logFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, "app*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return matches
+}
+
+func TestRotatingFileWriterRotatesAtSizeThreshold(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	w, err := NewRotatingFileWriter(path, 100, 10, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	line := strings.Repeat("x", 39) + "\n" // 40 bytes
+	for i := 0; i < 2; i++ {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if files := logFiles(t, dir); len(files) != 1 {
+		t.Fatalf("expected no rotation below the threshold, got %v", files)
+	}
+
+	// The third line would take the file to 120 bytes
+	if _, err := w.Write([]byte(line)); err != nil {
+		t.Fatal(err)
+	}
+	if files := logFiles(t, dir); len(files) != 2 {
+		t.Fatalf("expected one rotated file, got %v", files)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != int64(len(line)) {
+		t.Fatalf("expected the active file to hold only the last line, got %d bytes", info.Size())
+	}
+}
+
+func TestRotatingFileWriterKeepsMaxFiles(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewRotatingFileWriter(filepath.Join(dir, "app.log"), 10, 2, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// Every write rotates, and rotations in the same instant must not
+	// overwrite each other
+	for i := 0; i < 6; i++ {
+		if _, err := fmt.Fprintf(w, "line %04d\n", i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if files := logFiles(t, dir); len(files) != 3 {
+		t.Fatalf("expected the active file and 2 rotated files, got %v", files)
+	}
+}
+
+func TestRotatingFileWriterKeepsWritingWhenRenameFails(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	w, err := NewRotatingFileWriter(path, 10, 2, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	renameFile = func(string, string) error { return errors.New("disk busy") }
+	defer func() { renameFile = os.Rename }()
+
+	for i := 0; i < 3; i++ {
+		if _, err := fmt.Fprintf(w, "line %04d\n", i); err != nil {
+			t.Fatalf("write %d failed after a failed rotation: %v", i, err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), "\n"); got != 3 {
+		t.Fatalf("expected all 3 lines in the original file, got %d", got)
+	}
+}
+
+func TestRotatingFileWriterConcurrentWritesStayIntact(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewRotatingFileWriter(filepath.Join(dir, "app.log"), 4096, 100, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const writers, perWriter = 8, 200
+	var wg sync.WaitGroup
+	for g := 0; g < writers; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				fmt.Fprintf(w, "writer=%d line=%04d end\n", g, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := 0
+	for _, file := range logFiles(t, dir) {
+		f, err := os.Open(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var g, i int
+			if _, err := fmt.Sscanf(scanner.Text(), "writer=%d line=%d end", &g, &i); err != nil {
+				t.Fatalf("interleaved line %q in %s", scanner.Text(), file)
+			}
+			seen++
+		}
+		f.Close()
+	}
+	if seen != writers*perWriter {
+		t.Fatalf("expected %d lines, found %d", writers*perWriter, seen)
+	}
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"errors"
+	"io"
+)
+
+// StackWriter fans log output out to several writers. Unlike io.MultiWriter
+// it keeps writing to the remaining writers when one fails and reports every
+// failure together.
+type StackWriter struct {
+	writers []io.Writer
+}
+
+// NewStackWriter creates a writer that writes to all the given writers
+func NewStackWriter(writers ...io.Writer) *StackWriter {
+	return &StackWriter{writers: writers}
+}
+
+// Write writes p to every writer in the stack
+func (s *StackWriter) Write(p []byte) (int, error) {
+	var errs []error
+	for _, writer := range s.writers {
+		if _, err := writer.Write(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(p), errors.Join(errs...)
+}
+
+// Close closes every writer in the stack that can be closed
+func (s *StackWriter) Close() error {
+	var errs []error
+	for _, writer := range s.writers {
+		if closer, ok := writer.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
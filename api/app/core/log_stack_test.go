@@ -0,0 +1,68 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// stackChannel is a log channel that records what it receives and can be
+// told to fail
+type stackChannel struct {
+	bytes.Buffer
+	writeErr error
+	closeErr error
+	closed   bool
+}
+
+func (c *stackChannel) Write(p []byte) (int, error) {
+	if c.writeErr != nil {
+		return 0, c.writeErr
+	}
+	return c.Buffer.Write(p)
+}
+
+func (c *stackChannel) Close() error {
+	c.closed = true
+	return c.closeErr
+}
+
+func TestStackWriterWritesToEveryChannel(t *testing.T) {
+	file, webhook := &stackChannel{}, &stackChannel{}
+	logger := NewLogger(NewStackWriter(file, webhook), LogLevelDebug, true)
+
+	logger.Info("user registered", map[string]interface{}{"id": 7})
+
+	if file.Len() == 0 || file.String() != webhook.String() {
+		t.Fatalf("expected both channels to receive the same entry, got %q and %q", file.String(), webhook.String())
+	}
+}
+
+func TestStackWriterKeepsWritingPastAFailingChannel(t *testing.T) {
+	broken := &stackChannel{writeErr: errors.New("webhook unreachable")}
+	file := &stackChannel{}
+	stack := NewStackWriter(broken, file)
+
+	n, err := stack.Write([]byte("entry\n"))
+	if err == nil || !strings.Contains(err.Error(), "webhook unreachable") {
+		t.Fatalf("expected the failing channel to be reported, got %v", err)
+	}
+	if n != len("entry\n") || file.String() != "entry\n" {
+		t.Fatalf("expected the other channel to receive the entry, got %d bytes and %q", n, file.String())
+	}
+}
+
+func TestStackWriterClosesEveryChannel(t *testing.T) {
+	first := &stackChannel{closeErr: errors.New("flush failed")}
+	second := &stackChannel{}
+	var plain bytes.Buffer
+	stack := NewStackWriter(first, &plain, second)
+
+	if err := stack.Close(); err == nil || !strings.Contains(err.Error(), "flush failed") {
+		t.Fatalf("expected the close failure to be reported, got %v", err)
+	}
+	if !first.closed || !second.closed {
+		t.Fatal("expected every closable channel to be closed")
+	}
+}
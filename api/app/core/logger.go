@@ -0,0 +1,199 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogLevel is the severity of a log entry
+type LogLevel int
+
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarning
+	LogLevelError
+)
+
+// String returns the lowercase name of the level
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "debug"
+	case LogLevelInfo:
+		return "info"
+	case LogLevelWarning:
+		return "warning"
+	case LogLevelError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// ParseLogLevel converts a level name such as "warning" into a LogLevel
+func ParseLogLevel(name string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LogLevelDebug, nil
+	case "info":
+		return LogLevelInfo, nil
+	case "warning", "warn":
+		return LogLevelWarning, nil
+	case "error":
+		return LogLevelError, nil
+	}
+	return LogLevelDebug, fmt.Errorf("unknown log level: %s", name)
+}
+
+// logOutput is a writer shared by a logger and everything derived from it
+type logOutput struct {
+	writer io.Writer
+	mutex  sync.Mutex
+}
+
+// Logger writes leveled entries as text or JSON lines. Loggers are immutable:
+// WithFields and WithContext return a copy carrying the extra fields, so a
+// request-scoped logger never leaks fields into the global one.
+type Logger struct {
+	output *logOutput
+	level  LogLevel
+	json   bool
+	fields map[string]interface{}
+}
+
+// NewLogger creates a logger writing entries at or above level to writer
+func NewLogger(writer io.Writer, level LogLevel, json bool) *Logger {
+	return &Logger{
+		output: &logOutput{writer: writer},
+		level:  level,
+		json:   json,
+		fields: map[string]interface{}{},
+	}
+}
+
+// WithFields returns a logger that adds fields to every entry
+func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
+	merged := make(map[string]interface{}, len(l.fields)+len(fields))
+	for key, value := range l.fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	return &Logger{
+		output: l.output,
+		level:  l.level,
+		json:   l.json,
+		fields: merged,
+	}
+}
+
+// WithContext returns a logger carrying the values this package stores in ctx
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	fields := map[string]interface{}{}
+	if requestID, ok := RequestIDFromContext(ctx); ok {
+		fields["request_id"] = requestID
+	}
+	if eventName, ok := EventNameFromContext(ctx); ok {
+		fields["event"] = eventName
+	}
+	if start, ok := OperationStartFromContext(ctx); ok {
+		fields["elapsed_ms"] = time.Since(start).Milliseconds()
+	}
+	if len(fields) == 0 {
+		return l
+	}
+	return l.WithFields(fields)
+}
+
+// ShouldHandle reports whether entries at level are written
+func (l *Logger) ShouldHandle(level LogLevel) bool {
+	return level >= l.level
+}
+
+// Debug logs a debug entry
+func (l *Logger) Debug(message string, context ...map[string]interface{}) {
+	l.Log(LogLevelDebug, message, context...)
+}
+
+// Info logs an info entry
+func (l *Logger) Info(message string, context ...map[string]interface{}) {
+	l.Log(LogLevelInfo, message, context...)
+}
+
+// Warning logs a warning entry
+func (l *Logger) Warning(message string, context ...map[string]interface{}) {
+	l.Log(LogLevelWarning, message, context...)
+}
+
+// Error logs an error entry
+func (l *Logger) Error(message string, context ...map[string]interface{}) {
+	l.Log(LogLevelError, message, context...)
+}
+
+// Log writes an entry with the logger's fields merged with context
+func (l *Logger) Log(level LogLevel, message string, context ...map[string]interface{}) {
+	if !l.ShouldHandle(level) {
+		return
+	}
+
+	merged := make(map[string]interface{}, len(l.fields))
+	for key, value := range l.fields {
+		merged[key] = value
+	}
+	for _, values := range context {
+		for key, value := range values {
+			merged[key] = value
+		}
+	}
+
+	line := l.format(level, message, merged, time.Now())
+
+	l.output.mutex.Lock()
+	defer l.output.mutex.Unlock()
+	l.output.writer.Write(line)
+}
+
+// format renders a single entry terminated by a newline
+func (l *Logger) format(level LogLevel, message string, context map[string]interface{}, timestamp time.Time) []byte {
+	if l.json {
+		entry := map[string]interface{}{
+			"level":     level.String(),
+			"message":   message,
+			"timestamp": timestamp.Format(time.RFC3339Nano),
+		}
+		if len(context) > 0 {
+			entry["context"] = context
+		}
+		line, err := json.Marshal(entry)
+		if err == nil {
+			return append(line, '\n')
+		}
+		// Fall back to text if a context value can't be encoded
+	}
+
+	line := fmt.Sprintf("%s %s %s", timestamp.Format("2006/01/02 15:04:05"), strings.ToUpper(level.String()), message)
+	if len(context) > 0 {
+		if encoded, err := json.Marshal(context); err == nil {
+			line += " " + string(encoded)
+		} else {
+			line += fmt.Sprintf(" %v", context)
+		}
+	}
+	return []byte(line + "\n")
+}
+
+// Global logger instance
+var LoggerInstance = NewLogger(os.Stderr, LogLevelDebug, false)
+
+// SetLogger sets the global logger
+func SetLogger(logger *Logger) {
+	LoggerInstance = logger
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONLoggerMergesFieldsAndContext(t *testing.T) {
+	var output bytes.Buffer
+	base := NewLogger(&output, LogLevelDebug, true)
+	logger := base.WithFields(map[string]interface{}{"service": "api", "region": "eu"})
+	logger = logger.WithFields(map[string]interface{}{"region": "us"})
+
+	logger.Warning("payment failed", map[string]interface{}{
+		"order": map[string]interface{}{"id": 42, "items": []string{"book", "pen"}},
+	})
+
+	entries := logEntries(t, &output)
+	if len(entries) != 1 {
+		t.Fatalf("expected one entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry["level"] != "warning" || entry["message"] != "payment failed" {
+		t.Fatalf("expected the level and message, got %v", entry)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, entry["timestamp"].(string)); err != nil {
+		t.Fatalf("expected an RFC 3339 timestamp, got %v", entry["timestamp"])
+	}
+
+	// Later fields override earlier ones and nested context is kept as JSON
+	want := map[string]interface{}{
+		"service": "api",
+		"region":  "us",
+		"order":   map[string]interface{}{"id": float64(42), "items": []interface{}{"book", "pen"}},
+	}
+	if !reflect.DeepEqual(entry["context"], want) {
+		t.Fatalf("expected context %v, got %v", want, entry["context"])
+	}
+
+	// The logger WithFields was called on is left untouched
+	output.Reset()
+	base.Info("plain")
+	if entry := logEntries(t, &output)[0]; entry["context"] != nil {
+		t.Fatalf("expected the base logger to carry no fields, got %v", entry["context"])
+	}
+}
+
+func TestLoggerWithContextAddsRequestValues(t *testing.T) {
+	var output bytes.Buffer
+	logger := NewLogger(&output, LogLevelDebug, true)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	logger.WithContext(ctx).Info("handled", map[string]interface{}{"status": 200})
+
+	fields := findContext(t, &output, "handled")
+	if fields["request_id"] != "req-1" || fields["status"] != float64(200) {
+		t.Fatalf("expected the request id and entry context, got %v", fields)
+	}
+	if logger.WithContext(context.Background()) != logger {
+		t.Fatal("expected a context without values to reuse the logger")
+	}
+}
+
+func TestLoggerSkipsEntriesBelowItsLevel(t *testing.T) {
+	var output bytes.Buffer
+	logger := NewLogger(&output, LogLevelWarning, false)
+
+	logger.Debug("noise")
+	logger.Info("noise")
+	logger.Error("disk full", map[string]interface{}{"free": 0})
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 1 || !strings.HasSuffix(lines[0], `ERROR disk full {"free":0}`) {
+		t.Fatalf("expected only the error as a text line, got %q", output.String())
+	}
+}
@@ -3,6 +3,8 @@ package core
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"sync/atomic"
 
 	"gopkg.in/gomail.v2"
 )
@@ -15,6 +17,16 @@ type MailConfig struct {
 	Password string
 	From     string
 	FromName string
+	// Encryption is "ssl" for implicit TLS, or "starttls" (the default) to
+	// upgrade the connection when the server offers it
+	Encryption string
+}
+
+// MailAttachment is a file attached to an outgoing email
+type MailAttachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
 }
 
 // SendMailJob represents a mail job for queue processing
@@ -27,6 +39,7 @@ type SendMailJob struct {
 // MailService defines the interface for mail operations
 type MailService interface {
 	SendMail(to []string, subject, body string) error
+	SendMailWithAttachments(to []string, subject, body string, attachments []MailAttachment) error
 	SendMailAsync(to []string, subject, body string, queueName string) error
 	ProcessMailJobFromQueue(jobData []byte) error
 }
@@ -35,6 +48,8 @@ type MailService interface {
 type MailProvider struct {
 	config *MailConfig
 	mailer *gomail.Dialer
+	sent   atomic.Int64
+	failed atomic.Int64
 }
 
 // NewMailProvider creates a new mail provider
@@ -47,13 +62,57 @@ func NewMailProvider(config *MailConfig, mailer *gomail.Dialer) *MailProvider {
 
 // SendMail sends an email using the configured mailer
 func (m *MailProvider) SendMail(to []string, subject, body string) error {
+	return m.send(m.newMessage(to, subject, body))
+}
+
+// SendMailWithAttachments sends an email with files attached as MIME parts
+func (m *MailProvider) SendMailWithAttachments(to []string, subject, body string, attachments []MailAttachment) error {
+	msg := m.newMessage(to, subject, body)
+	for _, attachment := range attachments {
+		content := attachment.Content
+		settings := []gomail.FileSetting{
+			gomail.SetCopyFunc(func(w io.Writer) error {
+				_, err := w.Write(content)
+				return err
+			}),
+		}
+		if attachment.ContentType != "" {
+			settings = append(settings, gomail.SetHeader(map[string][]string{
+				"Content-Type": {attachment.ContentType},
+			}))
+		}
+		msg.Attach(attachment.Filename, settings...)
+	}
+
+	return m.send(msg)
+}
+
+// GetStats returns counts of sent and failed messages
+func (m *MailProvider) GetStats() map[string]interface{} {
+	return map[string]interface{}{
+		"sent":   m.sent.Load(),
+		"failed": m.failed.Load(),
+	}
+}
+
+// newMessage builds a message with the configured sender
+func (m *MailProvider) newMessage(to []string, subject, body string) *gomail.Message {
 	msg := gomail.NewMessage()
 	msg.SetHeader("From", fmt.Sprintf("%s <%s>", m.config.FromName, m.config.From))
 	msg.SetHeader("To", to...)
 	msg.SetHeader("Subject", subject)
 	msg.SetBody("text/html", body)
+	return msg
+}
 
-	return m.mailer.DialAndSend(msg)
+// send delivers a message and records the outcome
+func (m *MailProvider) send(msg *gomail.Message) error {
+	if err := m.mailer.DialAndSend(msg); err != nil {
+		m.failed.Add(1)
+		return err
+	}
+	m.sent.Add(1)
+	return nil
 }
 
 // SendMailAsync sends an email asynchronously via queue
@@ -108,6 +167,10 @@ func SendMail(to []string, subject, body string) error {
 	return MailServiceInstance.SendMail(to, subject, body)
 }
 
+func SendMailWithAttachments(to []string, subject, body string, attachments []MailAttachment) error {
+	return MailServiceInstance.SendMailWithAttachments(to, subject, body, attachments)
+}
+
 func SendMailAsync(to []string, subject, body string, queueName string) error {
 	return MailServiceInstance.SendMailAsync(to, subject, body, queueName)
 }
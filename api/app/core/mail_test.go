@@ -0,0 +1,153 @@
+package core
+
+import (
+	"bufio"
+	"encoding/base64"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gopkg.in/gomail.v2"
+)
+
+// fakeSMTP accepts one session and records the commands and message it receives
+type fakeSMTP struct {
+	listener net.Listener
+	commands []string
+	data     string
+	done     chan struct{}
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeSMTP{listener: listener, done: make(chan struct{})}
+	go server.serve()
+	return server
+}
+
+func (s *fakeSMTP) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 localhost ready")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0])
+
+		// Envelope commands are recorded with their addresses
+		if verb == "MAIL" || verb == "RCPT" {
+			s.commands = append(s.commands, command)
+		} else {
+			s.commands = append(s.commands, verb)
+		}
+
+		switch verb {
+		case "EHLO":
+			reply("250 localhost")
+		case "DATA":
+			reply("354 send the message")
+			var data strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil || line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			s.data = data.String()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// provider returns a mail provider sending to the fake server
+func (s *fakeSMTP) provider(t *testing.T) *MailProvider {
+	t.Helper()
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	config := &MailConfig{Host: "127.0.0.1", Port: portNumber, From: "app@example.com", FromName: "App"}
+	return NewMailProvider(config, gomail.NewDialer(config.Host, config.Port, "", ""))
+}
+
+func TestSendMailWithAttachmentsOverSMTP(t *testing.T) {
+	server := newFakeSMTP(t)
+	provider := server.provider(t)
+
+	content := []byte("id,total\n1,9.99\n")
+	err := provider.SendMailWithAttachments(
+		[]string{"ann@example.com", "bob@example.com"},
+		"Monthly report",
+		"<p>Attached</p>",
+		[]MailAttachment{{Filename: "report.csv", ContentType: "text/csv", Content: content}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-server.done
+
+	want := []string{
+		"EHLO",
+		"MAIL FROM:<app@example.com>",
+		"RCPT TO:<ann@example.com>",
+		"RCPT TO:<bob@example.com>",
+		"DATA",
+		"QUIT",
+	}
+	if !reflect.DeepEqual(server.commands, want) {
+		t.Fatalf("expected commands %q, got %q", want, server.commands)
+	}
+
+	for _, part := range []string{
+		"Subject: Monthly report",
+		"Content-Type: multipart/mixed",
+		"Content-Type: text/csv",
+		`Content-Disposition: attachment; filename="report.csv"`,
+		"Content-Transfer-Encoding: base64",
+		base64.StdEncoding.EncodeToString(content),
+	} {
+		if !strings.Contains(server.data, part) {
+			t.Errorf("expected the message to contain %q, got:\n%s", part, server.data)
+		}
+	}
+
+	if stats := provider.GetStats(); stats["sent"] != int64(1) || stats["failed"] != int64(0) {
+		t.Fatalf("expected one sent message, got %v", stats)
+	}
+}
+
+func TestSendMailCountsFailures(t *testing.T) {
+	server := newFakeSMTP(t)
+	provider := server.provider(t)
+	server.listener.Close()
+	<-server.done
+
+	if err := provider.SendMail([]string{"ann@example.com"}, "Hello", "<p>Hi</p>"); err == nil {
+		t.Fatal("expected sending to a closed server to fail")
+	}
+	if stats := provider.GetStats(); stats["sent"] != int64(0) || stats["failed"] != int64(1) {
+		t.Fatalf("expected one failed message, got %v", stats)
+	}
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// StatsProvider is implemented by components that report runtime statistics
+type StatsProvider interface {
+	GetStats() map[string]interface{}
+}
+
+// ContextStatsProvider is implemented by components whose stats need I/O,
+// so collecting them can be bounded by the caller's context
+type ContextStatsProvider interface {
+	GetStatsWithContext(ctx context.Context) map[string]interface{}
+}
+
+// invalidMetricChars matches characters not allowed in Prometheus metric names
+var invalidMetricChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// MetricsRegistry collects stats from registered components and renders them
+// in the Prometheus text exposition format
+type MetricsRegistry struct {
+	providers map[string]StatsProvider
+	mutex     sync.RWMutex
+}
+
+// NewMetricsRegistry creates a new metrics registry
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{
+		providers: make(map[string]StatsProvider),
+	}
+}
+
+// Register adds a component whose stats are exported as app_<name>_<stat>
+func (r *MetricsRegistry) Register(name string, provider StatsProvider) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.providers[name] = provider
+}
+
+// WritePrometheus writes every numeric stat of every registered component,
+// plus Go runtime gauges, as Prometheus gauges. Components that do I/O to
+// collect their stats give up when ctx is done.
+func (r *MetricsRegistry) WritePrometheus(ctx context.Context, w io.Writer) error {
+	r.mutex.RLock()
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	providers := make(map[string]StatsProvider, len(r.providers))
+	for name, provider := range r.providers {
+		providers[name] = provider
+	}
+	r.mutex.RUnlock()
+
+	sort.Strings(names)
+	for _, name := range names {
+		if err := writeStats(w, name, collectStats(ctx, providers[name])); err != nil {
+			return err
+		}
+	}
+
+	var memory runtime.MemStats
+	runtime.ReadMemStats(&memory)
+	return writeStats(w, "runtime", map[string]interface{}{
+		"goroutines":        runtime.NumGoroutine(),
+		"heap_alloc_bytes":  memory.HeapAlloc,
+		"heap_objects":      memory.HeapObjects,
+		"gc_cycles_total":   memory.NumGC,
+		"total_alloc_bytes": memory.TotalAlloc,
+	})
+}
+
+// collectStats gets a component's stats, with ctx if it can take one
+func collectStats(ctx context.Context, provider StatsProvider) map[string]interface{} {
+	if contextual, ok := provider.(ContextStatsProvider); ok {
+		return contextual.GetStatsWithContext(ctx)
+	}
+	return provider.GetStats()
+}
+
+// writeStats writes one gauge per numeric stat, skipping non-numeric values
+func writeStats(w io.Writer, component string, stats map[string]interface{}) error {
+	keys := make([]string, 0, len(stats))
+	for key := range stats {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, ok := metricValue(stats[key])
+		if !ok {
+			continue
+		}
+
+		name := invalidMetricChars.ReplaceAllString("app_"+component+"_"+key, "_")
+		if _, err := fmt.Fprintf(w, "# TYPE %s gauge\n%s %v\n", name, name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// metricValue converts a stat to a number, reporting false for non-numeric stats
+func metricValue(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+	return nil, false
+}
+
+// Global metrics registry instance
+var MetricsInstance = NewMetricsRegistry()
+
+// RegisterStats registers a component with the global metrics registry
+func RegisterStats(name string, provider StatsProvider) {
+	MetricsInstance.Register(name, provider)
+}
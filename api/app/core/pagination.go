@@ -0,0 +1,33 @@
+package core
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// EncodeCursor encodes the last-seen ID of a page into an opaque cursor
+func EncodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+// DecodeCursor decodes a cursor into the last-seen ID. An empty cursor
+// starts from the beginning and decodes to 0.
+func DecodeCursor(cursor string) (uint, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(string(decoded), 10, 64)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	return uint(id), nil
+}
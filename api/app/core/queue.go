@@ -2,8 +2,13 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
@@ -17,6 +22,12 @@ type QueueConfig struct {
 	Region    string
 	Queue     string
 	Endpoint  string
+	// WaitTimeSeconds is how long a receive long-polls for messages (max 20)
+	WaitTimeSeconds int32
+	// VisibilityTimeout hides received messages from other workers for this
+	// many seconds; zero uses the queue's default. Messages that aren't
+	// deleted in time are redelivered, so it must outlast the slowest job.
+	VisibilityTimeout int32
 }
 
 // QueueService defines the interface for queue operations
@@ -27,18 +38,37 @@ type QueueService interface {
 	SendMessageToQueueWithAttributes(messageBody string, attributes map[string]string, queueName string) error
 	ReceiveMessage() (*sqs.ReceiveMessageOutput, error)
 	ReceiveMessageFromQueue(queueName string) (*sqs.ReceiveMessageOutput, error)
+	ReceiveMessageFromQueueWithContext(ctx context.Context, queueName string, maxMessages int32) (*sqs.ReceiveMessageOutput, error)
+	TryReceiveMessageFromQueue(ctx context.Context, queueName string, maxMessages int32) (*sqs.ReceiveMessageOutput, error)
+	ChangeMessageVisibility(receiptHandle string, queueName string, timeout time.Duration) error
+	Size(queueName string) (int64, error)
+	Sizes(queueNames []string) (map[string]int64, error)
 	DeleteMessage(receiptHandle string) error
 	DeleteMessageFromQueue(receiptHandle string, queueName string) error
 }
 
+// SQSClient is the part of the SQS API the queue provider uses, so it can
+// be faked in tests
+type SQSClient interface {
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
+	ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error)
+	GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error)
+	GetQueueUrl(ctx context.Context, params *sqs.GetQueueUrlInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error)
+}
+
+// Ensure the SDK client can be used with NewQueueProvider
+var _ SQSClient = (*sqs.Client)(nil)
+
 // QueueProvider implements the QueueService interface
 type QueueProvider struct {
 	config *QueueConfig
-	client *sqs.Client
+	client SQSClient
 }
 
 // NewQueueProvider creates a new queue provider
-func NewQueueProvider(config *QueueConfig, client *sqs.Client) *QueueProvider {
+func NewQueueProvider(config *QueueConfig, client SQSClient) *QueueProvider {
 	return &QueueProvider{
 		config: config,
 		client: client,
@@ -117,40 +147,163 @@ func (q *QueueProvider) SendMessageToQueueWithAttributes(messageBody string, att
 
 // ReceiveMessage receives a message from the default SQS queue
 func (q *QueueProvider) ReceiveMessage() (*sqs.ReceiveMessageOutput, error) {
-	queueUrl := fmt.Sprintf("%s/queue/%s", q.config.Endpoint, q.config.Queue)
+	return q.ReceiveMessageFromQueueWithContext(context.TODO(), q.config.Queue, MaxReceiveMessages)
+}
 
-	result, err := q.client.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
-		QueueUrl:              aws.String(queueUrl),
-		MaxNumberOfMessages:   10,
-		WaitTimeSeconds:       0,
+// ReceiveMessageFromQueue receives a message from a specific queue
+func (q *QueueProvider) ReceiveMessageFromQueue(queueName string) (*sqs.ReceiveMessageOutput, error) {
+	return q.ReceiveMessageFromQueueWithContext(context.TODO(), queueName, MaxReceiveMessages)
+}
+
+// ReceiveMessageFromQueueWithContext receives up to maxMessages messages from
+// a specific queue, long polling for up to WaitTimeSeconds; cancelling ctx
+// ends the poll early
+func (q *QueueProvider) ReceiveMessageFromQueueWithContext(ctx context.Context, queueName string, maxMessages int32) (*sqs.ReceiveMessageOutput, error) {
+	return q.receive(ctx, queueName, maxMessages, q.config.WaitTimeSeconds)
+}
+
+// TryReceiveMessageFromQueue receives up to maxMessages of the messages
+// waiting in a queue without long polling, returning none if it is empty
+func (q *QueueProvider) TryReceiveMessageFromQueue(ctx context.Context, queueName string, maxMessages int32) (*sqs.ReceiveMessageOutput, error) {
+	return q.receive(ctx, queueName, maxMessages, 0)
+}
+
+// MaxReceiveMessages is the most messages SQS returns from one receive
+const MaxReceiveMessages = 10
+
+// receive receives up to maxMessages messages, clamped to what SQS allows,
+// waiting up to waitSeconds for the first
+func (q *QueueProvider) receive(ctx context.Context, queueName string, maxMessages int32, waitSeconds int32) (*sqs.ReceiveMessageOutput, error) {
+	maxMessages = min(max(maxMessages, 1), MaxReceiveMessages)
+	result, err := q.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:              aws.String(q.queueURL(queueName)),
+		MaxNumberOfMessages:   maxMessages,
+		WaitTimeSeconds:       waitSeconds,
+		VisibilityTimeout:     q.config.VisibilityTimeout,
 		MessageAttributeNames: []string{"All"},
+		MessageSystemAttributeNames: []types.MessageSystemAttributeName{
+			types.MessageSystemAttributeNameApproximateReceiveCount,
+		},
 	})
 
 	if err != nil {
-		log.Printf("Error receiving messages: %v", err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Printf("Error receiving messages from queue %s: %v", queueName, err)
 		return nil, err
 	}
 
 	return result, nil
 }
 
-// ReceiveMessageFromQueue receives a message from a specific queue
-func (q *QueueProvider) ReceiveMessageFromQueue(queueName string) (*sqs.ReceiveMessageOutput, error) {
-	queueUrl := fmt.Sprintf("%s/queue/%s", q.config.Endpoint, queueName)
-
-	result, err := q.client.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
-		QueueUrl:              aws.String(queueUrl),
-		MaxNumberOfMessages:   10,
-		WaitTimeSeconds:       0,
-		MessageAttributeNames: []string{"All"},
+// ChangeMessageVisibility keeps a received message hidden for timeout from
+// now, e.g. to extend a long running job or to retry a failed one sooner
+func (q *QueueProvider) ChangeMessageVisibility(receiptHandle string, queueName string, timeout time.Duration) error {
+	_, err := q.client.ChangeMessageVisibility(context.TODO(), &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(q.queueURL(queueName)),
+		ReceiptHandle:     aws.String(receiptHandle),
+		VisibilityTimeout: int32(timeout.Seconds()),
 	})
+	return err
+}
+
+// queueStatsTimeout bounds queue size lookups made without a caller's context
+const queueStatsTimeout = 5 * time.Second
 
+// Size returns the approximate number of messages waiting in a queue,
+// giving up after queueStatsTimeout
+func (q *QueueProvider) Size(queueName string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queueStatsTimeout)
+	defer cancel()
+	return q.SizeWithContext(ctx, queueName)
+}
+
+// SizeWithContext returns the approximate number of messages waiting in a
+// queue, giving up when ctx is done
+func (q *QueueProvider) SizeWithContext(ctx context.Context, queueName string) (int64, error) {
+	result, err := q.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(q.queueURL(queueName)),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameApproximateNumberOfMessages},
+	})
 	if err != nil {
-		log.Printf("Error receiving messages from queue %s: %v", queueName, err)
-		return nil, err
+		return 0, err
 	}
 
-	return result, nil
+	count := result.Attributes[string(types.QueueAttributeNameApproximateNumberOfMessages)]
+	return strconv.ParseInt(count, 10, 64)
+}
+
+// Sizes returns the approximate depth of several queues, giving up after
+// queueStatsTimeout
+func (q *QueueProvider) Sizes(queueNames []string) (map[string]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queueStatsTimeout)
+	defer cancel()
+	return q.SizesWithContext(ctx, queueNames)
+}
+
+// SizesWithContext returns the approximate depth of several queues, fetched
+// concurrently until ctx is done. Queues whose size couldn't be read are left
+// out of the map and their errors are returned together.
+func (q *QueueProvider) SizesWithContext(ctx context.Context, queueNames []string) (map[string]int64, error) {
+	sizes := make(map[string]int64, len(queueNames))
+	var errs []error
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, queueName := range slices.Compact(slices.Sorted(slices.Values(queueNames))) {
+		wg.Add(1)
+		go func(queueName string) {
+			defer wg.Done()
+			size, err := q.SizeWithContext(ctx, queueName)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("queue %s: %w", queueName, err))
+				return
+			}
+			sizes[queueName] = size
+		}(queueName)
+	}
+	wg.Wait()
+
+	return sizes, errors.Join(errs...)
+}
+
+// GetStats reports the depth of every enabled queue, giving up after queueStatsTimeout
+func (q *QueueProvider) GetStats() map[string]interface{} {
+	ctx, cancel := context.WithTimeout(context.Background(), queueStatsTimeout)
+	defer cancel()
+	return q.GetStatsWithContext(ctx)
+}
+
+// GetStatsWithContext reports the depth of every enabled queue, leaving out
+// queues whose depth wasn't read before ctx was done
+func (q *QueueProvider) GetStatsWithContext(ctx context.Context) map[string]interface{} {
+	sizes, _ := q.SizesWithContext(ctx, GetStringSlice("queue.enabled_queues"))
+
+	stats := make(map[string]interface{}, len(sizes))
+	for queueName, size := range sizes {
+		stats["size_"+queueName] = size
+	}
+	return stats
+}
+
+// queueURL returns the URL of a queue on the configured endpoint
+func (q *QueueProvider) queueURL(queueName string) string {
+	return fmt.Sprintf("%s/queue/%s", q.config.Endpoint, queueName)
+}
+
+// Ping verifies the queue endpoint is reachable and the default queue exists
+func (q *QueueProvider) Ping(ctx context.Context) error {
+	_, err := q.client.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{
+		QueueName: aws.String(q.config.Queue),
+	})
+	if err != nil {
+		return fmt.Errorf("queue %s at %s is unreachable: %w", q.config.Queue, q.config.Endpoint, err)
+	}
+	return nil
 }
 
 // DeleteMessage deletes a message from the default SQS queue
@@ -204,6 +357,26 @@ func ReceiveMessageFromQueue(queueName string) (*sqs.ReceiveMessageOutput, error
 	return QueueServiceInstance.ReceiveMessageFromQueue(queueName)
 }
 
+func ReceiveMessageFromQueueWithContext(ctx context.Context, queueName string, maxMessages int32) (*sqs.ReceiveMessageOutput, error) {
+	return QueueServiceInstance.ReceiveMessageFromQueueWithContext(ctx, queueName, maxMessages)
+}
+
+func TryReceiveMessageFromQueue(ctx context.Context, queueName string, maxMessages int32) (*sqs.ReceiveMessageOutput, error) {
+	return QueueServiceInstance.TryReceiveMessageFromQueue(ctx, queueName, maxMessages)
+}
+
+func ChangeMessageVisibility(receiptHandle string, queueName string, timeout time.Duration) error {
+	return QueueServiceInstance.ChangeMessageVisibility(receiptHandle, queueName, timeout)
+}
+
+func QueueSize(queueName string) (int64, error) {
+	return QueueServiceInstance.Size(queueName)
+}
+
+func QueueSizes(queueNames []string) (map[string]int64, error) {
+	return QueueServiceInstance.Sizes(queueNames)
+}
+
 func DeleteMessage(receiptHandle string) error {
 	return QueueServiceInstance.DeleteMessage(receiptHandle)
 }
@@ -0,0 +1,342 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// newTestQueueProvider returns a queue provider talking to handler
+func newTestQueueProvider(t *testing.T, handler http.HandlerFunc) *QueueProvider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := sqs.New(sqs.Options{
+		Region:           "us-east-1",
+		Credentials:      aws.AnonymousCredentials{},
+		BaseEndpoint:     aws.String(server.URL),
+		RetryMaxAttempts: 1,
+	})
+	return NewQueueProvider(&QueueConfig{Endpoint: server.URL, Queue: "jobs"}, client)
+}
+
+func TestQueueSizesReadsEachQueue(t *testing.T) {
+	queue := newTestQueueProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.Write([]byte(`{"Attributes":{"ApproximateNumberOfMessages":"3"}}`))
+	})
+
+	sizes, err := queue.SizesWithContext(context.Background(), []string{"jobs", "mail", "jobs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sizes) != 2 || sizes["jobs"] != 3 || sizes["mail"] != 3 {
+		t.Fatalf("expected both queues at depth 3, got %v", sizes)
+	}
+}
+
+func TestQueueSizesGiveUpWhenContextIsDone(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	queue := newTestQueueProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	sizes, err := queue.SizesWithContext(ctx, []string{"jobs", "mail"})
+	if err == nil || len(sizes) != 0 {
+		t.Fatalf("expected the lookups to fail, got %v, %v", sizes, err)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Fatalf("expected the lookups to stop with the context, took %v", elapsed)
+	}
+}
+
+// contextStats records the context its stats were collected with
+type contextStats struct{ ctx context.Context }
+
+func (s *contextStats) GetStats() map[string]interface{} {
+	return map[string]interface{}{"used_context": 0}
+}
+
+func (s *contextStats) GetStatsWithContext(ctx context.Context) map[string]interface{} {
+	s.ctx = ctx
+	return map[string]interface{}{"used_context": 1}
+}
+
+func TestWritePrometheusPassesContextToStats(t *testing.T) {
+	registry := NewMetricsRegistry()
+	stats := &contextStats{}
+	registry.Register("queue", stats)
+
+	ctx := context.WithValue(context.Background(), contextStats{}, "request")
+	var output bytes.Buffer
+	if err := registry.WritePrometheus(ctx, &output); err != nil {
+		t.Fatal(err)
+	}
+	if stats.ctx != ctx {
+		t.Fatal("expected the stats to be collected with the caller's context")
+	}
+	if !strings.Contains(output.String(), "app_queue_used_context 1") {
+		t.Fatalf("expected the context-aware stats, got %q", output.String())
+	}
+}
+
+// fakeSQS is an in-memory SQS keyed by queue URL, with a clock the test moves
+type fakeSQS struct {
+	mutex    sync.Mutex
+	now      time.Time
+	queues   map[string][]*fakeMessage
+	handles  int
+	received []*sqs.ReceiveMessageInput
+}
+
+// fakeMessage is a message held by fakeSQS
+type fakeMessage struct {
+	body       string
+	attributes map[string]types.MessageAttributeValue
+	handle     string
+	visibleAt  time.Time
+	receives   int
+}
+
+// fakeSQSDefaultVisibility is the visibility timeout of queues without one
+const fakeSQSDefaultVisibility = 30 * time.Second
+
+func newFakeSQS() *fakeSQS {
+	return &fakeSQS{now: time.Unix(0, 0), queues: map[string][]*fakeMessage{}}
+}
+
+// advance moves the fake's clock forward
+func (f *fakeSQS) advance(d time.Duration) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func (f *fakeSQS) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	url := aws.ToString(params.QueueUrl)
+	f.queues[url] = append(f.queues[url], &fakeMessage{
+		body:       aws.ToString(params.MessageBody),
+		attributes: params.MessageAttributes,
+		visibleAt:  f.now,
+	})
+	return &sqs.SendMessageOutput{}, nil
+}
+
+func (f *fakeSQS) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.received = append(f.received, params)
+
+	visibility := time.Duration(params.VisibilityTimeout) * time.Second
+	if visibility == 0 {
+		visibility = fakeSQSDefaultVisibility
+	}
+
+	output := &sqs.ReceiveMessageOutput{}
+	for _, message := range f.queues[aws.ToString(params.QueueUrl)] {
+		if len(output.Messages) == int(params.MaxNumberOfMessages) {
+			break
+		}
+		if f.now.Before(message.visibleAt) {
+			continue
+		}
+		f.handles++
+		message.handle = fmt.Sprintf("handle-%d", f.handles)
+		message.visibleAt = f.now.Add(visibility)
+		message.receives++
+		output.Messages = append(output.Messages, types.Message{
+			Body:              aws.String(message.body),
+			ReceiptHandle:     aws.String(message.handle),
+			MessageAttributes: message.attributes,
+			Attributes: map[string]string{
+				string(types.MessageSystemAttributeNameApproximateReceiveCount): strconv.Itoa(message.receives),
+			},
+		})
+	}
+	return output, nil
+}
+
+// find returns the message in a queue currently received with handle
+func (f *fakeSQS) find(url, handle string) (int, *fakeMessage, error) {
+	for i, message := range f.queues[url] {
+		if message.handle == handle {
+			return i, message, nil
+		}
+	}
+	return 0, nil, fmt.Errorf("receipt handle %s is invalid", handle)
+}
+
+func (f *fakeSQS) DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	url := aws.ToString(params.QueueUrl)
+	i, _, err := f.find(url, aws.ToString(params.ReceiptHandle))
+	if err != nil {
+		return nil, err
+	}
+	f.queues[url] = slices.Delete(f.queues[url], i, i+1)
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (f *fakeSQS) ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	_, message, err := f.find(aws.ToString(params.QueueUrl), aws.ToString(params.ReceiptHandle))
+	if err != nil {
+		return nil, err
+	}
+	message.visibleAt = f.now.Add(time.Duration(params.VisibilityTimeout) * time.Second)
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+func (f *fakeSQS) GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	visible := 0
+	for _, message := range f.queues[aws.ToString(params.QueueUrl)] {
+		if !f.now.Before(message.visibleAt) {
+			visible++
+		}
+	}
+	return &sqs.GetQueueAttributesOutput{Attributes: map[string]string{
+		string(types.QueueAttributeNameApproximateNumberOfMessages): strconv.Itoa(visible),
+	}}, nil
+}
+
+func (f *fakeSQS) GetQueueUrl(ctx context.Context, params *sqs.GetQueueUrlInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error) {
+	return &sqs.GetQueueUrlOutput{QueueUrl: aws.String("http://sqs.test/queue/" + aws.ToString(params.QueueName))}, nil
+}
+
+// newFakeQueueProvider returns a queue provider backed by a fakeSQS
+func newFakeQueueProvider(visibilityTimeout int32) (*QueueProvider, *fakeSQS) {
+	fake := newFakeSQS()
+	config := &QueueConfig{Endpoint: "http://sqs.test", Queue: "jobs", VisibilityTimeout: visibilityTimeout}
+	return NewQueueProvider(config, fake), fake
+}
+
+// popOne receives at most one message from queueName without waiting
+func popOne(t *testing.T, queue *QueueProvider, queueName string) *types.Message {
+	t.Helper()
+	output, err := queue.TryReceiveMessageFromQueue(context.Background(), queueName, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(output.Messages) == 0 {
+		return nil
+	}
+	return &output.Messages[0]
+}
+
+// queueSize reads a queue's size, failing the test on error
+func queueSize(t *testing.T, queue *QueueProvider, queueName string) int64 {
+	t.Helper()
+	size, err := queue.Size(queueName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return size
+}
+
+func TestQueuePushPopAndDelete(t *testing.T) {
+	queue, fake := newFakeQueueProvider(0)
+
+	if err := queue.SendMessageToQueueWithAttributes(`{"to":["a@example.com"]}`, map[string]string{"job_type": "send_mail"}, "mail"); err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.SendMessage("default"); err != nil {
+		t.Fatal(err)
+	}
+	if size := queueSize(t, queue, "mail"); size != 1 {
+		t.Fatalf("expected one message waiting in mail, got %d", size)
+	}
+
+	message := popOne(t, queue, "mail")
+	if message == nil || aws.ToString(message.Body) != `{"to":["a@example.com"]}` {
+		t.Fatalf("expected the pushed message, got %+v", message)
+	}
+	if jobType := message.MessageAttributes["job_type"]; aws.ToString(jobType.StringValue) != "send_mail" {
+		t.Fatalf("expected the job_type attribute, got %+v", message.MessageAttributes)
+	}
+	if size := queueSize(t, queue, "mail"); size != 0 {
+		t.Fatalf("expected an in-flight message to be hidden from the size, got %d", size)
+	}
+
+	if err := queue.DeleteMessageFromQueue(aws.ToString(message.ReceiptHandle), "mail"); err != nil {
+		t.Fatal(err)
+	}
+	fake.advance(time.Hour)
+	if redelivered := popOne(t, queue, "mail"); redelivered != nil {
+		t.Fatalf("expected a deleted message never to come back, got %+v", redelivered)
+	}
+
+	if message := popOne(t, queue, "jobs"); message == nil || aws.ToString(message.Body) != "default" {
+		t.Fatalf("expected SendMessage to push to the default queue, got %+v", message)
+	}
+	if err := queue.DeleteMessage("unknown"); err == nil {
+		t.Fatal("expected deleting with an unknown receipt handle to fail")
+	}
+}
+
+func TestQueueVisibilityTimeout(t *testing.T) {
+	queue, fake := newFakeQueueProvider(60)
+	queue.SendMessage("job")
+
+	first := popOne(t, queue, "jobs")
+	if first == nil {
+		t.Fatal("expected the message to be received")
+	}
+	if timeout := fake.received[0].VisibilityTimeout; timeout != 60 {
+		t.Fatalf("expected receives to ask for the configured visibility timeout, got %d", timeout)
+	}
+
+	fake.advance(59 * time.Second)
+	if message := popOne(t, queue, "jobs"); message != nil {
+		t.Fatal("expected the message to stay hidden within the visibility timeout")
+	}
+
+	fake.advance(2 * time.Second)
+	second := popOne(t, queue, "jobs")
+	if second == nil || second.Attributes["ApproximateReceiveCount"] != "2" {
+		t.Fatalf("expected the message to be redelivered once the timeout passed, got %+v", second)
+	}
+
+	// Extending the timeout keeps a long running job hidden
+	if err := queue.ChangeMessageVisibility(aws.ToString(second.ReceiptHandle), "jobs", 5*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	fake.advance(2 * time.Minute)
+	if message := popOne(t, queue, "jobs"); message != nil {
+		t.Fatal("expected the extended message to stay hidden")
+	}
+
+	// A zero timeout releases a failed job for an immediate retry
+	if err := queue.ChangeMessageVisibility(aws.ToString(second.ReceiptHandle), "jobs", 0); err != nil {
+		t.Fatal(err)
+	}
+	if message := popOne(t, queue, "jobs"); message == nil {
+		t.Fatal("expected the released message to be received again")
+	}
+}
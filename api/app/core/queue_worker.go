@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -15,22 +16,67 @@ import (
 type QueueWorker struct {
 	ctx           context.Context
 	cancel        context.CancelFunc
+	done          chan struct{}
 	enabledQueues []string
+	limiters      map[string]*TokenBucket
+	metrics       workerMetrics
+
+	// Priority mode drains queues in order instead of polling them all at once
+	prioritized   bool
+	fairnessQuota int
+	streak        int
+	nextFairQueue int
 }
 
+// priorityIdleInterval is how long a prioritized worker sleeps once every
+// queue was found empty, since it polls without long polling
+const priorityIdleInterval = time.Second
+
 // NewQueueWorker creates a new queue worker
 func NewQueueWorker(enabledQueues []string) *QueueWorker {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &QueueWorker{
 		ctx:           ctx,
 		cancel:        cancel,
+		done:          make(chan struct{}),
 		enabledQueues: enabledQueues,
+		limiters:      make(map[string]*TokenBucket),
 	}
 }
 
+// NewPriorityQueueWorker creates a worker that drains queues in the given
+// order, only taking work from a queue when every queue before it is empty.
+// After fairnessQuota consecutive rounds served by a higher queue, one round
+// goes to the lower queues in turn so they are never starved; zero disables this.
+func NewPriorityQueueWorker(queues []string, fairnessQuota int) *QueueWorker {
+	w := NewQueueWorker(queues)
+	w.prioritized = true
+	w.fairnessQuota = fairnessQuota
+	return w
+}
+
+// Prioritized reports whether the worker drains its queues in order
+func (w *QueueWorker) Prioritized() bool {
+	return w.prioritized
+}
+
+// SetRateLimit caps a queue at rate jobs per second with bursts of up to
+// burst jobs, e.g. to protect a downstream API. If several limits are set
+// for the same queue the strictest wins. Call it before Start.
+func (w *QueueWorker) SetRateLimit(queueName string, rate float64, burst int) {
+	if rate <= 0 {
+		return
+	}
+	if existing, ok := w.limiters[queueName]; ok && existing.Rate() <= rate {
+		return
+	}
+	w.limiters[queueName] = NewTokenBucket(rate, burst)
+}
+
 // Start starts the queue worker
 func (w *QueueWorker) Start() {
 	log.Printf("Starting queue worker for queues: %s", strings.Join(w.enabledQueues, ", "))
+	defer close(w.done)
 
 	for {
 		select {
@@ -38,6 +84,12 @@ func (w *QueueWorker) Start() {
 			log.Println("Queue worker stopped")
 			return
 		default:
+			if w.prioritized {
+				if !w.processByPriority() {
+					w.sleep(priorityIdleInterval)
+				}
+				continue
+			}
 			w.processAllQueues()
 			time.Sleep(50 * time.Millisecond) // Poll every 50ms
 		}
@@ -60,32 +112,145 @@ func (w *QueueWorker) processAllQueues() {
 	wg.Wait()
 }
 
+// processByPriority processes one batch from the first queue with work,
+// reporting whether any queue had work
+func (w *QueueWorker) processByPriority() bool {
+	queues := w.enabledQueues
+
+	// Give lower queues a turn once higher ones have had enough in a row
+	if w.fairnessQuota > 0 && w.streak >= w.fairnessQuota && len(queues) > 1 {
+		w.streak = 0
+		for i := 0; i < len(queues)-1; i++ {
+			index := 1 + (w.nextFairQueue+i)%(len(queues)-1)
+			if w.tryProcessQueue(queues[index]) {
+				w.nextFairQueue = index % (len(queues) - 1)
+				return true
+			}
+		}
+	}
+
+	for i, queueName := range queues {
+		if w.tryProcessQueue(queueName) {
+			if i < len(queues)-1 {
+				w.streak++
+			} else {
+				w.streak = 0
+			}
+			return true
+		}
+	}
+	w.streak = 0
+	return false
+}
+
+// tryProcessQueue processes the messages waiting in a queue without long
+// polling, reporting whether there were any. A rate limited queue that is
+// out of tokens is passed over rather than waited on, so it doesn't hold up
+// the queues after it.
+func (w *QueueWorker) tryProcessQueue(queueName string) bool {
+	batch := MaxReceiveMessages
+	if limiter, ok := w.limiters[queueName]; ok {
+		if batch = limiter.TryTake(MaxReceiveMessages); batch == 0 {
+			return false
+		}
+	}
+
+	result, err := TryReceiveMessageFromQueue(w.ctx, queueName, int32(batch))
+	if err != nil {
+		w.returnBatch(queueName, batch)
+		if w.ctx.Err() == nil {
+			log.Printf("Error processing queue %s: %v", queueName, err)
+		}
+		return false
+	}
+	w.returnBatch(queueName, batch-len(result.Messages))
+	if len(result.Messages) == 0 {
+		return false
+	}
+
+	w.processMessages(queueName, result.Messages)
+	return true
+}
+
+// sleep waits for d or until the worker is stopped
+func (w *QueueWorker) sleep(d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-w.ctx.Done():
+	}
+}
+
 // processQueue processes messages from a specific queue
 func (w *QueueWorker) processQueue(queueName string) error {
+	batch, err := w.takeBatch(queueName)
+	if err != nil {
+		return nil
+	}
+
 	// Receive messages from the queue
-	result, err := ReceiveMessageFromQueue(queueName)
+	result, err := ReceiveMessageFromQueueWithContext(w.ctx, queueName, int32(batch))
 	if err != nil {
+		w.returnBatch(queueName, batch)
+		if w.ctx.Err() != nil {
+			return nil
+		}
 		return err
 	}
+	w.returnBatch(queueName, batch-len(result.Messages))
+
+	w.processMessages(queueName, result.Messages)
+	return nil
+}
+
+// takeBatch returns how many messages to receive from a queue. A rate
+// limited queue waits for a token first and receives no more messages than
+// it holds tokens for, so nothing sits received but unstarted while its
+// visibility timeout runs down. It only fails when the worker is stopped.
+func (w *QueueWorker) takeBatch(queueName string) (int, error) {
+	limiter, ok := w.limiters[queueName]
+	if !ok {
+		return MaxReceiveMessages, nil
+	}
+	return limiter.Take(w.ctx, MaxReceiveMessages)
+}
+
+// returnBatch hands back the tokens of a batch that received fewer messages than it could
+func (w *QueueWorker) returnBatch(queueName string, unused int) {
+	if limiter, ok := w.limiters[queueName]; ok {
+		limiter.Return(unused)
+	}
+}
 
-	if len(result.Messages) > 0 {
-		log.Printf("Processing %d messages from queue %s", len(result.Messages), queueName)
+// processMessages processes a batch of received messages concurrently
+func (w *QueueWorker) processMessages(queueName string, messages []types.Message) {
+	if len(messages) > 0 {
+		log.Printf("Processing %d messages from queue %s", len(messages), queueName)
 
 		// Process messages concurrently
 		var wg sync.WaitGroup
-		for _, message := range result.Messages {
+		for _, message := range messages {
 			wg.Add(1)
 			go func(msg types.Message) {
 				defer wg.Done()
-				if err := w.processMessageWithQueue(&msg, queueName); err != nil {
+				start := w.metrics.begin(isRedelivery(&msg))
+				err := w.processMessageWithQueue(&msg, queueName)
+				w.metrics.finish(start, err)
+				if err != nil {
 					log.Printf("Error processing message from queue %s: %v", queueName, err)
 				}
 			}(message)
 		}
 		wg.Wait()
 	}
+}
 
-	return nil
+// isRedelivery reports whether SQS has delivered the message before
+func isRedelivery(message *types.Message) bool {
+	count, err := strconv.Atoi(message.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)])
+	return err == nil && count > 1
 }
 
 // processMessageWithQueue processes a message with queue context
@@ -113,7 +278,29 @@ func (w *QueueWorker) processMessageWithQueue(message *types.Message, queueName
 	return nil
 }
 
+// Processed returns how many messages the worker has handled, failed or not
+func (w *QueueWorker) Processed() int64 {
+	return w.metrics.processed.Load()
+}
+
+// Done is closed once the worker has stopped polling
+func (w *QueueWorker) Done() <-chan struct{} {
+	return w.done
+}
+
 // Stop stops the queue worker
 func (w *QueueWorker) Stop() {
 	w.cancel()
 }
+
+// Shutdown stops the worker and waits for the in-flight polling round to finish
+func (w *QueueWorker) Shutdown(ctx context.Context) error {
+	w.cancel()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// latencySampleSize is how many recent processing times the p95 is taken over
+const latencySampleSize = 1024
+
+// QueueWorkerMetrics is a snapshot of a worker's job processing
+type QueueWorkerMetrics struct {
+	Processed int64
+	Failed    int64
+	Retried   int64
+	InFlight  int64
+	P95       time.Duration
+}
+
+// workerMetrics records job outcomes and recent processing times
+type workerMetrics struct {
+	processed atomic.Int64
+	failed    atomic.Int64
+	retried   atomic.Int64
+	inFlight  atomic.Int64
+
+	latencies []time.Duration
+	next      int
+	mutex     sync.Mutex
+}
+
+// begin records a job starting, counting it as a retry if it was delivered before
+func (m *workerMetrics) begin(retry bool) time.Time {
+	m.inFlight.Add(1)
+	if retry {
+		m.retried.Add(1)
+	}
+	return time.Now()
+}
+
+// finish records the outcome of a job started at start
+func (m *workerMetrics) finish(start time.Time, err error) {
+	m.inFlight.Add(-1)
+	m.processed.Add(1)
+	if err != nil {
+		m.failed.Add(1)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if len(m.latencies) < latencySampleSize {
+		m.latencies = append(m.latencies, time.Since(start))
+		return
+	}
+	m.latencies[m.next] = time.Since(start)
+	m.next = (m.next + 1) % latencySampleSize
+}
+
+// p95 returns the 95th percentile of the recent processing times
+func (m *workerMetrics) p95() time.Duration {
+	m.mutex.Lock()
+	latencies := slices.Clone(m.latencies)
+	m.mutex.Unlock()
+
+	if len(latencies) == 0 {
+		return 0
+	}
+	slices.Sort(latencies)
+	return latencies[(len(latencies)*95+99)/100-1]
+}
+
+// GetMetrics returns the worker's job counts and the p95 processing time of
+// its most recent jobs
+func (w *QueueWorker) GetMetrics() QueueWorkerMetrics {
+	return QueueWorkerMetrics{
+		Processed: w.metrics.processed.Load(),
+		Failed:    w.metrics.failed.Load(),
+		Retried:   w.metrics.retried.Load(),
+		InFlight:  w.metrics.inFlight.Load(),
+		P95:       w.metrics.p95(),
+	}
+}
+
+// GetStats returns the worker metrics for the metrics registry
+func (w *QueueWorker) GetStats() map[string]interface{} {
+	metrics := w.GetMetrics()
+	return map[string]interface{}{
+		"jobs_processed_total": metrics.Processed,
+		"jobs_failed_total":    metrics.Failed,
+		"jobs_retried_total":   metrics.Retried,
+		"jobs_in_flight":       metrics.InFlight,
+		"job_p95_seconds":      metrics.P95.Seconds(),
+	}
+}
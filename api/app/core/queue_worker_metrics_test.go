@@ -0,0 +1,89 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// outcomeJobs is a job dispatcher failing every job whose payload is "fail"
+type outcomeJobs struct {
+	JobDispatcherService
+}
+
+func (j outcomeJobs) ProcessJobFromQueue(jobData []byte, jobType string) error {
+	if string(jobData) == "fail" {
+		return errors.New("job failed")
+	}
+	return nil
+}
+
+// deletingQueue is a queue service that accepts every delete
+type deletingQueue struct {
+	QueueService
+}
+
+func (deletingQueue) DeleteMessageFromQueue(receiptHandle string, queueName string) error {
+	return nil
+}
+
+// jobMessage builds a received message with body, delivered receives times
+func jobMessage(body string, receives string) types.Message {
+	return types.Message{
+		Body:          aws.String(body),
+		ReceiptHandle: aws.String("handle"),
+		Attributes: map[string]string{
+			string(types.MessageSystemAttributeNameApproximateReceiveCount): receives,
+		},
+	}
+}
+
+func TestGetMetricsCountsJobOutcomes(t *testing.T) {
+	previousJobs, previousMessages := JobDispatcherServiceInstance, MessageProcessorServiceInstance
+	SetJobDispatcherService(outcomeJobs{})
+	SetMessageProcessorService(NewMessageProcessorProvider())
+	t.Cleanup(func() {
+		SetJobDispatcherService(previousJobs)
+		SetMessageProcessorService(previousMessages)
+	})
+	withQueueService(t, deletingQueue{})
+
+	worker := NewQueueWorker([]string{"jobs"})
+	defer worker.Stop()
+
+	worker.processMessages("jobs", []types.Message{
+		jobMessage("ok", "1"),
+		jobMessage("ok", "1"),
+		jobMessage("ok", "3"),
+		jobMessage("fail", "1"),
+		jobMessage("fail", "2"),
+	})
+
+	metrics := worker.GetMetrics()
+	if metrics.Processed != 5 || metrics.Failed != 2 || metrics.Retried != 2 || metrics.InFlight != 0 {
+		t.Fatalf("expected 5 processed, 2 failed, 2 retried and none in flight, got %+v", metrics)
+	}
+
+	stats := worker.GetStats()
+	if stats["jobs_processed_total"] != int64(5) || stats["jobs_failed_total"] != int64(2) || stats["jobs_retried_total"] != int64(2) {
+		t.Fatalf("expected the stats to match the metrics, got %v", stats)
+	}
+}
+
+func TestWorkerMetricsP95(t *testing.T) {
+	var metrics workerMetrics
+	if metrics.p95() != 0 {
+		t.Fatal("expected no p95 before any job")
+	}
+
+	// 100 samples of 1ms..100ms put the p95 at 95ms
+	for i := 1; i <= 100; i++ {
+		metrics.latencies = append(metrics.latencies, time.Duration(i)*time.Millisecond)
+	}
+	if p95 := metrics.p95(); p95 != 95*time.Millisecond {
+		t.Fatalf("expected a p95 of 95ms, got %v", p95)
+	}
+}
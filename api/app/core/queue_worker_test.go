@@ -0,0 +1,151 @@
+package core
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// receiveRecorder is a queue service recording how many messages each
+// receive asked for and returning up to available messages
+type receiveRecorder struct {
+	QueueService
+	mutex     sync.Mutex
+	requested []int32
+	available int
+}
+
+func (r *receiveRecorder) ReceiveMessageFromQueueWithContext(ctx context.Context, queueName string, maxMessages int32) (*sqs.ReceiveMessageOutput, error) {
+	return r.TryReceiveMessageFromQueue(ctx, queueName, maxMessages)
+}
+
+func (r *receiveRecorder) TryReceiveMessageFromQueue(ctx context.Context, queueName string, maxMessages int32) (*sqs.ReceiveMessageOutput, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.requested = append(r.requested, maxMessages)
+
+	count := min(int(maxMessages), r.available)
+	r.available -= count
+	// Messages without a body fail before any job runs
+	return &sqs.ReceiveMessageOutput{Messages: make([]types.Message, count)}, nil
+}
+
+func withQueueService(t *testing.T, service QueueService) {
+	t.Helper()
+	previous := QueueServiceInstance
+	SetQueueService(service)
+	t.Cleanup(func() { SetQueueService(previous) })
+}
+
+func TestRateLimitedQueueReceivesNoMoreThanItsTokens(t *testing.T) {
+	queue := &receiveRecorder{available: 1}
+	withQueueService(t, queue)
+
+	worker := NewQueueWorker([]string{"jobs"})
+	defer worker.Stop()
+	worker.SetRateLimit("jobs", 0.001, 3)
+
+	if err := worker.processQueue("jobs"); err != nil {
+		t.Fatal(err)
+	}
+	if len(queue.requested) != 1 || queue.requested[0] != 3 {
+		t.Fatalf("expected a receive of at most 3 messages, got %v", queue.requested)
+	}
+
+	// Only one message arrived, so two tokens are left for the next receive
+	worker.tryProcessQueue("jobs")
+	if len(queue.requested) != 2 || queue.requested[1] != 2 {
+		t.Fatalf("expected the unused tokens to be returned, got %v", queue.requested)
+	}
+}
+
+func TestRateLimitedQueueOutOfTokensIsPassedOver(t *testing.T) {
+	queue := &receiveRecorder{available: 10}
+	withQueueService(t, queue)
+
+	worker := NewPriorityQueueWorker([]string{"jobs", "mail"}, 0)
+	defer worker.Stop()
+	worker.SetRateLimit("jobs", 0.001, 1)
+
+	worker.tryProcessQueue("jobs")
+	if worker.tryProcessQueue("jobs") {
+		t.Fatal("expected a queue without tokens to be skipped")
+	}
+	if len(queue.requested) != 1 {
+		t.Fatalf("expected no receive without a token, got %v", queue.requested)
+	}
+	if !worker.tryProcessQueue("mail") || queue.requested[1] != MaxReceiveMessages {
+		t.Fatalf("expected an unlimited queue to receive a full batch, got %v", queue.requested)
+	}
+}
+
+// priorityRecorder is a queue service holding a number of messages per
+// queue and recording which queue each non-empty receive was served from
+type priorityRecorder struct {
+	QueueService
+	mutex     sync.Mutex
+	available map[string]int
+	served    []string
+}
+
+func (r *priorityRecorder) TryReceiveMessageFromQueue(ctx context.Context, queueName string, maxMessages int32) (*sqs.ReceiveMessageOutput, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	count := min(int(maxMessages), r.available[queueName])
+	r.available[queueName] -= count
+	if count > 0 {
+		r.served = append(r.served, queueName)
+	}
+	return &sqs.ReceiveMessageOutput{Messages: make([]types.Message, count)}, nil
+}
+
+// drainByPriority runs priority rounds until every queue is empty, returning
+// the queues in the order they were served
+func drainByPriority(t *testing.T, fairnessQuota int, available map[string]int) []string {
+	t.Helper()
+	queue := &priorityRecorder{available: available}
+	withQueueService(t, queue)
+
+	worker := NewPriorityQueueWorker([]string{"high", "default", "low"}, fairnessQuota)
+	defer worker.Stop()
+
+	for rounds := 0; worker.processByPriority(); rounds++ {
+		if rounds > 100 {
+			t.Fatal("expected the queues to drain")
+		}
+	}
+	return queue.served
+}
+
+func TestPriorityWorkerDrainsQueuesInOrder(t *testing.T) {
+	served := drainByPriority(t, 0, map[string]int{"high": 20, "default": 10, "low": 5})
+
+	want := []string{"high", "high", "default", "low"}
+	if !reflect.DeepEqual(served, want) {
+		t.Fatalf("expected %v, got %v", want, served)
+	}
+}
+
+func TestPriorityWorkerGivesLowerQueuesTurnsAfterQuota(t *testing.T) {
+	served := drainByPriority(t, 2, map[string]int{"high": 50, "default": 20, "low": 20})
+
+	// Every two rounds from a higher queue, the lower queues take turns
+	want := []string{"high", "high", "default", "high", "high", "low", "high", "default", "low"}
+	if !reflect.DeepEqual(served, want) {
+		t.Fatalf("expected %v, got %v", want, served)
+	}
+}
+
+func TestPriorityWorkerFairTurnFallsBackWhenLowerQueuesAreEmpty(t *testing.T) {
+	served := drainByPriority(t, 1, map[string]int{"high": 40})
+
+	want := []string{"high", "high", "high", "high"}
+	if !reflect.DeepEqual(served, want) {
+		t.Fatalf("expected the high queue to keep being served, got %v", served)
+	}
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBucket limits work to a steady rate per second while allowing short
+// bursts of up to burst operations
+type TokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	mutex  sync.Mutex
+}
+
+// NewTokenBucket creates a full token bucket refilling at rate tokens per second
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Rate returns the refill rate in tokens per second
+func (b *TokenBucket) Rate() float64 {
+	return b.rate
+}
+
+// Wait blocks until a token is available or ctx is done
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	_, err := b.Take(ctx, 1)
+	return err
+}
+
+// Take blocks until at least one token is available, then takes as many of
+// the available tokens as it can, up to limit, and returns how many it took.
+// Tokens that go unused should be handed back with Return.
+func (b *TokenBucket) Take(ctx context.Context, limit int) (int, error) {
+	taken, delay := b.reserve(limit)
+	if delay <= 0 {
+		return taken, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return taken, nil
+	case <-ctx.Done():
+		b.Return(taken)
+		return 0, ctx.Err()
+	}
+}
+
+// TryTake takes as many of the available tokens as it can, up to limit,
+// without waiting. It returns zero if no whole token is available.
+func (b *TokenBucket) TryTake(limit int) int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill()
+	taken := min(int(b.tokens), max(limit, 1))
+	if taken < 1 {
+		return 0
+	}
+	b.tokens -= float64(taken)
+	return taken
+}
+
+// Return gives back tokens that were taken but never used
+func (b *TokenBucket) Return(n int) {
+	if n <= 0 {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.tokens = min(b.tokens+float64(n), b.burst)
+}
+
+// reserve takes up to limit whole tokens, or a single token in debt if none are
+// available, and returns how many it took and how long the caller must wait
+// for them to become valid
+func (b *TokenBucket) reserve(limit int) (int, time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill()
+	if available := int(b.tokens); available >= 1 {
+		taken := min(available, max(limit, 1))
+		b.tokens -= float64(taken)
+		return taken, 0
+	}
+
+	b.tokens--
+	return 1, time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// refill adds the tokens earned since the last call; the mutex must be held
+func (b *TokenBucket) refill() {
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketTakeTakesAvailableTokensUpToLimit(t *testing.T) {
+	bucket := NewTokenBucket(1, 5)
+
+	if taken, err := bucket.Take(context.Background(), 3); err != nil || taken != 3 {
+		t.Fatalf("expected 3 tokens, got %d, %v", taken, err)
+	}
+	if taken, err := bucket.Take(context.Background(), 10); err != nil || taken != 2 {
+		t.Fatalf("expected the 2 remaining tokens, got %d, %v", taken, err)
+	}
+	if taken := bucket.TryTake(10); taken != 0 {
+		t.Fatalf("expected an empty bucket, got %d tokens", taken)
+	}
+
+	bucket.Return(2)
+	if taken := bucket.TryTake(10); taken != 2 {
+		t.Fatalf("expected the returned tokens back, got %d", taken)
+	}
+}
+
+func TestTokenBucketTakeWaitsForAToken(t *testing.T) {
+	bucket := NewTokenBucket(50, 1)
+	bucket.TryTake(1)
+
+	started := time.Now()
+	if taken, err := bucket.Take(context.Background(), 10); err != nil || taken != 1 {
+		t.Fatalf("expected a single token, got %d, %v", taken, err)
+	}
+	if elapsed := time.Since(started); elapsed < 10*time.Millisecond {
+		t.Fatalf("expected to wait for the token to refill, waited %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := bucket.Take(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a cancelled wait to fail, got %v", err)
+	}
+}
@@ -2,15 +2,33 @@ package core
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 )
 
+// ErrInsufficientBudget is returned when a context deadline leaves too little
+// time for a cache round-trip; callers should go straight to the source.
+var ErrInsufficientBudget = errors.New("insufficient time budget for cache operation")
+
 // RedisCacheDriver implements Redis caching
 type RedisCacheDriver struct {
 	*BaseCacheProvider
-	client *redis.Client
+	client    *redis.Client
+	minBudget time.Duration
+	scanCount int64
+	hits      atomic.Int64
+	misses    atomic.Int64
+	errors    atomic.Int64
+
+	compressThreshold int
+	compressedWrites  atomic.Int64
+	bytesSaved        atomic.Int64
 }
 
 // NewRedisCacheDriver creates a new Redis cache driver
@@ -21,27 +39,235 @@ func NewRedisCacheDriver(client *redis.Client, prefix string, ttl time.Duration)
 	}
 }
 
+// SetMinBudget sets the minimum remaining context time required before a
+// context-aware read is attempted. Zero disables the check.
+func (d *RedisCacheDriver) SetMinBudget(budget time.Duration) {
+	d.minBudget = budget
+}
+
+// defaultScanCount is the SCAN COUNT hint and the deletion batch size used
+// by DeletePattern unless SetScanCount overrides it
+const defaultScanCount = 500
+
+// SetScanCount sets how many keys DeletePattern asks SCAN for per call,
+// which is also the most keys it unlinks at once. Zero restores the default.
+func (d *RedisCacheDriver) SetScanCount(count int64) {
+	d.scanCount = count
+}
+
 // Get retrieves a value from Redis cache
 func (d *RedisCacheDriver) Get(key string) (interface{}, bool) {
+	val, exists, _ := d.GetWithContext(context.Background(), key)
+	return val, exists
+}
+
+// GetWithContext retrieves a value from Redis cache, skipping the backend
+// with ErrInsufficientBudget when the context deadline is too close
+func (d *RedisCacheDriver) GetWithContext(ctx context.Context, key string) (interface{}, bool, error) {
+	if !d.hasBudget(ctx) {
+		return nil, false, ErrInsufficientBudget
+	}
+
+	fullKey := d.GetFullKey(key)
+
+	val, err := redisRead(ctx, "GET", key, func() (string, error) {
+		return d.client.Get(ctx, fullKey).Result()
+	})
+	if err == redis.Nil {
+		d.misses.Add(1)
+		return nil, false, nil
+	}
+	if err != nil {
+		d.errors.Add(1)
+		return nil, false, err
+	}
+
+	d.hits.Add(1)
+	return decodeValue(val), true, nil
+}
+
+// Pull retrieves a value and deletes it in one step, so concurrent pulls of
+// the same key never both receive it
+func (d *RedisCacheDriver) Pull(key string) (interface{}, bool, error) {
+	return d.PullWithContext(context.Background(), key)
+}
+
+// PullWithContext retrieves and deletes a value. GET and DEL run in a
+// MULTI/EXEC transaction since GETDEL needs Redis 6.2. Like other writes it
+// is never abandoned once sent, since the key would be deleted with its
+// value lost; a cancelled ctx only stops the transaction from being sent.
+func (d *RedisCacheDriver) PullWithContext(ctx context.Context, key string) (interface{}, bool, error) {
 	fullKey := d.GetFullKey(key)
-	ctx := context.Background()
 
-	val, err := d.client.Get(ctx, fullKey).Result()
+	var get *redis.StringCmd
+	_, err := redisWrite(ctx, "GET/DEL", key, func(ctx context.Context) ([]redis.Cmder, error) {
+		return d.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			get = pipe.Get(ctx, fullKey)
+			pipe.Del(ctx, fullKey)
+			return nil
+		})
+	})
+	if err == redis.Nil {
+		d.misses.Add(1)
+		return nil, false, nil
+	}
 	if err != nil {
-		return nil, false
+		d.errors.Add(1)
+		return nil, false, err
 	}
 
-	return val, true
+	d.hits.Add(1)
+	return decodeValue(get.Val()), true, nil
+}
+
+// GetManyWithContext retrieves several values in one MGET, leaving keys
+// that are missing out of the result
+func (d *RedisCacheDriver) GetManyWithContext(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	if len(keys) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	if !d.hasBudget(ctx) {
+		return nil, ErrInsufficientBudget
+	}
+
+	fullKeys := make([]string, len(keys))
+	for i, key := range keys {
+		fullKeys[i] = d.GetFullKey(key)
+	}
+
+	values, err := redisRead(ctx, "MGET", strings.Join(keys, " "), func() ([]interface{}, error) {
+		return d.client.MGet(ctx, fullKeys...).Result()
+	})
+	if err != nil {
+		d.errors.Add(1)
+		return nil, err
+	}
+
+	results := make(map[string]interface{}, len(keys))
+	for i, value := range values {
+		if value == nil {
+			d.misses.Add(1)
+			continue
+		}
+		d.hits.Add(1)
+		if str, ok := value.(string); ok {
+			value = decodeValue(str)
+		}
+		results[keys[i]] = value
+	}
+	return results, nil
+}
+
+// redisRead runs a read with ctx handed straight to go-redis, which closes
+// the connection and returns as soon as ctx is done. A read cut short returns
+// ctx's error wrapped in the command and key, never a partial value.
+func redisRead[R any](ctx context.Context, command, key string, fn func() (R, error)) (R, error) {
+	result, err := fn()
+	if err != nil {
+		if ctxErr := redisContextErr(ctx); ctxErr != nil {
+			var zero R
+			return zero, fmt.Errorf("redis %s %s: %w", command, key, ctxErr)
+		}
+	}
+	return result, err
+}
+
+// redisWrite runs a write that must not be abandoned once sent. go-redis
+// gives up on a cancelled context even after the command reached the server,
+// so the write would land while reporting failure. fn therefore gets a
+// context that keeps ctx's deadline and values but ignores cancellation: a
+// cancelled ctx only stops the write from being sent. A write cut off by the
+// deadline may still have landed and returns ctx's error wrapped.
+func redisWrite[R any](ctx context.Context, command, key string, fn func(context.Context) (R, error)) (R, error) {
+	if err := ctx.Err(); err != nil {
+		var zero R
+		return zero, fmt.Errorf("redis %s %s: %w", command, key, err)
+	}
+
+	writeCtx := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		writeCtx, cancel = context.WithDeadline(writeCtx, deadline)
+		defer cancel()
+	}
+
+	result, err := fn(writeCtx)
+	if err != nil {
+		if ctxErr := redisContextErr(writeCtx); ctxErr != nil {
+			return result, fmt.Errorf("redis %s %s: %w", command, key, ctxErr)
+		}
+	}
+	return result, err
+}
+
+// redisContextErr returns ctx's error, counting a passed deadline as
+// exceeded before ctx's own timer fires. go-redis sets the same deadline on
+// the connection, whose read can time out first.
+func redisContextErr(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) {
+		return context.DeadlineExceeded
+	}
+	return nil
+}
+
+// hasBudget reports whether ctx leaves enough time for a backend call
+func (d *RedisCacheDriver) hasBudget(ctx context.Context) bool {
+	if d.minBudget <= 0 {
+		return true
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return true
+	}
+	return time.Until(deadline) >= d.minBudget
 }
 
 // Set stores a value in Redis cache
 func (d *RedisCacheDriver) Set(key string, value interface{}, ttl ...time.Duration) error {
+	return d.SetWithContext(context.Background(), key, value, ttl...)
+}
+
+// SetWithContext stores a value in Redis cache. A cancelled ctx stops the
+// write from being sent but never abandons one in flight; see redisWrite.
+func (d *RedisCacheDriver) SetWithContext(ctx context.Context, key string, value interface{}, ttl ...time.Duration) error {
 	fullKey := d.GetFullKey(key)
+	duration := d.GetEffectiveTTL(ttl...)
+
+	_, err := redisWrite(ctx, "SET", key, func(ctx context.Context) (string, error) {
+		return d.client.Set(ctx, fullKey, d.encodeValue(value), duration).Result()
+	})
+	return err
+}
+
+// SetMany stores several values atomically: either every key is written or,
+// if the transaction fails, none are. Values without an expiry are written
+// with a single MSET, others in a MULTI/EXEC transaction.
+func (d *RedisCacheDriver) SetMany(values map[string]interface{}, ttl ...time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
 	ctx := context.Background()
 
+	// One roll of the jitter for the batch, so keys written together expire together
 	duration := d.GetEffectiveTTL(ttl...)
+	if duration == 0 {
+		pairs := make([]interface{}, 0, len(values)*2)
+		for key, value := range values {
+			pairs = append(pairs, d.GetFullKey(key), d.encodeValue(value))
+		}
+		return d.client.MSet(ctx, pairs...).Err()
+	}
 
-	return d.client.Set(ctx, fullKey, value, duration).Err()
+	_, err := d.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, value := range values {
+			pipe.Set(ctx, d.GetFullKey(key), d.encodeValue(value), duration)
+		}
+		return nil
+	})
+	return err
 }
 
 // Delete removes a value from Redis cache
@@ -51,6 +277,66 @@ func (d *RedisCacheDriver) Delete(key string) error {
 	return d.client.Del(ctx, fullKey).Err()
 }
 
+// DeletePattern removes every key matching a glob pattern, e.g. "users:*",
+// returning how many were removed
+func (d *RedisCacheDriver) DeletePattern(pattern string) (int64, error) {
+	return d.DeletePatternWithContext(context.Background(), pattern)
+}
+
+// DeletePatternWithContext removes every key matching a glob pattern. Keys
+// are unlinked batch by batch as SCAN returns them, so memory stays bounded
+// on a large keyspace and Redis reclaims the values in the background
+// instead of blocking on one large DEL. Keys written during the scan may or
+// may not be removed.
+func (d *RedisCacheDriver) DeletePatternWithContext(ctx context.Context, pattern string) (int64, error) {
+	count := d.scanCount
+	if count <= 0 {
+		count = defaultScanCount
+	}
+
+	var deleted int64
+	var cursor uint64
+	batch := make([]string, 0, count)
+	for {
+		keys, next, err := d.client.Scan(ctx, cursor, d.GetFullKey(pattern), count).Result()
+		if err != nil {
+			return deleted, fmt.Errorf("redis SCAN %s: %w", pattern, err)
+		}
+
+		for len(keys) > 0 {
+			n := int(count) - len(batch)
+			if n > len(keys) {
+				n = len(keys)
+			}
+			batch = append(batch, keys[:n]...)
+			keys = keys[n:]
+
+			if int64(len(batch)) >= count {
+				removed, err := d.client.Unlink(ctx, batch...).Result()
+				deleted += removed
+				if err != nil {
+					return deleted, fmt.Errorf("redis UNLINK %s: %w", pattern, err)
+				}
+				batch = batch[:0]
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	if len(batch) > 0 {
+		removed, err := d.client.Unlink(ctx, batch...).Result()
+		deleted += removed
+		if err != nil {
+			return deleted, fmt.Errorf("redis UNLINK %s: %w", pattern, err)
+		}
+	}
+	return deleted, nil
+}
+
 // Has checks if a key exists in Redis cache
 func (d *RedisCacheDriver) Has(key string) bool {
 	fullKey := d.GetFullKey(key)
@@ -60,21 +346,161 @@ func (d *RedisCacheDriver) Has(key string) bool {
 	return err == nil
 }
 
+// Ping verifies the Redis server is reachable
+func (d *RedisCacheDriver) Ping(ctx context.Context) error {
+	if err := d.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("redis cache at %s is unreachable: %w", d.client.Options().Addr, err)
+	}
+	return nil
+}
+
+// IsConnected reports whether the Redis server currently answers a PING
+func (d *RedisCacheDriver) IsConnected() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return d.Ping(ctx) == nil
+}
+
+// redisInfoStats are the INFO fields reported by GetStats
+var redisInfoStats = map[string]string{
+	"used_memory":       "used_memory_bytes",
+	"connected_clients": "connected_clients",
+	"keyspace_hits":     "server_hits",
+	"keyspace_misses":   "server_misses",
+	"evicted_keys":      "evicted_keys",
+	"expired_keys":      "expired_keys",
+}
+
+// GetStats returns this driver's read counts and hit rate alongside figures
+// from the server's INFO output, which cover every client of the server
+func (d *RedisCacheDriver) GetStats() map[string]interface{} {
+	hits, misses := d.hits.Load(), d.misses.Load()
+	stats := map[string]interface{}{
+		"hits":                    hits,
+		"misses":                  misses,
+		"errors":                  d.errors.Load(),
+		"hit_rate":                hitRate(hits, misses),
+		"connected":               false,
+		"compressed_writes":       d.compressedWrites.Load(),
+		"compression_bytes_saved": d.bytesSaved.Load(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	info, err := d.client.Info(ctx).Result()
+	if err != nil {
+		return stats
+	}
+	stats["connected"] = true
+
+	for _, line := range strings.Split(info, "\n") {
+		name, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found {
+			continue
+		}
+		if stat, ok := redisInfoStats[name]; ok {
+			if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+				stats[stat] = number
+			}
+		}
+	}
+
+	serverHits, _ := stats["server_hits"].(int64)
+	serverMisses, _ := stats["server_misses"].(int64)
+	stats["server_hit_rate"] = hitRate(serverHits, serverMisses)
+	return stats
+}
+
+// hitRate returns hits as a fraction of all lookups
+func hitRate(hits, misses int64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
 // Flush clears all Redis cache
 func (d *RedisCacheDriver) Flush() error {
 	ctx := context.Background()
 	return d.client.FlushDB(ctx).Err()
 }
 
+// releaseLockScript deletes a lock only if it is still held by the given owner
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Lock attempts to acquire a distributed lock, returning an owner token on success
+func (d *RedisCacheDriver) Lock(key string, ttl time.Duration) (string, bool, error) {
+	fullKey := d.GetFullKey(key)
+	ctx := context.Background()
+
+	owner, err := newLockOwner()
+	if err != nil {
+		return "", false, err
+	}
+
+	acquired, err := d.client.SetNX(ctx, fullKey, owner, ttl).Result()
+	if err != nil || !acquired {
+		return "", false, err
+	}
+	return owner, true, nil
+}
+
+// Unlock releases a distributed lock if it is still held by owner
+func (d *RedisCacheDriver) Unlock(key string, owner string) error {
+	fullKey := d.GetFullKey(key)
+	ctx := context.Background()
+	return releaseLockScript.Run(ctx, d.client, []string{fullKey}, owner).Err()
+}
+
 // Increment increments a numeric value in Redis cache
 func (d *RedisCacheDriver) Increment(key string, value ...int64) (int64, error) {
+	return d.IncrementWithContext(context.Background(), key, value...)
+}
+
+// IncrementWithContext increments a numeric value in Redis cache. Like
+// SetWithContext it never abandons an increment already sent.
+func (d *RedisCacheDriver) IncrementWithContext(ctx context.Context, key string, value ...int64) (int64, error) {
+	fullKey := d.GetFullKey(key)
+
+	return redisWrite(ctx, "INCRBY", key, func(ctx context.Context) (int64, error) {
+		if len(value) > 0 {
+			return d.client.IncrBy(ctx, fullKey, value[0]).Result()
+		}
+		return d.client.Incr(ctx, fullKey).Result()
+	})
+}
+
+// incrementWithTTLScript increments a counter and starts its expiry on the
+// first increment, returning the new count and the remaining TTL in ms. A
+// counter left without an expiry, e.g. by a plain INCR, gets one too, so it
+// can't block its key forever.
+var incrementWithTTLScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+local ttl = redis.call("PTTL", KEYS[1])
+if count == 1 or ttl < 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {count, ttl}
+`)
+
+// IncrementWithTTL increments a counter that expires ttl after its first
+// increment, returning the new count and the time left until it resets
+func (d *RedisCacheDriver) IncrementWithTTL(key string, ttl time.Duration) (int64, time.Duration, error) {
 	fullKey := d.GetFullKey(key)
 	ctx := context.Background()
 
-	if len(value) > 0 {
-		return d.client.IncrBy(ctx, fullKey, value[0]).Result()
+	result, err := incrementWithTTLScript.Run(ctx, d.client, []string{fullKey}, ttl.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, 0, err
 	}
-	return d.client.Incr(ctx, fullKey).Result()
+	return result[0], time.Duration(result[1]) * time.Millisecond, nil
 }
 
 // Decrement decrements a numeric value in Redis cache
@@ -0,0 +1,695 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// fakeRedis is an in-process RESP server implementing the handful of
+// commands the cache driver uses, in the spirit of miniredis
+type fakeRedis struct {
+	mutex   sync.Mutex
+	data    map[string]string
+	unlinks []int
+	cursors []string
+	delay   time.Duration
+	calls   int
+	hits    int
+	misses  int
+	// dropInMulti makes the server hang up once a transaction has queued
+	// that many commands, as a crash mid-write would
+	dropInMulti int
+	addr        string
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeRedis{data: map[string]string{}, addr: listener.Addr().String()}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+// driver returns a cache driver connected to the server
+func (s *fakeRedis) driver(t *testing.T) *RedisCacheDriver {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{Addr: s.addr, MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisCacheDriver(client, "t:", time.Minute)
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	var queued [][]string
+	inMulti := false
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		var reply string
+		switch command := strings.ToUpper(args[0]); {
+		case command == "MULTI":
+			inMulti, queued = true, nil
+			reply = "+OK\r\n"
+		case command == "EXEC":
+			reply = s.exec(queued)
+			inMulti, queued = false, nil
+		case inMulti:
+			queued = append(queued, args)
+			if s.dropsAt(len(queued)) {
+				return
+			}
+			reply = "+QUEUED\r\n"
+		default:
+			reply = s.handle(args)
+		}
+		time.Sleep(s.latency())
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand reads one RESP array of bulk strings
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil || line[0] != '*' {
+		return nil, fmt.Errorf("unexpected %q", line)
+	}
+
+	args := make([]string, count)
+	for i := range args {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(header[1:]))
+		if err != nil {
+			return nil, err
+		}
+		value := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, value); err != nil {
+			return nil, err
+		}
+		args[i] = string(value[:size])
+	}
+	return args, nil
+}
+
+func (s *fakeRedis) handle(args []string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.apply(args)
+}
+
+// exec runs a transaction's queued commands without interleaving others
+func (s *fakeRedis) exec(queued [][]string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	reply := "*" + strconv.Itoa(len(queued)) + "\r\n"
+	for _, args := range queued {
+		reply += s.apply(args)
+	}
+	return reply
+}
+
+// apply runs one command; the mutex must be held
+func (s *fakeRedis) apply(args []string) string {
+	s.calls++
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "SET":
+		s.data[args[1]] = args[2]
+		return "+OK\r\n"
+	case "MSET":
+		for i := 1; i+1 < len(args); i += 2 {
+			s.data[args[i]] = args[i+1]
+		}
+		return "+OK\r\n"
+	case "GET":
+		value, ok := s.data[args[1]]
+		if !ok {
+			s.misses++
+			return "$-1\r\n"
+		}
+		s.hits++
+		return bulk(value)
+	case "INCR", "INCRBY":
+		by := 1
+		if len(args) > 2 {
+			by, _ = strconv.Atoi(args[2])
+		}
+		current, _ := strconv.Atoi(s.data[args[1]])
+		s.data[args[1]] = strconv.Itoa(current + by)
+		return ":" + s.data[args[1]] + "\r\n"
+	case "UNLINK", "DEL":
+		removed := 0
+		for _, key := range args[1:] {
+			if _, ok := s.data[key]; ok {
+				delete(s.data, key)
+				removed++
+			}
+		}
+		if strings.ToUpper(args[0]) == "UNLINK" {
+			s.unlinks = append(s.unlinks, len(args)-1)
+		}
+		return ":" + strconv.Itoa(removed) + "\r\n"
+	case "SCAN":
+		return s.scan(args[1:])
+	case "INFO":
+		return s.info()
+	}
+	return "-ERR unknown command '" + args[0] + "'\r\n"
+}
+
+// scan pages through the sorted keyspace. A cursor stands for the last key
+// returned, so keys removed mid-scan don't shift later pages, as Redis
+// guarantees for keys present throughout a scan.
+func (s *fakeRedis) scan(args []string) string {
+	cursor, _ := strconv.Atoi(args[0])
+	match, count := "*", 10
+	for i := 1; i+1 < len(args); i += 2 {
+		switch strings.ToUpper(args[i]) {
+		case "MATCH":
+			match = args[i+1]
+		case "COUNT":
+			count, _ = strconv.Atoi(args[i+1])
+		}
+	}
+
+	keys := make([]string, 0, len(s.data))
+	for key := range s.data {
+		if cursor == 0 || key > s.cursors[cursor-1] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	page := keys[:min(count, len(keys))]
+	var matched []string
+	for _, key := range page {
+		if ok, _ := path.Match(match, key); ok {
+			matched = append(matched, key)
+		}
+	}
+	next := 0
+	if len(page) < len(keys) {
+		s.cursors = append(s.cursors, page[len(page)-1])
+		next = len(s.cursors)
+	}
+
+	reply := "*2\r\n" + bulk(strconv.Itoa(next)) + "*" + strconv.Itoa(len(matched)) + "\r\n"
+	for _, key := range matched {
+		reply += bulk(key)
+	}
+	return reply
+}
+
+// info reports the stored bytes and lookup counts in INFO's format
+func (s *fakeRedis) info() string {
+	used := 0
+	for key, value := range s.data {
+		used += len(key) + len(value)
+	}
+	return bulk(fmt.Sprintf("# Memory\r\nused_memory:%d\r\n\r\n# Stats\r\nkeyspace_hits:%d\r\nkeyspace_misses:%d\r\nevicted_keys:0\r\n",
+		used, s.hits, s.misses))
+}
+
+func bulk(value string) string {
+	return "$" + strconv.Itoa(len(value)) + "\r\n" + value + "\r\n"
+}
+
+func (s *fakeRedis) fill(prefix string, n int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for i := 0; i < n; i++ {
+		s.data[fmt.Sprintf("%s%05d", prefix, i)] = "value"
+	}
+}
+
+// dropTransactionsAt makes the server hang up once a transaction queues n
+// commands; 0 stops dropping
+func (s *fakeRedis) dropTransactionsAt(n int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.dropInMulti = n
+}
+
+// dropsAt reports whether the connection is dropped with queued commands
+func (s *fakeRedis) dropsAt(queued int) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.dropInMulti > 0 && queued >= s.dropInMulti
+}
+
+// setDelay makes the server wait d after running each command before replying
+func (s *fakeRedis) setDelay(d time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.delay = d
+}
+
+func (s *fakeRedis) latency() time.Duration {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.delay
+}
+
+// commandCount returns how many commands the server has run
+func (s *fakeRedis) commandCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.calls
+}
+
+// value returns the raw stored value of key
+func (s *fakeRedis) value(key string) (string, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	value, ok := s.data[key]
+	return value, ok
+}
+
+// store sets the raw stored value of key, as another writer would
+func (s *fakeRedis) store(key, value string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.data[key] = value
+}
+
+// state returns how many keys are stored and the size of each UNLINK so far
+func (s *fakeRedis) state() (int, []int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.data), append([]int(nil), s.unlinks...)
+}
+
+func TestRedisDeletePatternUnlinksInBoundedBatches(t *testing.T) {
+	server := newFakeRedis(t)
+	server.fill("t:users:", 1250)
+	server.fill("t:posts:", 300)
+	cache := server.driver(t)
+	cache.SetScanCount(100)
+
+	deleted, err := cache.DeletePattern("users:*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1250 {
+		t.Fatalf("expected 1250 keys deleted, got %d", deleted)
+	}
+
+	remaining, unlinks := server.state()
+	if remaining != 300 {
+		t.Fatalf("expected only the posts to remain, got %d keys", remaining)
+	}
+	total := 0
+	for _, size := range unlinks {
+		if size > 100 {
+			t.Fatalf("expected no UNLINK of more than 100 keys, got %d", size)
+		}
+		total += size
+	}
+	if total != 1250 {
+		t.Fatalf("expected every matching key to be unlinked once, got %d", total)
+	}
+}
+
+func TestRedisDeletePatternWithoutMatches(t *testing.T) {
+	server := newFakeRedis(t)
+	server.fill("t:posts:", 20)
+	cache := server.driver(t)
+
+	deleted, err := cache.DeletePattern("users:*")
+	if err != nil || deleted != 0 {
+		t.Fatalf("expected nothing deleted, got %d, %v", deleted, err)
+	}
+	if _, unlinks := server.state(); len(unlinks) != 0 {
+		t.Fatalf("expected no UNLINK without matches, got %v", unlinks)
+	}
+}
+
+func TestRedisDeletePatternStopsWhenContextIsDone(t *testing.T) {
+	server := newFakeRedis(t)
+	server.fill("t:users:", 20)
+	cache := server.driver(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := cache.DeletePatternWithContext(ctx, "users:*"); err == nil {
+		t.Fatal("expected a cancelled context to stop the scan")
+	}
+	if remaining, _ := server.state(); remaining != 20 {
+		t.Fatalf("expected no keys removed, got %d left", remaining)
+	}
+}
+
+func TestRedisSetAndGetThroughServer(t *testing.T) {
+	server := newFakeRedis(t)
+	cache := server.driver(t)
+
+	if err := cache.Set("greeting", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if value, ok := cache.Get("greeting"); !ok || value != "hello" {
+		t.Fatalf("expected hello, got %v, %v", value, ok)
+	}
+	if _, ok := cache.Get("missing"); ok {
+		t.Fatal("expected a missing key to miss")
+	}
+}
+
+func TestRedisReadsReturnPromptlyWhenCancelledInFlight(t *testing.T) {
+	server := newFakeRedis(t)
+	cache := server.driver(t)
+	if err := cache.Set("greeting", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	server.setDelay(time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	started := time.Now()
+	value, ok, err := cache.GetWithContext(ctx, "greeting")
+	if !errors.Is(err, context.Canceled) || ok || value != nil {
+		t.Fatalf("expected a wrapped context.Canceled and no value, got %v, %v, %v", value, ok, err)
+	}
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected Get to return when cancelled, took %v", elapsed)
+	}
+
+	deadline, cancelDeadline := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelDeadline()
+	values, err := cache.GetManyWithContext(deadline, []string{"greeting"})
+	if !errors.Is(err, context.DeadlineExceeded) || values != nil {
+		t.Fatalf("expected a wrapped context.DeadlineExceeded and no values, got %v, %v", values, err)
+	}
+}
+
+func TestRedisWritesAreNotAbandonedWhenCancelledInFlight(t *testing.T) {
+	server := newFakeRedis(t)
+	cache := server.driver(t)
+	server.setDelay(50 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if err := cache.SetWithContext(ctx, "greeting", "hello"); err != nil {
+		t.Fatalf("expected a write already sent to report its real outcome, got %v", err)
+	}
+	if _, ok := server.value("t:greeting"); !ok {
+		t.Fatal("expected the write to land")
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if count, err := cache.IncrementWithContext(ctx, "hits", 5); err != nil || count != 5 {
+		t.Fatalf("expected the increment to complete, got %d, %v", count, err)
+	}
+}
+
+func TestRedisWritesReportContextErrors(t *testing.T) {
+	server := newFakeRedis(t)
+	cache := server.driver(t)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := cache.SetWithContext(cancelled, "greeting", "hello"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a wrapped context.Canceled, got %v", err)
+	}
+	if _, ok := server.value("t:greeting"); ok {
+		t.Fatal("expected a cancelled write not to be sent")
+	}
+
+	server.setDelay(200 * time.Millisecond)
+	deadline, cancelDeadline := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelDeadline()
+	if _, err := cache.IncrementWithContext(deadline, "hits"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a wrapped context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestRedisPullReturnsValueOnceAndDeletesKey(t *testing.T) {
+	server := newFakeRedis(t)
+	cache := server.driver(t)
+	if err := cache.Set("token", "abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	value, ok, err := cache.Pull("token")
+	if err != nil || !ok || value != "abc" {
+		t.Fatalf("expected abc, got %v, %v, %v", value, ok, err)
+	}
+	if _, ok := server.value("t:token"); ok {
+		t.Fatal("expected the key to be gone after Pull")
+	}
+	if value, ok, err := cache.Pull("token"); err != nil || ok || value != nil {
+		t.Fatalf("expected a missing key to return nil without error, got %v, %v, %v", value, ok, err)
+	}
+}
+
+func TestRedisConcurrentPullsReturnValueOnce(t *testing.T) {
+	server := newFakeRedis(t)
+	cache := server.driver(t)
+
+	for round := 0; round < 20; round++ {
+		if err := cache.Set("token", "abc"); err != nil {
+			t.Fatal(err)
+		}
+
+		var wg sync.WaitGroup
+		var mutex sync.Mutex
+		winners := 0
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, ok, err := cache.Pull("token")
+				if err != nil {
+					t.Error(err)
+				}
+				if ok {
+					mutex.Lock()
+					winners++
+					mutex.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if winners != 1 {
+			t.Fatalf("expected exactly one Pull to get the value, got %d", winners)
+		}
+	}
+}
+
+func TestRedisPullCancelledInFlightKeepsValue(t *testing.T) {
+	server := newFakeRedis(t)
+	cache := server.driver(t)
+	if err := cache.Set("token", "abc"); err != nil {
+		t.Fatal(err)
+	}
+	server.setDelay(50 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	value, ok, err := cache.PullWithContext(ctx, "token")
+	if err != nil || !ok || value != "abc" {
+		t.Fatalf("expected the pulled value despite cancellation, got %v, %v, %v", value, ok, err)
+	}
+}
+
+func TestRedisReadsSkipBackendNearDeadline(t *testing.T) {
+	server := newFakeRedis(t)
+	cache := server.driver(t)
+	if err := cache.Set("greeting", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	cache.SetMinBudget(50 * time.Millisecond)
+	before := server.commandCount()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, _, err := cache.GetWithContext(ctx, "greeting"); !errors.Is(err, ErrInsufficientBudget) {
+		t.Fatalf("expected ErrInsufficientBudget, got %v", err)
+	}
+	if _, err := cache.GetManyWithContext(ctx, []string{"greeting"}); !errors.Is(err, ErrInsufficientBudget) {
+		t.Fatalf("expected ErrInsufficientBudget from GetMany, got %v", err)
+	}
+	if calls := server.commandCount(); calls != before {
+		t.Fatalf("expected the backend never to be called, got %d commands", calls-before)
+	}
+
+	roomy, cancelRoomy := context.WithTimeout(context.Background(), time.Second)
+	defer cancelRoomy()
+	if value, ok, err := cache.GetWithContext(roomy, "greeting"); err != nil || !ok || value != "hello" {
+		t.Fatalf("expected a read with enough budget to reach the backend, got %v, %v, %v", value, ok, err)
+	}
+}
+
+func TestRedisPingReachableServer(t *testing.T) {
+	cache := newFakeRedis(t).driver(t)
+
+	if err := cache.Ping(context.Background()); err != nil {
+		t.Fatalf("expected a reachable server to answer, got %v", err)
+	}
+	if !cache.IsConnected() {
+		t.Fatal("expected IsConnected to report true")
+	}
+}
+
+func TestRedisPingUnreachableServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: addr, MaxRetries: -1})
+	defer client.Close()
+	cache := NewRedisCacheDriver(client, "t:", time.Minute)
+
+	err = cache.Ping(context.Background())
+	if err == nil {
+		t.Fatal("expected an unreachable server to fail the ping")
+	}
+	if !strings.Contains(err.Error(), addr) {
+		t.Fatalf("expected the error to name the address, got %v", err)
+	}
+	if cache.IsConnected() {
+		t.Fatal("expected IsConnected to report false")
+	}
+}
+
+func TestRedisGetStatsCombinesDriverAndServerCounts(t *testing.T) {
+	server := newFakeRedis(t)
+	cache := server.driver(t)
+	other := server.driver(t)
+
+	cache.Set("a", "1")
+	cache.Get("a")
+	cache.Get("a")
+	cache.Get("missing")
+	// Another client's lookups only show up in the server's counts
+	other.Get("missing")
+
+	stats := cache.GetStats()
+	want := map[string]interface{}{
+		"hits":              int64(2),
+		"misses":            int64(1),
+		"errors":            int64(0),
+		"connected":         true,
+		"used_memory_bytes": int64(len("t:a") + len("1")),
+		"server_hits":       int64(2),
+		"server_misses":     int64(2),
+		"evicted_keys":      int64(0),
+		"server_hit_rate":   0.5,
+	}
+	for stat, value := range want {
+		if stats[stat] != value {
+			t.Errorf("expected %s to be %v, got %v", stat, value, stats[stat])
+		}
+	}
+	if rate := stats["hit_rate"].(float64); rate < 0.66 || rate > 0.67 {
+		t.Errorf("expected a hit rate of 2/3, got %v", rate)
+	}
+}
+
+func TestRedisGetStatsWithoutServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: addr, MaxRetries: -1})
+	defer client.Close()
+	cache := NewRedisCacheDriver(client, "t:", time.Minute)
+	cache.Get("a")
+
+	stats := cache.GetStats()
+	if stats["connected"] != false || stats["errors"] != int64(1) || stats["hit_rate"] != 0.0 {
+		t.Fatalf("expected a disconnected driver with one error, got %v", stats)
+	}
+	if _, ok := stats["used_memory_bytes"]; ok {
+		t.Fatalf("expected no server figures without a server, got %v", stats)
+	}
+}
+
+func TestRedisSetManyWithoutExpiryUsesOneMSET(t *testing.T) {
+	server := newFakeRedis(t)
+	cache := server.driver(t)
+	cache.Ping(context.Background())
+	before := server.commandCount()
+
+	values := map[string]interface{}{"a": "1", "b": "2", "c": "3"}
+	if err := cache.SetMany(values, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := server.commandCount() - before; calls != 1 {
+		t.Fatalf("expected a single MSET, got %d commands", calls)
+	}
+	for key, want := range values {
+		if got, ok := server.value("t:" + key); !ok || got != want {
+			t.Fatalf("expected t:%s to be %v, got %q", key, want, got)
+		}
+	}
+}
+
+func TestRedisSetManyWritesNothingWhenTransactionFails(t *testing.T) {
+	server := newFakeRedis(t)
+	cache := server.driver(t)
+	server.dropTransactionsAt(2)
+
+	err := cache.SetMany(map[string]interface{}{"a": "1", "b": "2", "c": "3"}, time.Minute)
+	if err == nil {
+		t.Fatal("expected the dropped transaction to fail")
+	}
+	if stored, _ := server.state(); stored != 0 {
+		t.Fatalf("expected no keys written after a failure mid-write, got %d", stored)
+	}
+
+	server.dropTransactionsAt(0)
+	if err := cache.SetMany(map[string]interface{}{"a": "1", "b": "2", "c": "3"}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := server.state(); stored != 3 {
+		t.Fatalf("expected every key written once the transaction succeeds, got %d", stored)
+	}
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// compressedMarker prefixes gzip-compressed values so reads can tell them
+// apart from values written uncompressed, including those stored before
+// compression was enabled
+const compressedMarker = 0x00
+
+// gzipMagic is the header every gzip stream starts with
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// SetCompressThreshold makes the driver gzip string and byte values of at
+// least threshold bytes before storing them. Other values are stored as
+// before. Zero disables compression; values that were already compressed
+// are still decompressed on read.
+func (d *RedisCacheDriver) SetCompressThreshold(threshold int) {
+	d.compressThreshold = threshold
+}
+
+// encodeValue compresses value when it is large enough and compression
+// actually makes it smaller, returning it unchanged otherwise
+func (d *RedisCacheDriver) encodeValue(value interface{}) interface{} {
+	if d.compressThreshold <= 0 {
+		return value
+	}
+
+	var raw []byte
+	switch v := value.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return value
+	}
+	if len(raw) < d.compressThreshold {
+		return value
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(compressedMarker)
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(raw); err != nil {
+		return value
+	}
+	if err := writer.Close(); err != nil {
+		return value
+	}
+	if buf.Len() >= len(raw) {
+		return value
+	}
+
+	d.compressedWrites.Add(1)
+	d.bytesSaved.Add(int64(len(raw) - buf.Len()))
+	return buf.Bytes()
+}
+
+// decodeValue decompresses a value written by encodeValue, returning any
+// other value unchanged
+func decodeValue(value string) string {
+	if len(value) < 3 || value[0] != compressedMarker || value[1:3] != string(gzipMagic) {
+		return value
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader([]byte(value[1:])))
+	if err != nil {
+		return value
+	}
+	defer reader.Close()
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return value
+	}
+	return string(raw)
+}
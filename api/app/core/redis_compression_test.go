@@ -0,0 +1,82 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedisCompressesLargeValues(t *testing.T) {
+	server := newFakeRedis(t)
+	cache := server.driver(t)
+	cache.SetCompressThreshold(64)
+
+	large := strings.Repeat("compressible ", 100)
+	if err := cache.Set("large", large); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, _ := server.value("t:large")
+	if len(stored) >= len(large) || stored[0] != compressedMarker || stored[1:3] != string(gzipMagic) {
+		t.Fatalf("expected a smaller gzip value behind the marker, got %d bytes", len(stored))
+	}
+	if value, ok := cache.Get("large"); !ok || value != large {
+		t.Fatalf("expected the original value back, got %d bytes", len(value.(string)))
+	}
+	if value, ok, err := cache.Pull("large"); err != nil || !ok || value != large {
+		t.Fatalf("expected Pull to decompress too, got %v, %v", ok, err)
+	}
+
+	stats := cache.GetStats()
+	if stats["compressed_writes"] != int64(1) {
+		t.Errorf("expected one compressed write, got %v", stats["compressed_writes"])
+	}
+	if saved := int64(len(large) - len(stored)); stats["compression_bytes_saved"] != saved {
+		t.Errorf("expected %d bytes saved, got %v", saved, stats["compression_bytes_saved"])
+	}
+}
+
+func TestRedisStoresSmallAndIncompressibleValuesRaw(t *testing.T) {
+	server := newFakeRedis(t)
+	cache := server.driver(t)
+	cache.SetCompressThreshold(64)
+
+	// Below the threshold, and at it but too short for gzip to shrink
+	for key, value := range map[string]string{"small": "short", "dense": "q7Vx2LmP9aRtZ4kWc8NbY3hJf6GsD1eU5oIy0TnBvMwXpQ2rKzHj7lCdA4gFuS9e"} {
+		if err := cache.Set(key, value); err != nil {
+			t.Fatal(err)
+		}
+		if stored, _ := server.value("t:" + key); stored != value {
+			t.Fatalf("expected %s to be stored as is, got %q", key, stored)
+		}
+		if got, ok := cache.Get(key); !ok || got != value {
+			t.Fatalf("expected %q back, got %v", value, got)
+		}
+	}
+
+	if stats := cache.GetStats(); stats["compressed_writes"] != int64(0) {
+		t.Fatalf("expected no compressed writes, got %v", stats["compressed_writes"])
+	}
+}
+
+func TestRedisReadsValuesWrittenWithoutCompression(t *testing.T) {
+	server := newFakeRedis(t)
+	cache := server.driver(t)
+
+	// Written before compression was enabled
+	legacy := strings.Repeat("legacy ", 50)
+	server.store("t:legacy", legacy)
+	cache.SetCompressThreshold(64)
+	if value, ok := cache.Get("legacy"); !ok || value != legacy {
+		t.Fatalf("expected the uncompressed value as is, got %v", value)
+	}
+
+	// Compressed while enabled, then read with compression turned off
+	large := strings.Repeat("compressible ", 100)
+	if err := cache.Set("large", large); err != nil {
+		t.Fatal(err)
+	}
+	cache.SetCompressThreshold(0)
+	if value, ok := cache.Get("large"); !ok || value != large {
+		t.Fatal("expected compressed values to be read after compression is disabled")
+	}
+}
@@ -1,6 +1,11 @@
 package core
 
-// RegisterEvent registers an event listener
-func RegisterEvent(eventName string, handlerFactory func(EventInterface) ListenerInterface) {
-	GlobalRegistry.RegisterListener(eventName, handlerFactory)
+// RegisterEvent registers an event listener, returning a handle to remove it with
+func RegisterEvent(eventName string, handlerFactory func(EventInterface) ListenerInterface) ListenerHandle {
+	return GlobalRegistry.RegisterListener(eventName, handlerFactory)
+}
+
+// RemoveEventListener removes the listener registration the handle was returned for
+func RemoveEventListener(handle ListenerHandle) bool {
+	return GlobalRegistry.RemoveListenerByHandle(handle)
 }
@@ -1,8 +1,25 @@
 package core
 
+import "sync"
+
+// ListenerHandle identifies one registration of a listener so it can be
+// removed later, even when several registrations use identical closures
+type ListenerHandle struct {
+	eventName string
+	id        uint64
+}
+
+// registeredListener is a listener factory together with its handle ID
+type registeredListener struct {
+	id      uint64
+	factory func(EventInterface) ListenerInterface
+}
+
 // EventListenerRegistry holds all registered event listeners
 type EventListenerRegistry struct {
-	listeners map[string][]func(EventInterface) ListenerInterface
+	listeners map[string][]registeredListener
+	nextID    uint64
+	mutex     sync.RWMutex
 }
 
 // Global registry instance
@@ -11,16 +28,57 @@ var GlobalRegistry *EventListenerRegistry
 // InitializeRegistry initializes the global registry
 func InitializeRegistry() {
 	GlobalRegistry = &EventListenerRegistry{
-		listeners: make(map[string][]func(EventInterface) ListenerInterface),
+		listeners: make(map[string][]registeredListener),
 	}
 }
 
-// RegisterListener registers a listener for an event
-func (r *EventListenerRegistry) RegisterListener(eventName string, handlerFactory func(EventInterface) ListenerInterface) {
-	r.listeners[eventName] = append(r.listeners[eventName], handlerFactory)
+// RegisterListener registers a listener for an event, returning a handle
+// that RemoveListenerByHandle accepts
+func (r *EventListenerRegistry) RegisterListener(eventName string, handlerFactory func(EventInterface) ListenerInterface) ListenerHandle {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.nextID++
+	r.listeners[eventName] = append(r.listeners[eventName], registeredListener{id: r.nextID, factory: handlerFactory})
+	return ListenerHandle{eventName: eventName, id: r.nextID}
+}
+
+// RemoveListenerByHandle removes exactly the registration the handle was
+// returned for, reporting whether it was still registered. Go can't compare
+// func values, so handles are the only way to remove a specific closure.
+func (r *EventListenerRegistry) RemoveListenerByHandle(handle ListenerHandle) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	listeners := r.listeners[handle.eventName]
+	for i, listener := range listeners {
+		if listener.id != handle.id {
+			continue
+		}
+
+		// Copy rather than shift in place so slices already handed out by
+		// GetListeners are left untouched
+		remaining := make([]registeredListener, 0, len(listeners)-1)
+		remaining = append(remaining, listeners[:i]...)
+		remaining = append(remaining, listeners[i+1:]...)
+		if len(remaining) == 0 {
+			delete(r.listeners, handle.eventName)
+		} else {
+			r.listeners[handle.eventName] = remaining
+		}
+		return true
+	}
+	return false
 }
 
 // GetListeners returns all listeners for an event
 func (r *EventListenerRegistry) GetListeners(eventName string) []func(EventInterface) ListenerInterface {
-	return r.listeners[eventName]
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	factories := make([]func(EventInterface) ListenerInterface, len(r.listeners[eventName]))
+	for i, listener := range r.listeners[eventName] {
+		factories[i] = listener.factory
+	}
+	return factories
 }
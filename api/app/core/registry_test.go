@@ -0,0 +1,86 @@
+package core
+
+import "testing"
+
+// countingListener records which registration produced it
+type countingListener struct {
+	calls *[]string
+	name  string
+}
+
+func (l countingListener) Handle(mailService interface{}) error {
+	*l.calls = append(*l.calls, l.name)
+	return nil
+}
+
+func newTestRegistry() *EventListenerRegistry {
+	return &EventListenerRegistry{listeners: make(map[string][]registeredListener)}
+}
+
+// handleAll runs every listener registered for eventName
+func handleAll(r *EventListenerRegistry, eventName string) {
+	for _, factory := range r.GetListeners(eventName) {
+		factory(nil).Handle(nil)
+	}
+}
+
+func TestRemoveOneOfTwoIdenticalClosures(t *testing.T) {
+	registry := newTestRegistry()
+	var calls []string
+	listener := func(name string) func(EventInterface) ListenerInterface {
+		return func(EventInterface) ListenerInterface {
+			return countingListener{calls: &calls, name: name}
+		}
+	}
+
+	first := registry.RegisterListener("user.created", listener("first"))
+	second := registry.RegisterListener("user.created", listener("second"))
+
+	if !registry.RemoveListenerByHandle(first) {
+		t.Fatal("expected the first registration to be removed")
+	}
+	handleAll(registry, "user.created")
+	if len(calls) != 1 || calls[0] != "second" {
+		t.Fatalf("expected only the second registration to remain, got %v", calls)
+	}
+
+	if registry.RemoveListenerByHandle(first) {
+		t.Fatal("expected removing the same handle twice to report false")
+	}
+	if !registry.RemoveListenerByHandle(second) {
+		t.Fatal("expected the second registration to be removed")
+	}
+	if listeners := registry.GetListeners("user.created"); len(listeners) != 0 {
+		t.Fatalf("expected no listeners left, got %d", len(listeners))
+	}
+}
+
+func TestRemoveSameFuncRegisteredTwice(t *testing.T) {
+	registry := newTestRegistry()
+	var calls []string
+	factory := func(EventInterface) ListenerInterface {
+		return countingListener{calls: &calls, name: "shared"}
+	}
+
+	handle := registry.RegisterListener("user.created", factory)
+	registry.RegisterListener("user.created", factory)
+
+	registry.RemoveListenerByHandle(handle)
+	handleAll(registry, "user.created")
+	if len(calls) != 1 {
+		t.Fatalf("expected exactly one registration to remain, got %d", len(calls))
+	}
+}
+
+func TestRemovedListenerLeavesEarlierSnapshotsIntact(t *testing.T) {
+	registry := newTestRegistry()
+	factory := func(EventInterface) ListenerInterface { return nil }
+	handle := registry.RegisterListener("user.created", factory)
+	registry.RegisterListener("user.created", factory)
+
+	snapshot := registry.GetListeners("user.created")
+	registry.RemoveListenerByHandle(handle)
+	if len(snapshot) != 2 || snapshot[0] == nil || snapshot[1] == nil {
+		t.Fatal("expected a slice returned before removal to be unchanged")
+	}
+}
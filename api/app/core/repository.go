@@ -1,10 +1,16 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 )
 
+// ErrPagingUnsupported is returned when counting or paging through a
+// repository that can't do it in the database
+var ErrPagingUnsupported = errors.New("repository does not support paging")
+
 // RepositoryInterface defines the interface for repositories
 type RepositoryInterface interface {
 	// Basic CRUD operations
@@ -19,6 +25,23 @@ type RepositoryInterface interface {
 	Get() ([]ModelInterface, error)
 }
 
+// PagingRepository is implemented by repositories that can count and page
+// through their query in the database instead of loading every row
+type PagingRepository interface {
+	// Count counts the models matching the query
+	Count() (int64, error)
+	// Page gets up to limit models matching the query, ordered by ID, skipping offset
+	Page(offset, limit int) ([]ModelInterface, error)
+	// After gets up to limit models matching the query with an ID above afterID, ordered by ID
+	After(afterID uint, limit int) ([]ModelInterface, error)
+}
+
+// ContextRepository is implemented by repositories that can bind their
+// queries to a context, so a cancelled caller cancels the query itself
+type ContextRepository interface {
+	WithContext(ctx context.Context) RepositoryInterface
+}
+
 // Repository provides base repository functionality
 type Repository struct {
 	modelType reflect.Type
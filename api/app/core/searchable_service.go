@@ -0,0 +1,136 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// searchFieldPattern matches the column names Search may interpolate into SQL
+var searchFieldPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// likeEscaper escapes the LIKE wildcards and the escape character itself
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchableService adds substring search across fields to BaseService. When
+// SearchFields is set in the options, only those fields may be searched and
+// they are used when the caller passes none.
+type SearchableService[T any] struct {
+	*BaseService[T]
+}
+
+// NewSearchableService creates a new searchable service
+func NewSearchableService[T any](repository RepositoryInterface, cache CacheInterface, options *ServiceOptions) *SearchableService[T] {
+	return &SearchableService[T]{
+		BaseService: NewBaseService[T](repository, cache).WithOptions(options),
+	}
+}
+
+// Search finds entities where any of the fields contains query
+func (s *SearchableService[T]) Search(query string, fields []string) ([]T, error) {
+	return s.search(s.BaseService, query, fields)
+}
+
+// SearchWithContext finds entities matching query with context
+func (s *SearchableService[T]) SearchWithContext(ctx context.Context, query string, fields []string) ([]T, error) {
+	return readWithContext(ctx, s.BaseService, func(base *BaseService[T]) ([]T, error) { return s.search(base, query, fields) })
+}
+
+// SearchPaginated finds one page of entities matching query along with the total match count
+func (s *SearchableService[T]) SearchPaginated(query string, fields []string, page, perPage int) ([]T, int64, error) {
+	return s.searchPaginated(s.BaseService, query, fields, page, perPage)
+}
+
+// SearchPaginatedWithContext finds one page of entities matching query with context
+func (s *SearchableService[T]) SearchPaginatedWithContext(ctx context.Context, query string, fields []string, page, perPage int) ([]T, int64, error) {
+	type result struct {
+		items []T
+		total int64
+	}
+	r, err := readWithContext(ctx, s.BaseService, func(base *BaseService[T]) (result, error) {
+		items, total, err := s.searchPaginated(base, query, fields, page, perPage)
+		return result{items, total}, err
+	})
+	return r.items, r.total, err
+}
+
+// search runs the LIKE query for query across fields through base
+func (s *SearchableService[T]) search(base *BaseService[T], query string, fields []string) ([]T, error) {
+	repository, err := s.searchRepository(base, query, fields)
+	if err != nil {
+		return nil, err
+	}
+	models, err := repository.Get()
+	if err != nil {
+		return nil, err
+	}
+	return s.castAll(models)
+}
+
+// searchPaginated counts the matches for query and loads one page of them through base
+func (s *SearchableService[T]) searchPaginated(base *BaseService[T], query string, fields []string, page, perPage int) ([]T, int64, error) {
+	if page < 1 || perPage < 1 {
+		return nil, 0, fmt.Errorf("invalid page %d or per page %d", page, perPage)
+	}
+
+	repository, err := s.searchRepository(base, query, fields)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.paginate(repository, page, perPage)
+}
+
+// searchRepository scopes base's repository to the LIKE query for query across fields
+func (s *SearchableService[T]) searchRepository(base *BaseService[T], query string, fields []string) (RepositoryInterface, error) {
+	fields, err := s.searchFields(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	condition, args := BuildSearchCondition(query, fields)
+	return base.repository.Where(condition, args...), nil
+}
+
+// searchFields resolves and validates the fields to search
+func (s *SearchableService[T]) searchFields(fields []string) ([]string, error) {
+	var allowed []string
+	if s.options != nil {
+		allowed = s.options.SearchFields
+	}
+	if len(fields) == 0 {
+		fields = allowed
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields to search %s by", s.typeName())
+	}
+
+	for _, field := range fields {
+		if !searchFieldPattern.MatchString(field) {
+			return nil, fmt.Errorf("invalid search field %q", field)
+		}
+		if len(allowed) > 0 && !slices.Contains(allowed, field) {
+			return nil, fmt.Errorf("%s cannot be searched by %s", s.typeName(), field)
+		}
+	}
+	return fields, nil
+}
+
+// BuildSearchCondition builds a parameterized condition matching rows where
+// any of fields contains query. Wildcards in query are escaped so they match
+// literally. Fields are interpolated as-is and must be trusted column names.
+func BuildSearchCondition(query string, fields []string) (string, []interface{}) {
+	pattern := "%" + likeEscaper.Replace(query) + "%"
+
+	clauses := make([]string, len(fields))
+	args := make([]interface{}, len(fields))
+	for i, field := range fields {
+		clauses[i] = field + ` LIKE ? ESCAPE '\\'`
+		args[i] = pattern
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+
+// Ensure SearchableService satisfies the interface it implements
+var _ SearchableServiceInterface[ModelInterface] = (*SearchableService[ModelInterface])(nil)
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// likeUnescaper reverses likeEscaper
+var likeUnescaper = strings.NewReplacer(`\\`, `\`, `\%`, `%`, `\_`, `_`)
+
+// searchRepository records every search condition it is scoped to and
+// applies it to item names the way the database would apply "name LIKE %...%"
+type searchRepository struct {
+	pagedRepository
+	conditions *[]string
+	args       []interface{}
+}
+
+func (r *searchRepository) Where(query interface{}, args ...interface{}) RepositoryInterface {
+	*r.conditions = append(*r.conditions, query.(string))
+	return &searchRepository{pagedRepository: r.pagedRepository, conditions: r.conditions, args: args}
+}
+
+// matching keeps the rows whose name contains the literal search term
+func (r *searchRepository) matching() []*testItem {
+	term := likeUnescaper.Replace(strings.TrimSuffix(strings.TrimPrefix(r.args[0].(string), "%"), "%"))
+	var rows []*testItem
+	for _, row := range r.memoryRepository.matching() {
+		if strings.Contains(row.Name, term) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func (r *searchRepository) Get() ([]ModelInterface, error) {
+	return toModelInterfaces(r.matching()), nil
+}
+
+func (r *searchRepository) Count() (int64, error) {
+	return int64(len(r.matching())), nil
+}
+
+func (r *searchRepository) Page(offset, limit int) ([]ModelInterface, error) {
+	rows := r.matching()
+	return toModelInterfaces(rows[offset:min(offset+limit, len(rows))]), nil
+}
+
+func newSearchTestService(options *ServiceOptions, names ...string) (*SearchableService[*testItem], *[]string) {
+	repository := &searchRepository{
+		pagedRepository: pagedRepository{memoryRepository{store: newMemoryStore(names...)}},
+		conditions:      &[]string{},
+	}
+	return NewSearchableService[*testItem](repository, nil, options), repository.conditions
+}
+
+// lastCondition returns the condition of the most recent search
+func lastCondition(conditions *[]string) string {
+	return (*conditions)[len(*conditions)-1]
+}
+
+func itemNames(items []*testItem) []string {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+	}
+	return names
+}
+
+func TestBuildSearchConditionEscapesWildcards(t *testing.T) {
+	condition, args := BuildSearchCondition(`50%_off\`, []string{"name", "users.email"})
+
+	want := `(name LIKE ? ESCAPE '\\' OR users.email LIKE ? ESCAPE '\\')`
+	if condition != want {
+		t.Fatalf("expected %s, got %s", want, condition)
+	}
+	pattern := `%50\%\_off\\%`
+	if !reflect.DeepEqual(args, []interface{}{pattern, pattern}) {
+		t.Fatalf("expected one escaped pattern per field, got %v", args)
+	}
+}
+
+func TestSearchMatchesWildcardsLiterally(t *testing.T) {
+	service, conditions := newSearchTestService(nil, "50% off", "500 off", "half_price", "halfprice")
+
+	items, err := service.Search("50%", []string{"name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := itemNames(items); !reflect.DeepEqual(names, []string{"50% off"}) {
+		t.Fatalf("expected only the literal match, got %v", names)
+	}
+	if lastCondition(conditions) != `(name LIKE ? ESCAPE '\\')` {
+		t.Fatalf("expected the search to be scoped by name, got %s", lastCondition(conditions))
+	}
+
+	items, _ = service.Search("f_p", []string{"name"})
+	if names := itemNames(items); !reflect.DeepEqual(names, []string{"half_price"}) {
+		t.Fatalf("expected _ to match only itself, got %v", names)
+	}
+}
+
+func TestSearchPaginatedReturnsTotal(t *testing.T) {
+	service, _ := newSearchTestService(nil, "apple", "grape", "pineapple", "apricot", "snapple")
+
+	cases := []struct {
+		page      int
+		wantNames []string
+	}{
+		{1, []string{"apple", "pineapple"}},
+		{2, []string{"snapple"}},
+		{3, []string{}},
+	}
+	for _, tc := range cases {
+		items, total, err := service.SearchPaginated("apple", []string{"name"}, tc.page, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 3 {
+			t.Fatalf("page %d: expected a total of 3 matches, got %d", tc.page, total)
+		}
+		if names := itemNames(items); !reflect.DeepEqual(names, tc.wantNames) {
+			t.Fatalf("page %d: expected %v, got %v", tc.page, tc.wantNames, names)
+		}
+	}
+
+	if _, _, err := service.SearchPaginated("apple", []string{"name"}, 0, 2); err == nil {
+		t.Fatal("expected page 0 to be rejected")
+	}
+}
+
+func TestSearchFieldsAreRestricted(t *testing.T) {
+	service, conditions := newSearchTestService(&ServiceOptions{SearchFields: []string{"name", "email"}}, "ann")
+
+	// Without fields the configured ones are searched
+	if _, err := service.Search("ann", nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := `(name LIKE ? ESCAPE '\\' OR email LIKE ? ESCAPE '\\')`; lastCondition(conditions) != want {
+		t.Fatalf("expected %s, got %s", want, lastCondition(conditions))
+	}
+
+	for _, fields := range [][]string{{"password"}, {"name; DROP TABLE users"}} {
+		if _, err := service.Search("ann", fields); err == nil {
+			t.Errorf("expected searching by %v to be rejected", fields)
+		}
+	}
+
+	unrestricted, _ := newSearchTestService(nil)
+	if _, err := unrestricted.Search("ann", nil); err == nil {
+		t.Fatal("expected a search without any fields to be rejected")
+	}
+}
@@ -19,6 +19,8 @@ type BaseServiceInterface[T any] interface {
 	AllWithContext(ctx context.Context) ([]T, error)
 	Paginate(page, perPage int) ([]T, int64, error)
 	PaginateWithContext(ctx context.Context, page, perPage int) ([]T, int64, error)
+	PaginateCursor(cursor string, limit int) ([]T, string, error)
+	PaginateCursorWithContext(ctx context.Context, cursor string, limit int) ([]T, string, error)
 
 	// Update operations
 	Update(id uint, data map[string]interface{}) (T, error)
@@ -0,0 +1,28 @@
+package core
+
+import "errors"
+
+// ErrStaleObject is returned when a versioned model was changed by someone
+// else between being read and being updated
+var ErrStaleObject = errors.New("stale object: record was modified concurrently")
+
+// Versioned is implemented by models that use optimistic locking
+type Versioned interface {
+	GetVersion() uint
+	SetVersion(version uint)
+}
+
+// Versioning adds a version column to a model, opting it into optimistic locking
+type Versioning struct {
+	Version uint `gorm:"not null;default:1" json:"version"`
+}
+
+// GetVersion returns the version the model was read at
+func (v *Versioning) GetVersion() uint {
+	return v.Version
+}
+
+// SetVersion sets the model's version
+func (v *Versioning) SetVersion(version uint) {
+	v.Version = version
+}
@@ -1,6 +1,7 @@
 package facades
 
 import (
+	"errors"
 	"fmt"
 	"time"
 )
@@ -20,36 +21,85 @@ type RedisCacheDriver interface {
 	Decrement(key string, value ...int64) (int64, error)
 }
 
+// CacheCounter interface for drivers that support expiring counters
+type CacheCounter interface {
+	IncrementWithTTL(key string, ttl time.Duration) (int64, time.Duration, error)
+}
+
+// CacheBulkWriter interface for drivers that can store several values atomically
+type CacheBulkWriter interface {
+	SetMany(values map[string]interface{}, ttl ...time.Duration) error
+}
+
+// CachePuller interface for drivers that can get and delete a value atomically
+type CachePuller interface {
+	Pull(key string) (interface{}, bool, error)
+}
+
+// CachePatternDeleter interface for drivers that can delete keys by glob pattern
+type CachePatternDeleter interface {
+	DeletePattern(pattern string) (int64, error)
+}
+
+// CacheLocker interface for drivers that support atomic locks
+type CacheLocker interface {
+	Lock(key string, ttl time.Duration) (string, bool, error)
+	Unlock(key string, owner string) error
+}
+
+// ErrLockWaitTimeout is returned when another node holds the compute lock
+// and does not publish a value before the lock expires
+var ErrLockWaitTimeout = errors.New("timed out waiting for cache value computed by lock holder")
+
+// lockPollInterval is how often waiting callers re-check the cache
+const lockPollInterval = 50 * time.Millisecond
+
 // Global cache instance
 var globalCacheInstance CacheInterface
 
 // Cache facade for easy cache operations
-type Cache struct{}
+type Cache struct {
+	// store is the driver the facade uses; nil means the global instance
+	store CacheInterface
+}
+
+// NewCache returns a cache facade bound to store rather than the global
+// instance, e.g. for a second connection
+func NewCache(store CacheInterface) *Cache {
+	return &Cache{store: store}
+}
+
+// driver returns the driver the facade uses
+func (c *Cache) driver() CacheInterface {
+	if c.store != nil {
+		return c.store
+	}
+	return globalCacheInstance
+}
 
 // Get retrieves a value from cache
 func (c *Cache) Get(key string) (interface{}, bool) {
-	// Use global cache instance directly
-	return globalCacheInstance.Get(key)
+	return c.driver().Get(key)
 }
 
 // Set stores a value in cache
 func (c *Cache) Set(key string, value interface{}, ttl ...time.Duration) error {
-	return globalCacheInstance.Set(key, value, ttl...)
+	return c.driver().Set(key, value, ttl...)
 }
 
 // Delete removes a value from cache
 func (c *Cache) Delete(key string) error {
-	return globalCacheInstance.Delete(key)
+	return c.driver().Delete(key)
 }
 
 // Has checks if a key exists in cache
 func (c *Cache) Has(key string) bool {
-	return globalCacheInstance.Has(key)
+	return c.driver().Has(key)
 }
 
 // Flush clears all cache
 func (c *Cache) Flush() error {
-	return globalCacheInstance.Flush()
+	return c.driver().Flush()
 }
 
 // Remember gets a value from cache or stores the result of a callback
@@ -79,8 +129,63 @@ func (c *Cache) RememberForever(key string, callback func() (interface{}, error)
 	return c.Remember(key, 0, callback) // 0 means no expiration
 }
 
-// Pull gets a value from cache and deletes it
+// GetOrSetDistributed gets a value from cache or computes it while holding a
+// distributed lock, so only one node runs the factory. Other callers poll the
+// cache for the result until lockTTL elapses. Drivers without lock support
+// fall back to Remember.
+func (c *Cache) GetOrSetDistributed(key string, factory func() (interface{}, error), ttl, lockTTL time.Duration) (interface{}, error) {
+	if value, exists := c.Get(key); exists {
+		return value, nil
+	}
+
+	locker, ok := c.driver().(CacheLocker)
+	if !ok {
+		return c.Remember(key, ttl, factory)
+	}
+
+	lockKey := "lock:" + key
+	owner, acquired, err := locker.Lock(lockKey, lockTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	if acquired {
+		defer locker.Unlock(lockKey, owner)
+
+		// Another node may have finished between our miss and the lock
+		if value, exists := c.Get(key); exists {
+			return value, nil
+		}
+
+		value, err := factory()
+		if err != nil {
+			return nil, err
+		}
+		if err := c.Set(key, value, ttl); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+
+	deadline := time.Now().Add(lockTTL)
+	for time.Now().Before(deadline) {
+		time.Sleep(lockPollInterval)
+		if value, exists := c.Get(key); exists {
+			return value, nil
+		}
+	}
+
+	return nil, ErrLockWaitTimeout
+}
+
+// Pull gets a value from cache and deletes it. Drivers that support it do
+// this atomically, so a one-time value is only ever handed out once.
 func (c *Cache) Pull(key string) (interface{}, bool) {
+	if puller, ok := c.driver().(CachePuller); ok {
+		value, exists, err := puller.Pull(key)
+		return value, exists && err == nil
+	}
+
 	value, exists := c.Get(key)
 	if exists {
 		c.Delete(key)
@@ -101,7 +206,7 @@ func (c *Cache) Add(key string, value interface{}, ttl ...time.Duration) bool {
 // Increment increments a numeric value in cache
 func (c *Cache) Increment(key string, value ...int64) (int64, error) {
 	// Check if the driver supports increment
-	if redisDriver, ok := globalCacheInstance.(RedisCacheDriver); ok {
+	if redisDriver, ok := c.driver().(RedisCacheDriver); ok {
 		return redisDriver.Increment(key, value...)
 	}
 
@@ -109,10 +214,36 @@ func (c *Cache) Increment(key string, value ...int64) (int64, error) {
 	return 0, fmt.Errorf("increment not supported for this cache driver")
 }
 
+// IncrementWithTTL increments a counter whose expiry is set atomically by the
+// first increment, returning the new count and the time left until it resets
+func (c *Cache) IncrementWithTTL(key string, ttl time.Duration) (int64, time.Duration, error) {
+	if counter, ok := c.driver().(CacheCounter); ok {
+		return counter.IncrementWithTTL(key, ttl)
+	}
+	return 0, 0, fmt.Errorf("expiring counters not supported for this cache driver")
+}
+
+// SetMany stores several values so that either all of them are written or none are
+func (c *Cache) SetMany(values map[string]interface{}, ttl ...time.Duration) error {
+	if writer, ok := c.driver().(CacheBulkWriter); ok {
+		return writer.SetMany(values, ttl...)
+	}
+	return fmt.Errorf("atomic bulk writes not supported for this cache driver")
+}
+
+// DeletePattern removes every key matching a glob pattern, e.g. "users:*",
+// returning how many were removed
+func (c *Cache) DeletePattern(pattern string) (int64, error) {
+	if deleter, ok := c.driver().(CachePatternDeleter); ok {
+		return deleter.DeletePattern(pattern)
+	}
+	return 0, fmt.Errorf("pattern deletes not supported for this cache driver")
+}
+
 // Decrement decrements a numeric value in cache
 func (c *Cache) Decrement(key string, value ...int64) (int64, error) {
 	// Check if the driver supports decrement
-	if redisDriver, ok := globalCacheInstance.(RedisCacheDriver); ok {
+	if redisDriver, ok := c.driver().(RedisCacheDriver); ok {
 		return redisDriver.Decrement(key, value...)
 	}
 
@@ -140,11 +271,21 @@ func Set(key string, value interface{}, ttl ...time.Duration) error {
 	return CacheInstance.Set(key, value, ttl...)
 }
 
+// SetMany stores several values in cache atomically
+func SetMany(values map[string]interface{}, ttl ...time.Duration) error {
+	return CacheInstance.SetMany(values, ttl...)
+}
+
 // Delete removes a value from cache
 func Delete(key string) error {
 	return CacheInstance.Delete(key)
 }
 
+// DeletePattern removes every key matching a glob pattern
+func DeletePattern(pattern string) (int64, error) {
+	return CacheInstance.DeletePattern(pattern)
+}
+
 // Has checks if a key exists in cache
 func Has(key string) bool {
 	return CacheInstance.Has(key)
@@ -165,6 +306,11 @@ func RememberForever(key string, callback func() (interface{}, error)) (interfac
 	return CacheInstance.RememberForever(key, callback)
 }
 
+// GetOrSetDistributed gets a value from cache or computes it under a distributed lock
+func GetOrSetDistributed(key string, factory func() (interface{}, error), ttl, lockTTL time.Duration) (interface{}, error) {
+	return CacheInstance.GetOrSetDistributed(key, factory, ttl, lockTTL)
+}
+
 // Pull gets a value from cache and deletes it
 func Pull(key string) (interface{}, bool) {
 	return CacheInstance.Pull(key)
@@ -180,6 +326,11 @@ func Increment(key string, value ...int64) (int64, error) {
 	return CacheInstance.Increment(key, value...)
 }
 
+// IncrementWithTTL increments a counter that expires ttl after its first increment
+func IncrementWithTTL(key string, ttl time.Duration) (int64, time.Duration, error) {
+	return CacheInstance.IncrementWithTTL(key, ttl)
+}
+
 // Decrement decrements a numeric value in cache
 func Decrement(key string, value ...int64) (int64, error) {
 	return CacheInstance.Decrement(key, value...)
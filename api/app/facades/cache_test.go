@@ -0,0 +1,136 @@
+package facades
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// sharedBackend is an in-memory store shared by several nodes, standing in
+// for the Redis they would all talk to
+type sharedBackend struct {
+	mutex  sync.Mutex
+	values map[string]interface{}
+	locks  map[string]string
+	owners int
+}
+
+func newSharedBackend() *sharedBackend {
+	return &sharedBackend{values: map[string]interface{}{}, locks: map[string]string{}}
+}
+
+// backendNode is one node's cache driver on a sharedBackend
+type backendNode struct {
+	backend *sharedBackend
+}
+
+func (n backendNode) Get(key string) (interface{}, bool) {
+	n.backend.mutex.Lock()
+	defer n.backend.mutex.Unlock()
+	value, exists := n.backend.values[key]
+	return value, exists
+}
+
+func (n backendNode) Set(key string, value interface{}, ttl ...time.Duration) error {
+	n.backend.mutex.Lock()
+	defer n.backend.mutex.Unlock()
+	n.backend.values[key] = value
+	return nil
+}
+
+func (n backendNode) Delete(key string) error {
+	n.backend.mutex.Lock()
+	defer n.backend.mutex.Unlock()
+	delete(n.backend.values, key)
+	return nil
+}
+
+func (n backendNode) Has(key string) bool {
+	_, exists := n.Get(key)
+	return exists
+}
+
+func (n backendNode) Flush() error {
+	n.backend.mutex.Lock()
+	defer n.backend.mutex.Unlock()
+	n.backend.values = map[string]interface{}{}
+	return nil
+}
+
+func (n backendNode) Lock(key string, ttl time.Duration) (string, bool, error) {
+	n.backend.mutex.Lock()
+	defer n.backend.mutex.Unlock()
+	if _, held := n.backend.locks[key]; held {
+		return "", false, nil
+	}
+	n.backend.owners++
+	owner := fmt.Sprint(n.backend.owners)
+	n.backend.locks[key] = owner
+	return owner, true, nil
+}
+
+func (n backendNode) Unlock(key string, owner string) error {
+	n.backend.mutex.Lock()
+	defer n.backend.mutex.Unlock()
+	if n.backend.locks[key] == owner {
+		delete(n.backend.locks, key)
+	}
+	return nil
+}
+
+func TestGetOrSetDistributedRunsFactoryOnOneNode(t *testing.T) {
+	backend := newSharedBackend()
+	nodes := []*Cache{NewCache(backendNode{backend}), NewCache(backendNode{backend})}
+
+	var builds atomic.Int32
+	factory := func() (interface{}, error) {
+		builds.Add(1)
+		// Slow enough that the other node misses the cache and waits
+		time.Sleep(100 * time.Millisecond)
+		return "report", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, len(nodes))
+	errs := make([]error, len(nodes))
+	for i, node := range nodes {
+		wg.Add(1)
+		go func(i int, node *Cache) {
+			defer wg.Done()
+			results[i], errs[i] = node.GetOrSetDistributed("report", factory, time.Minute, time.Second)
+		}(i, node)
+	}
+	wg.Wait()
+
+	if builds.Load() != 1 {
+		t.Fatalf("expected the factory to run on one node, ran %d times", builds.Load())
+	}
+	for i := range nodes {
+		if errs[i] != nil || results[i] != "report" {
+			t.Fatalf("node %d: expected the computed value, got %v, %v", i, results[i], errs[i])
+		}
+	}
+	if len(backend.locks) != 0 {
+		t.Fatalf("expected the compute lock to be released, got %v", backend.locks)
+	}
+}
+
+func TestGetOrSetDistributedTimesOutWhileLockIsHeld(t *testing.T) {
+	backend := newSharedBackend()
+	holder, waiter := backendNode{backend}, NewCache(backendNode{backend})
+
+	if _, acquired, _ := holder.Lock("lock:report", time.Minute); !acquired {
+		t.Fatal("expected to take the lock")
+	}
+
+	_, err := waiter.GetOrSetDistributed("report", func() (interface{}, error) {
+		t.Fatal("expected the waiting node not to run the factory")
+		return nil, nil
+	}, time.Minute, 120*time.Millisecond)
+	if !errors.Is(err, ErrLockWaitTimeout) {
+		t.Fatalf("expected ErrLockWaitTimeout, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package facades
+
+import (
+	"base_lara_go_project/app/core"
+	"time"
+)
+
+// Config retrieves a config value using dot notation (e.g. "app.name")
+func Config(key string, defaultValue ...interface{}) interface{} {
+	return core.Get(key, defaultValue...)
+}
+
+// ConfigString retrieves a config value as a string
+func ConfigString(key string, defaultValue ...string) string {
+	return core.GetString(key, defaultValue...)
+}
+
+// ConfigInt retrieves a config value as an int
+func ConfigInt(key string, defaultValue ...int) int {
+	return core.GetInt(key, defaultValue...)
+}
+
+// ConfigIntRounded retrieves a config value as an int rounded to the nearest integer
+func ConfigIntRounded(key string, defaultValue ...int) int {
+	return core.GetIntRounded(key, defaultValue...)
+}
+
+// ConfigBool retrieves a config value as a bool
+func ConfigBool(key string, defaultValue ...bool) bool {
+	return core.GetBool(key, defaultValue...)
+}
+
+// ConfigFloat retrieves a config value as a float64
+func ConfigFloat(key string, defaultValue ...float64) float64 {
+	return core.GetFloat(key, defaultValue...)
+}
+
+// ConfigStringSlice retrieves a config value as a []string
+func ConfigStringSlice(key string, defaultValue ...[]string) []string {
+	return core.GetStringSlice(key, defaultValue...)
+}
+
+// ConfigDuration retrieves a config value as a time.Duration
+func ConfigDuration(key string, defaultValue ...time.Duration) time.Duration {
+	return core.GetDuration(key, defaultValue...)
+}
+
+// ConfigAll returns a copy of all config with sensitive values redacted
+func ConfigAll() map[string]interface{} {
+	return core.All()
+}
+
+// SetConfig sets a config value using dot notation
+func SetConfig(key string, value interface{}) {
+	core.Set(key, value)
+}
+
+// PushConfig appends values to a config list using dot notation
+func PushConfig(key string, values ...interface{}) {
+	core.Push(key, values...)
+}
+
+// PrependConfig inserts values at the front of a config list using dot notation
+func PrependConfig(key string, values ...interface{}) {
+	core.Prepend(key, values...)
+}
+
+// WatchConfig reloads a file-based config block whenever its file changes
+func WatchConfig(name string) (func(), error) {
+	return core.WatchConfig(name)
+}
+
+// ConfigUnmarshal maps the config sub-tree at key into a typed struct
+func ConfigUnmarshal(key string, target interface{}) error {
+	return core.Unmarshal(key, target)
+}
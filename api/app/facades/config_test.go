@@ -0,0 +1,92 @@
+package facades
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"base_lara_go_project/app/core"
+)
+
+// useTypedConfig registers a block holding values of every stored shape
+func useTypedConfig(t *testing.T) {
+	t.Helper()
+	SetConfig("typed", map[string]interface{}{
+		"ratio":        0.75,
+		"ratio_string": "1.5",
+		"ratio_int":    3,
+		"queues":       []string{"high", "default"},
+		"queues_any":   []interface{}{"high", "low"},
+		"queues_list":  "high, default, low",
+		"timeout":      "30s",
+		"timeout_secs": 45,
+		"timeout_num":  float64(12),
+		"bad_value":    "soon",
+	})
+	t.Cleanup(func() {
+		SetConfig("typed", map[string]interface{}{})
+		core.ClearCache()
+	})
+}
+
+func TestConfigFloat(t *testing.T) {
+	useTypedConfig(t)
+
+	cases := map[string]float64{"typed.ratio": 0.75, "typed.ratio_string": 1.5, "typed.ratio_int": 3}
+	for key, want := range cases {
+		if got := ConfigFloat(key); got != want {
+			t.Errorf("%s: expected %v, got %v", key, want, got)
+		}
+	}
+	if got := ConfigFloat("typed.bad_value", 2.5); got != 2.5 {
+		t.Errorf("expected the default for an unparsable value, got %v", got)
+	}
+	if got := ConfigFloat("typed.missing", 2.5); got != 2.5 {
+		t.Errorf("expected the default for a missing key, got %v", got)
+	}
+	if got := ConfigFloat("typed.missing"); got != 0 {
+		t.Errorf("expected 0 without a default, got %v", got)
+	}
+}
+
+func TestConfigStringSlice(t *testing.T) {
+	useTypedConfig(t)
+
+	cases := map[string][]string{
+		"typed.queues":      {"high", "default"},
+		"typed.queues_any":  {"high", "low"},
+		"typed.queues_list": {"high", "default", "low"},
+	}
+	for key, want := range cases {
+		if got := ConfigStringSlice(key); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %v, got %v", key, want, got)
+		}
+	}
+	if got := ConfigStringSlice("typed.missing", []string{"default"}); !reflect.DeepEqual(got, []string{"default"}) {
+		t.Errorf("expected the default for a missing key, got %v", got)
+	}
+	if got := ConfigStringSlice("typed.missing"); got != nil {
+		t.Errorf("expected nil without a default, got %v", got)
+	}
+}
+
+func TestConfigDuration(t *testing.T) {
+	useTypedConfig(t)
+
+	cases := map[string]time.Duration{
+		"typed.timeout":      30 * time.Second,
+		"typed.timeout_secs": 45 * time.Second,
+		"typed.timeout_num":  12 * time.Second,
+	}
+	for key, want := range cases {
+		if got := ConfigDuration(key); got != want {
+			t.Errorf("%s: expected %v, got %v", key, want, got)
+		}
+	}
+	if got := ConfigDuration("typed.bad_value", time.Minute); got != time.Minute {
+		t.Errorf("expected the default for an unparsable value, got %v", got)
+	}
+	if got := ConfigDuration("typed.missing", time.Minute); got != time.Minute {
+		t.Errorf("expected the default for a missing key, got %v", got)
+	}
+}
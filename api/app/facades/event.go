@@ -2,6 +2,7 @@ package facades
 
 import (
 	"base_lara_go_project/app/core"
+	"time"
 )
 
 // EventDispatcher defines the interface for dispatching events
@@ -50,3 +51,20 @@ func DispatchEvent(event core.EventInterface) error {
 func DispatchEventSync(event core.EventInterface) error {
 	return EventDispatcherInstance.DispatchSync(event)
 }
+
+// EventIdempotent dispatches an event synchronously unless the same event ID
+// was already dispatched within window, reporting whether it ran
+func EventIdempotent(event core.IdentifiableEvent, window time.Duration) (bool, error) {
+	return core.DispatchEventIdempotent(event, window)
+}
+
+// Listen registers a listener for an event, returning a handle to remove it with
+func Listen(eventName string, handlerFactory func(core.EventInterface) core.ListenerInterface) core.ListenerHandle {
+	return core.RegisterEvent(eventName, handlerFactory)
+}
+
+// RemoveListenerByHandle removes exactly the registration the handle was
+// returned for, e.g. a per-request listener once the request is done
+func RemoveListenerByHandle(handle core.ListenerHandle) bool {
+	return core.RemoveEventListener(handle)
+}
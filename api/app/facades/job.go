@@ -36,3 +36,8 @@ func DispatchJobAsync(job interface{}) error {
 	queueName := queues["jobs"].(string)
 	return core.DispatchJob(job, queueName)
 }
+
+// Chain dispatches jobs that each run only after the previous one succeeded (like Laravel's Bus::chain())
+func Chain(links []core.ChainLink) error {
+	return core.DispatchChain(links)
+}
@@ -0,0 +1,41 @@
+package facades
+
+import (
+	"base_lara_go_project/app/core"
+	"context"
+)
+
+// Log returns the global logger
+func Log() *core.Logger {
+	return core.LoggerInstance
+}
+
+// LogWithFields returns a logger that adds fields to every entry
+func LogWithFields(fields map[string]interface{}) *core.Logger {
+	return core.LoggerInstance.WithFields(fields)
+}
+
+// LogWithContext returns a logger carrying the values stored in ctx
+func LogWithContext(ctx context.Context) *core.Logger {
+	return core.LoggerInstance.WithContext(ctx)
+}
+
+// LogDebug logs a debug entry
+func LogDebug(message string, context ...map[string]interface{}) {
+	core.LoggerInstance.Debug(message, context...)
+}
+
+// LogInfo logs an info entry
+func LogInfo(message string, context ...map[string]interface{}) {
+	core.LoggerInstance.Info(message, context...)
+}
+
+// LogWarning logs a warning entry
+func LogWarning(message string, context ...map[string]interface{}) {
+	core.LoggerInstance.Warning(message, context...)
+}
+
+// LogError logs an error entry
+func LogError(message string, context ...map[string]interface{}) {
+	core.LoggerInstance.Error(message, context...)
+}
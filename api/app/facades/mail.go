@@ -10,6 +10,11 @@ func Mail(to []string, subject, body string) error {
 	return core.SendMail(to, subject, body)
 }
 
+// MailWithAttachments sends an email with attachments synchronously
+func MailWithAttachments(to []string, subject, body string, attachments []core.MailAttachment) error {
+	return core.SendMailWithAttachments(to, subject, body, attachments)
+}
+
 // MailAsync sends an email asynchronously via the mail queue from config
 func MailAsync(to []string, subject, body string) error {
 	queueConfig := config.QueueConfig()
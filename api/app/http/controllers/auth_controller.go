@@ -7,6 +7,8 @@ import (
 	"base_lara_go_project/app/facades"
 	"base_lara_go_project/app/http/requests"
 	"base_lara_go_project/app/utils/token"
+	"base_lara_go_project/app/validators"
+	"errors"
 	"net/http"
 
 	db "base_lara_go_project/app/models/db"
@@ -19,7 +21,7 @@ func Register(c *gin.Context) {
 	var input requests.RegisterRequest
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -52,7 +54,7 @@ func Login(c *gin.Context) {
 	var input requests.LoginRequest
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -126,3 +128,20 @@ func TestEmailTemplate(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Test email sent successfully to " + testUser.Email})
 }
+
+// respondBindError renders a request binding failure: the failed rules for
+// invalid input, or a server error when a rule couldn't be checked
+func respondBindError(c *gin.Context, err error) {
+	if validationErrors, ok := validators.AsValidationErrors(err); ok {
+		c.JSON(validationErrors.StatusCode(), gin.H{"errors": validationErrors.ToMap()})
+		return
+	}
+
+	var ruleErr *validators.RuleError
+	if errors.As(err, &ruleErr) {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate request"})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"base_lara_go_project/app/core"
+	"base_lara_go_project/app/validators"
+
+	"github.com/gin-gonic/gin"
+)
+
+// postLogin sends body to the Login handler
+func postLogin(t *testing.T, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/login", Login)
+
+	request := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	return response
+}
+
+func TestLoginValidationFailureIsBadRequest(t *testing.T) {
+	core.Set("app", map[string]interface{}{})
+
+	response := postLogin(t, `{"email":"not-an-email"}`)
+	if response.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", response.Code)
+	}
+
+	var payload struct {
+		Errors map[string][]string `json:"errors"`
+	}
+	if err := json.Unmarshal(response.Body.Bytes(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	// Keys are json names only once RegisterFormFieldValidators has run
+	if len(payload.Errors["Email"])+len(payload.Errors["email"]) == 0 {
+		t.Fatalf("expected an email error, got %v", payload.Errors)
+	}
+}
+
+func TestLoginValidationFailureIsUnprocessableWhenOptedIn(t *testing.T) {
+	core.Set("app", map[string]interface{}{"validation_unprocessable": "true"})
+	defer core.Set("app", map[string]interface{}{})
+
+	if response := postLogin(t, `{"email":"not-an-email"}`); response.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", response.Code)
+	}
+}
+
+func TestLoginMalformedJSONIsBadRequest(t *testing.T) {
+	core.Set("app", map[string]interface{}{"validation_unprocessable": "true"})
+	defer core.Set("app", map[string]interface{}{})
+
+	if response := postLogin(t, `{"email":`); response.Code != http.StatusBadRequest {
+		t.Fatalf("expected malformed JSON to stay 400, got %d", response.Code)
+	}
+}
+
+func TestRuleErrorIsServerError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	response := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(response)
+
+	respondBindError(c, &validators.RuleError{Rule: "unique", Err: errors.New("connection refused")})
+	if response.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", response.Code)
+	}
+	if strings.Contains(response.Body.String(), "taken") {
+		t.Fatalf("a database failure must not be reported as a taken value, got %s", response.Body.String())
+	}
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"base_lara_go_project/app/core"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// healthCheckTimeout bounds how long each component check may take
+const healthCheckTimeout = 2 * time.Second
+
+// Health answers 200 when every critical component is up and 503 otherwise.
+// It is public, so only the overall status is shown; HealthDetails reports
+// each component.
+func Health(c *gin.Context) {
+	report := core.HealthInstance.Check(c.Request.Context(), healthCheckTimeout)
+	c.JSON(healthStatusCode(report), gin.H{"status": report.Status})
+}
+
+// HealthDetails reports the status, latency and error of every registered
+// component. Errors can name internal addresses, so the route must sit
+// behind authentication.
+func HealthDetails(c *gin.Context) {
+	report := core.HealthInstance.Check(c.Request.Context(), healthCheckTimeout)
+	c.JSON(healthStatusCode(report), report)
+}
+
+// Ready reports whether the app should receive traffic: it is healthy and
+// not shutting down
+func Ready(c *gin.Context) {
+	if core.IsShuttingDown() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": core.HealthStatusDown})
+		return
+	}
+	Health(c)
+}
+
+// healthStatusCode answers 503 when a critical component is down
+func healthStatusCode(report core.HealthReport) int {
+	if !report.Healthy() {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"base_lara_go_project/app/core"
+
+	"github.com/gin-gonic/gin"
+)
+
+// withHealthChecks swaps in a health registry holding a healthy cache and a
+// database whose check returns dbErr
+func withHealthChecks(t *testing.T, dbErr error) {
+	t.Helper()
+	previous := core.HealthInstance
+	core.HealthInstance = core.NewHealthRegistry()
+	t.Cleanup(func() { core.HealthInstance = previous })
+
+	core.RegisterHealthCheck("cache", false, func(ctx context.Context) error { return nil })
+	core.RegisterHealthCheck("database", true, func(ctx context.Context) error { return dbErr })
+}
+
+func serveHealth(handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", handler)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/", nil))
+	return response
+}
+
+func TestHealthShowsOnlyStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		dbErr  error
+		status int
+		body   string
+	}{
+		{"healthy", nil, http.StatusOK, `{"status":"up"}`},
+		{"critical component down", errors.New("dial tcp 10.0.0.5:3306: connection refused"), http.StatusServiceUnavailable, `{"status":"down"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			withHealthChecks(t, test.dbErr)
+			for _, handler := range []gin.HandlerFunc{Health, Ready} {
+				response := serveHealth(handler)
+				if response.Code != test.status || response.Body.String() != test.body {
+					t.Fatalf("expected %d %s, got %d %s", test.status, test.body, response.Code, response.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestHealthDetailsReportsComponents(t *testing.T) {
+	withHealthChecks(t, errors.New("connection refused"))
+
+	response := serveHealth(HealthDetails)
+	if response.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", response.Code)
+	}
+
+	var report core.HealthReport
+	if err := json.Unmarshal(response.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Components["database"].Error != "connection refused" || report.Components["cache"].Status != core.HealthStatusUp {
+		t.Fatalf("expected per-component results, got %+v", report)
+	}
+}
@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"base_lara_go_project/app/core"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics exposes registered component stats in Prometheus text format
+func Metrics(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := core.MetricsInstance.WritePrometheus(c.Request.Context(), c.Writer); err != nil {
+		c.Error(err)
+	}
+}
@@ -0,0 +1,74 @@
+package middlewares
+
+import (
+	"base_lara_go_project/app/core"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// CORS handles preflight requests and sets the Access-Control-* headers from
+// the cors config. Origins match exactly or by wildcard, e.g.
+// https://*.example.com, and "*" allows any origin. Browsers reject "*" on
+// credentialed requests, so with credentials enabled "*" only works when
+// cors.echo_origin is set and the request's own origin is sent back.
+//
+// Blank entries, e.g. from a trailing comma, are ignored. If no usable
+// origin is left the middleware logs why and adds no CORS headers, so only
+// same-origin browser requests succeed.
+func CORS() gin.HandlerFunc {
+	origins := allowedOrigins()
+	if len(origins) == 0 {
+		log.Println("Warning: cors.allowed_origins is empty; CORS is disabled and only same-origin requests will work")
+		return sameOriginOnly
+	}
+	credentials := core.GetBool("cors.allow_credentials")
+
+	corsConfig := cors.Config{
+		AllowMethods:     core.GetStringSlice("cors.allowed_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}),
+		AllowHeaders:     core.GetStringSlice("cors.allowed_headers", []string{"Origin", "Content-Type", "Accept", "Authorization"}),
+		ExposeHeaders:    core.GetStringSlice("cors.exposed_headers"),
+		AllowCredentials: credentials,
+		MaxAge:           core.GetDuration("cors.max_age", 12*time.Hour),
+	}
+
+	switch {
+	case slices.Contains(origins, "*") && credentials && core.GetBool("cors.echo_origin"):
+		corsConfig.AllowOriginFunc = func(origin string) bool { return true }
+	case slices.Contains(origins, "*"):
+		if credentials {
+			log.Println("Warning: CORS allows any origin with credentials; browsers will reject credentialed requests unless cors.echo_origin is set")
+		}
+		corsConfig.AllowAllOrigins = true
+	default:
+		corsConfig.AllowOrigins = origins
+		corsConfig.AllowWildcard = true
+	}
+
+	// cors.New panics on an invalid config, e.g. an origin without a scheme
+	if err := corsConfig.Validate(); err != nil {
+		log.Printf("Warning: invalid CORS config, CORS is disabled: %v", err)
+		return sameOriginOnly
+	}
+	return cors.New(corsConfig)
+}
+
+// allowedOrigins returns the configured origins, trimmed and without blanks
+func allowedOrigins() []string {
+	var origins []string
+	for _, origin := range core.GetStringSlice("cors.allowed_origins") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// sameOriginOnly passes requests through without any CORS headers
+func sameOriginOnly(c *gin.Context) {
+	c.Next()
+}
@@ -0,0 +1,105 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"base_lara_go_project/app/core"
+
+	"github.com/gin-gonic/gin"
+)
+
+// corsRouter builds a router using CORS with the given cors config block
+func corsRouter(t *testing.T, corsConfig map[string]interface{}) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	core.Set("cors", corsConfig)
+
+	router := gin.New()
+	router.Use(CORS())
+	router.GET("/resource", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+	return router
+}
+
+func TestCORSPreflightFromAllowedOrigin(t *testing.T) {
+	router := corsRouter(t, map[string]interface{}{
+		"allowed_origins":   "https://app.example.com, https://*.example.org",
+		"allowed_methods":   "GET,POST",
+		"allowed_headers":   "Content-Type,Authorization",
+		"allow_credentials": "true",
+		"max_age":           "600",
+	})
+
+	request := httptest.NewRequest(http.MethodOptions, "/resource", nil)
+	request.Header.Set("Origin", "https://admin.example.org")
+	request.Header.Set("Access-Control-Request-Method", "POST")
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	if response.Code != http.StatusNoContent {
+		t.Fatalf("expected preflight to answer 204, got %d", response.Code)
+	}
+	headers := response.Header()
+	if got := headers.Get("Access-Control-Allow-Origin"); got != "https://admin.example.org" {
+		t.Errorf("expected the wildcard-matched origin to be allowed, got %q", got)
+	}
+	if got := headers.Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("expected credentials to be allowed, got %q", got)
+	}
+	if got := headers.Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("expected max age 600, got %q", got)
+	}
+}
+
+func TestCORSRejectsDisallowedOrigin(t *testing.T) {
+	router := corsRouter(t, map[string]interface{}{
+		"allowed_origins": "https://app.example.com",
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/resource", nil)
+	request.Header.Set("Origin", "https://evil.example.net")
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	if response.Code != http.StatusForbidden {
+		t.Fatalf("expected a disallowed origin to get 403, got %d", response.Code)
+	}
+	if got := response.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected no allow-origin header, got %q", got)
+	}
+}
+
+func TestCORSIgnoresBlankOrigins(t *testing.T) {
+	router := corsRouter(t, map[string]interface{}{
+		"allowed_origins": "https://app.example.com,",
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/resource", nil)
+	request.Header.Set("Origin", "https://app.example.com")
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	if got := response.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Fatalf("expected the listed origin to be allowed, got %q", got)
+	}
+}
+
+func TestCORSFallsBackToSameOriginWhenUnconfigured(t *testing.T) {
+	for name, origins := range map[string]string{"empty": "", "only commas": " , ,", "no scheme": "app.example.com"} {
+		t.Run(name, func(t *testing.T) {
+			router := corsRouter(t, map[string]interface{}{"allowed_origins": origins})
+
+			request := httptest.NewRequest(http.MethodGet, "/resource", nil)
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, request)
+
+			if response.Code != http.StatusOK {
+				t.Fatalf("expected same-origin requests to pass, got %d", response.Code)
+			}
+			if got := response.Header().Get("Access-Control-Allow-Origin"); got != "" {
+				t.Fatalf("expected no CORS headers, got %q", got)
+			}
+		})
+	}
+}
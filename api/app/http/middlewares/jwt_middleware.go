@@ -1,20 +1,46 @@
 package middlewares
 
 import (
+	"base_lara_go_project/app/core"
 	"base_lara_go_project/app/utils/token"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 )
 
+// JwtAuthMiddleware rejects requests without a valid, unexpired bearer token
+// and exposes its user ID and claims to the rest of the request
 func JwtAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		err := token.IsTokenValid(c)
+		claims, err := token.ParseToken(token.ExtractToken(c))
 		if err != nil {
 			c.String(http.StatusUnauthorized, "Unauthorized")
 			c.Abort()
 			return
 		}
+		userID, err := token.UserIDFromClaims(claims)
+		if err != nil {
+			c.String(http.StatusUnauthorized, "Unauthorized")
+			c.Abort()
+			return
+		}
+
+		// Expose the user to services through the request context so writes
+		// made on their behalf can be attributed in the audit log
+		c.Set("claims", claims)
+		c.Set("user_id", userID)
+		c.Request = c.Request.WithContext(core.WithUserID(c.Request.Context(), userID))
 		c.Next()
 	}
 }
+
+// ClaimsFromContext returns the token claims stored by JwtAuthMiddleware
+func ClaimsFromContext(c *gin.Context) (jwt.MapClaims, bool) {
+	claims, ok := c.Get("claims")
+	if !ok {
+		return nil, false
+	}
+	mapClaims, ok := claims.(jwt.MapClaims)
+	return mapClaims, ok
+}
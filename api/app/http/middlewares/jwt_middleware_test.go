@@ -0,0 +1,73 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"base_lara_go_project/app/core"
+	"base_lara_go_project/app/utils/token"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// authRouter serves the user ID the middleware resolved at /me
+func authRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/me", JwtAuthMiddleware(), func(c *gin.Context) {
+		userID, _ := core.UserIDFromContext(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"user_id": userID})
+	})
+	return router
+}
+
+func TestJwtAuthMiddleware(t *testing.T) {
+	t.Setenv("API_SECRET", "test-secret")
+	t.Setenv("TOKEN_HOUR_LIFESPAN", "1")
+
+	valid, err := token.GenerateToken(7, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": 7, "exp": time.Now().Add(-time.Minute).Unix(),
+	}).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		header string
+		status int
+	}{
+		{"valid", "/me", "Bearer " + valid, http.StatusOK},
+		{"expired", "/me", "Bearer " + expired, http.StatusUnauthorized},
+		{"tampered", "/me", "Bearer " + valid[:len(valid)-2] + "xx", http.StatusUnauthorized},
+		{"missing", "/me", "", http.StatusUnauthorized},
+		{"query string", "/me?token=" + valid, "", http.StatusUnauthorized},
+	}
+
+	router := authRouter()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, test.target, nil)
+			if test.header != "" {
+				request.Header.Set("Authorization", test.header)
+			}
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, request)
+
+			if response.Code != test.status {
+				t.Fatalf("expected %d, got %d", test.status, response.Code)
+			}
+			if test.status == http.StatusOK && response.Body.String() != `{"user_id":7}` {
+				t.Fatalf("expected the user ID in the request context, got %s", response.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package middlewares
+
+import (
+	"base_lara_go_project/app/core"
+	"base_lara_go_project/app/utils/token"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireMonitoringToken only lets through requests bearing the token set
+// as monitoring.token, guarding endpoints such as /metrics that expose
+// internal addresses and stats. Every request is refused while no token is
+// configured.
+func RequireMonitoringToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		expected := core.GetString("monitoring.token")
+		presented := token.ExtractToken(c)
+		if expected == "" || subtle.ConstantTimeCompare([]byte(presented), []byte(expected)) != 1 {
+			c.String(http.StatusUnauthorized, "Unauthorized")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
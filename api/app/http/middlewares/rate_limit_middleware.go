@@ -0,0 +1,79 @@
+package middlewares
+
+import (
+	"base_lara_go_project/app/core"
+	"base_lara_go_project/app/facades"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit allows at most maxPerWindow requests per key in each window and
+// answers the rest with 429. Counts live in the configured cache, so with the
+// Redis driver the limit is shared by every instance. keyFunc defaults to
+// RateLimitByIP. It panics if the cache is already booted and its driver
+// can't count; requests are let through, and logged, if counting fails later.
+func RateLimit(maxPerWindow int, window time.Duration, keyFunc func(*gin.Context) string) gin.HandlerFunc {
+	if keyFunc == nil {
+		keyFunc = RateLimitByIP
+	}
+	if core.CacheInstance != nil {
+		if _, ok := core.CacheInstance.(facades.CacheCounter); !ok {
+			panic(fmt.Sprintf("rate limiting needs a cache driver with expiring counters, got %T", core.CacheInstance))
+		}
+	}
+
+	var warnUnsupported sync.Once
+	return func(c *gin.Context) {
+		counter, ok := core.CacheInstance.(facades.CacheCounter)
+		if !ok {
+			warnUnsupported.Do(func() {
+				log.Printf("Rate limiting is disabled: cache driver %T can't count requests", core.CacheInstance)
+			})
+			c.Next()
+			return
+		}
+
+		key := fmt.Sprintf("rate_limit:%s", keyFunc(c))
+		count, resetIn, err := counter.IncrementWithTTL(key, window)
+		if err != nil {
+			log.Printf("Rate limiter failed to count %s: %v", key, err)
+			c.Next()
+			return
+		}
+
+		remaining := maxPerWindow - int(count)
+		if remaining < 0 {
+			remaining = 0
+		}
+		c.Header("X-RateLimit-Limit", strconv.Itoa(maxPerWindow))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+
+		if count > int64(maxPerWindow) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(resetIn.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RateLimitByIP keys rate limits by the client IP
+func RateLimitByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimitByUser keys rate limits by the authenticated user, falling back to
+// the client IP; it must run after JwtAuthMiddleware to see the user
+func RateLimitByUser(c *gin.Context) string {
+	if userID, ok := c.Get("user_id"); ok {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return RateLimitByIP(c)
+}
@@ -0,0 +1,114 @@
+package middlewares
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"base_lara_go_project/app/core"
+
+	"github.com/gin-gonic/gin"
+)
+
+// plainCache is a cache driver without expiring counters
+type plainCache struct {
+	core.CacheInterface
+}
+
+// withCache swaps the global cache for the duration of a test
+func withCache(t *testing.T, cache core.CacheInterface) {
+	t.Helper()
+	previous := core.CacheInstance
+	core.CacheInstance = cache
+	t.Cleanup(func() { core.CacheInstance = previous })
+}
+
+func rateLimitedRouter(maxPerWindow int, window time.Duration) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RateLimit(maxPerWindow, window, nil))
+	router.GET("/resource", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+	return router
+}
+
+func getResource(router *gin.Engine) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, "/resource", nil)
+	request.RemoteAddr = "203.0.113.7:4000"
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	return response
+}
+
+func TestRateLimitRejectsRequestsOverTheLimit(t *testing.T) {
+	withCache(t, core.NewArrayCacheDriver("test:", time.Minute))
+	router := rateLimitedRouter(2, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if response := getResource(router); response.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i+1, response.Code)
+		}
+	}
+
+	response := getResource(router)
+	if response.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the third request to get 429, got %d", response.Code)
+	}
+	if got := response.Header().Get("X-RateLimit-Remaining"); got != "0" {
+		t.Fatalf("expected no requests remaining, got %q", got)
+	}
+	if got := response.Header().Get("Retry-After"); got != "60" {
+		t.Fatalf("expected Retry-After 60, got %q", got)
+	}
+}
+
+func TestRateLimitResetsAfterWindow(t *testing.T) {
+	withCache(t, core.NewArrayCacheDriver("test:", time.Minute))
+	router := rateLimitedRouter(1, 50*time.Millisecond)
+
+	getResource(router)
+	if response := getResource(router); response.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 within the window, got %d", response.Code)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if response := getResource(router); response.Code != http.StatusOK {
+		t.Fatalf("expected the limit to reset after the window, got %d", response.Code)
+	}
+}
+
+func TestRateLimitRefusesDriverWithoutCounters(t *testing.T) {
+	withCache(t, plainCache{core.NewArrayCacheDriver("test:", time.Minute)})
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected RateLimit to panic for a driver that can't count")
+		}
+	}()
+	RateLimit(1, time.Minute, nil)
+}
+
+func TestRateLimitLogsWhenCacheCantCount(t *testing.T) {
+	withCache(t, nil)
+	router := rateLimitedRouter(1, time.Minute)
+
+	// The cache is booted after the routes, with a driver that can't count
+	core.CacheInstance = plainCache{core.NewArrayCacheDriver("test:", time.Minute)}
+
+	var output bytes.Buffer
+	log.SetOutput(&output)
+	defer log.SetOutput(os.Stderr)
+
+	for i := 0; i < 3; i++ {
+		if response := getResource(router); response.Code != http.StatusOK {
+			t.Fatalf("expected requests to be let through, got %d", response.Code)
+		}
+	}
+	if got := strings.Count(output.String(), "Rate limiting is disabled"); got != 1 {
+		t.Fatalf("expected the disabled limiter to be logged once, got %d in %q", got, output.String())
+	}
+}
@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"base_lara_go_project/app/core"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader is the header request IDs are read from and echoed in
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client supplied IDs so they can't bloat logs
+const maxRequestIDLength = 128
+
+// RequestID tags each request with the incoming X-Request-ID, or a new UUID
+// when there is none, stores it in the request context so loggers created
+// with WithContext include it, and echoes it in the response
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = newRequestID()
+		}
+
+		c.Set("request_id", requestID)
+		c.Request = c.Request.WithContext(core.WithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// newRequestID generates a random version 4 UUID
+func newRequestID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	buf[6] = (buf[6] & 0x0f) | 0x40
+	buf[8] = (buf[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", buf[0:4], buf[4:6], buf[6:8], buf[8:10], buf[10:])
+}
@@ -0,0 +1,92 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"base_lara_go_project/app/core"
+
+	"github.com/gin-gonic/gin"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+// loggingRouter logs one entry through a context logger on every request
+func loggingRouter(t *testing.T) (*gin.Engine, *bytes.Buffer) {
+	t.Helper()
+	var output bytes.Buffer
+	logger := core.NewLogger(&output, core.LogLevelDebug, true)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/orders", func(c *gin.Context) {
+		logger.WithContext(c.Request.Context()).Info("listing orders")
+		c.Status(http.StatusNoContent)
+	})
+	return router, &output
+}
+
+// loggedRequestID returns the request_id of the single logged entry
+func loggedRequestID(t *testing.T, output *bytes.Buffer) interface{} {
+	t.Helper()
+	var entry struct {
+		Context map[string]interface{} `json:"context"`
+	}
+	if err := json.Unmarshal(output.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log entry %q: %v", output.String(), err)
+	}
+	return entry.Context["request_id"]
+}
+
+func TestRequestIDIsGeneratedAndLogged(t *testing.T) {
+	router, output := loggingRouter(t)
+
+	response := serve(router, "/orders")
+
+	requestID := response.Header().Get(RequestIDHeader)
+	if !uuidPattern.MatchString(requestID) {
+		t.Fatalf("expected a generated UUID in the response, got %q", requestID)
+	}
+	if logged := loggedRequestID(t, output); logged != requestID {
+		t.Fatalf("expected the log entry to carry %q, got %v", requestID, logged)
+	}
+
+	if next := serve(router, "/orders").Header().Get(RequestIDHeader); next == requestID {
+		t.Fatal("expected every request to get its own ID")
+	}
+}
+
+func TestRequestIDKeepsIncomingHeader(t *testing.T) {
+	router, output := loggingRouter(t)
+
+	request := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	request.Header.Set(RequestIDHeader, "edge-1234")
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	if got := response.Header().Get(RequestIDHeader); got != "edge-1234" {
+		t.Fatalf("expected the incoming ID to be echoed, got %q", got)
+	}
+	if logged := loggedRequestID(t, output); logged != "edge-1234" {
+		t.Fatalf("expected the log entry to carry the incoming ID, got %v", logged)
+	}
+}
+
+func TestRequestIDReplacesOversizedHeader(t *testing.T) {
+	router, _ := loggingRouter(t)
+
+	request := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	request.Header.Set(RequestIDHeader, strings.Repeat("x", maxRequestIDLength+1))
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	if got := response.Header().Get(RequestIDHeader); !uuidPattern.MatchString(got) {
+		t.Fatalf("expected an oversized ID to be replaced with a UUID, got %q", got)
+	}
+}
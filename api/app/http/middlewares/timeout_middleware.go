@@ -0,0 +1,189 @@
+package middlewares
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout gives each request a deadline of d. The handler chain runs with a
+// request context that is cancelled at the deadline, and its response is
+// buffered; if it has not finished by then the client gets a 503 instead
+// and anything the handler writes afterwards is discarded. Handlers should
+// honour c.Request.Context(), since the middleware still waits for them to
+// return before giving the gin context back.
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return TimeoutWithOverrides(d, nil)
+}
+
+// TimeoutWithOverrides is Timeout with per-route deadlines keyed by route
+// pattern as returned by c.FullPath(), e.g. "/api/v1/reports/:id". A zero
+// or negative duration disables the timeout for that route.
+func TimeoutWithOverrides(d time.Duration, overrides map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := d
+		if override, ok := overrides[c.FullPath()]; ok {
+			timeout = override
+		}
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		original := c.Writer
+		writer := &timeoutWriter{ResponseWriter: original, header: make(http.Header)}
+		c.Writer = writer
+
+		done := make(chan struct{})
+		var panicked interface{}
+		go func() {
+			defer close(done)
+			defer func() { panicked = recover() }()
+			c.Next()
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			if writer.timeout() {
+				original.Header().Set("Content-Type", "application/json; charset=utf-8")
+				original.WriteHeader(http.StatusServiceUnavailable)
+				original.WriteString(`{"error":"Request timed out"}`)
+				original.Flush()
+			}
+			<-done
+		}
+
+		c.Writer = original
+		if panicked != nil {
+			panic(panicked)
+		}
+		writer.flushTo(original)
+	}
+}
+
+// timeoutWriter buffers a handler's response so it can be dropped in favour
+// of a 503 when the handler runs past its deadline
+type timeoutWriter struct {
+	gin.ResponseWriter
+	mu       sync.Mutex
+	header   http.Header
+	body     bytes.Buffer
+	status   int
+	written  bool
+	timedOut bool
+}
+
+// timeout marks the response as timed out, reporting false if the handler
+// finished first and its response should be sent instead
+func (w *timeoutWriter) timeout() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return false
+	}
+	w.timedOut = true
+	return true
+}
+
+// flushTo copies the buffered response to dst unless the request timed out
+func (w *timeoutWriter) flushTo(dst gin.ResponseWriter) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return
+	}
+	w.timedOut = true
+
+	for key, values := range w.header {
+		dst.Header()[key] = values
+	}
+	if w.status != 0 {
+		dst.WriteHeader(w.status)
+	}
+	if w.written {
+		dst.WriteHeaderNow()
+	}
+	dst.Write(w.body.Bytes())
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.written {
+		w.status = code
+	}
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.written = true
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *timeoutWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *timeoutWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *timeoutWriter) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.written
+}
+
+// Flush is a no-op since the response is only sent once the handler returns
+func (w *timeoutWriter) Flush() {}
+
+// Hijack is not supported on a buffered response
+func (w *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, errors.New("timeout middleware does not support hijacking")
+}
@@ -0,0 +1,94 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// timeoutRouter serves handler at /slow and /reports/:id behind middleware
+func timeoutRouter(middleware gin.HandlerFunc, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(gin.Recovery(), middleware)
+	router.GET("/slow", handler)
+	router.GET("/reports/:id", handler)
+	return router
+}
+
+func serve(router *gin.Engine, target string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, target, nil))
+	return response
+}
+
+// waitFor responds after d unless the request context ends first
+func waitFor(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		select {
+		case <-time.After(d):
+			c.Header("X-Handler", "done")
+			c.JSON(http.StatusCreated, gin.H{"status": "done"})
+		case <-c.Request.Context().Done():
+			// A late write must never reach the client
+			c.JSON(http.StatusOK, gin.H{"status": "late"})
+		}
+	}
+}
+
+func TestTimeoutAnswers503WhenHandlerRunsLate(t *testing.T) {
+	router := timeoutRouter(Timeout(20*time.Millisecond), waitFor(time.Second))
+
+	started := time.Now()
+	response := serve(router, "/slow")
+	if response.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", response.Code)
+	}
+	if body := response.Body.String(); body != `{"error":"Request timed out"}` {
+		t.Fatalf("expected only the timeout body, got %q", body)
+	}
+	if response.Header().Get("X-Handler") != "" {
+		t.Fatal("expected the late handler's headers to be discarded")
+	}
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected the handler to stop with its context, took %v", elapsed)
+	}
+}
+
+func TestTimeoutPassesFastResponseThrough(t *testing.T) {
+	router := timeoutRouter(Timeout(time.Second), waitFor(0))
+
+	response := serve(router, "/slow")
+	if response.Code != http.StatusCreated {
+		t.Fatalf("expected the handler's 201, got %d", response.Code)
+	}
+	if response.Header().Get("X-Handler") != "done" || !strings.Contains(response.Body.String(), "done") {
+		t.Fatalf("expected the handler's response, got %q", response.Body.String())
+	}
+}
+
+func TestTimeoutOverridesPerRoute(t *testing.T) {
+	middleware := TimeoutWithOverrides(20*time.Millisecond, map[string]time.Duration{
+		"/reports/:id": 0,
+	})
+	router := timeoutRouter(middleware, waitFor(50*time.Millisecond))
+
+	if response := serve(router, "/reports/7"); response.Code != http.StatusCreated {
+		t.Fatalf("expected a disabled timeout to let the route finish, got %d", response.Code)
+	}
+	if response := serve(router, "/slow"); response.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected the default timeout elsewhere, got %d", response.Code)
+	}
+}
+
+func TestTimeoutReraisesHandlerPanics(t *testing.T) {
+	router := timeoutRouter(Timeout(time.Second), func(c *gin.Context) { panic("boom") })
+
+	if response := serve(router, "/slow"); response.Code != http.StatusInternalServerError {
+		t.Fatalf("expected Recovery to turn the panic into a 500, got %d", response.Code)
+	}
+}
@@ -2,7 +2,6 @@ package processors
 
 import (
 	"base_lara_go_project/app/core"
-	"encoding/json"
 	"fmt"
 	"log"
 )
@@ -22,26 +21,11 @@ func (e *EventJobProcessor) CanProcess(jobType string) bool {
 
 // Process processes an event job
 func (e *EventJobProcessor) Process(jobData []byte) error {
-	var eventData map[string]interface{}
-	if err := json.Unmarshal(jobData, &eventData); err != nil {
-		return fmt.Errorf("failed to unmarshal event data: %v", err)
-	}
-
-	eventName, ok := eventData["eventName"].(string)
-	if !ok {
-		return fmt.Errorf("invalid event name in job data")
-	}
-
-	eventPayload, ok := eventData["event"].(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("invalid event payload in job data")
-	}
-
-	log.Printf("Processing event: %s", eventName)
-	event, err := core.CreateEvent(eventName, eventPayload)
+	queued, err := core.UnmarshalEvent(jobData)
 	if err != nil {
 		return fmt.Errorf("failed to create event: %v", err)
 	}
 
-	return core.EventDispatcherInstance.DispatchSync(event)
+	log.Printf("Processing event: %s", queued.Event.GetEventName())
+	return core.EventDispatcherInstance.DispatchSync(queued.Event)
 }
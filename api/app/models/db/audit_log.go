@@ -0,0 +1,19 @@
+package db
+
+import "time"
+
+// AuditLog records a single create, update or delete made through an auditable service
+type AuditLog struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    *uint     `gorm:"index" json:"user_id"`
+	Action    string    `gorm:"type:varchar(16);not null" json:"action"`
+	Table     string    `gorm:"column:table_name;type:varchar(64);not null;index:idx_audit_logs_record" json:"table"`
+	RecordID  uint      `gorm:"not null;index:idx_audit_logs_record" json:"record_id"`
+	OldValues string    `gorm:"type:json" json:"old_values"`
+	NewValues string    `gorm:"type:json" json:"new_values"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -36,9 +36,29 @@ func RegisterCache() {
 		cacheDriver = createArrayDriver(cacheConfig)
 	}
 
+	// Spread expirations if jitter is configured
+	if jitterable, ok := cacheDriver.(interface{ SetTTLJitter(float64) error }); ok {
+		if err := jitterable.SetTTLJitter(cacheConfig.TTLJitter); err != nil {
+			log.Fatalf("Invalid CACHE_TTL_JITTER: %v", err)
+		}
+	}
+
 	// Set up the global cache instance
 	core.CacheInstance = cacheDriver
 
+	// The app keeps working on a cache outage, only slower
+	statsProvider, _ := cacheDriver.(core.StatsProvider)
+	monitorClient(GlobalServiceContainer, monitoredClient{
+		name: "cache",
+		check: func(ctx context.Context) error {
+			if pinger, ok := cacheDriver.(interface{ Ping(context.Context) error }); ok {
+				return pinger.Ping(ctx)
+			}
+			return nil
+		},
+		stats: statsProvider,
+	})
+
 	log.Printf("Cache configured with %s driver", cacheConfig.Store)
 }
 
@@ -51,19 +71,28 @@ func createRedisDriver(config config.CacheConfig) core.CacheInterface {
 		DB:       config.Redis.Database,
 	})
 
+	driver := core.NewRedisCacheDriver(client, config.Prefix, config.TTL)
+	driver.SetMinBudget(config.Redis.MinBudget)
+	driver.SetScanCount(config.Redis.ScanCount)
+	driver.SetCompressThreshold(config.Redis.CompressThreshold)
+
 	// Test connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	_, err := client.Ping(ctx).Result()
-	if err != nil {
-		log.Printf("Warning: Redis connection failed: %v", err)
+	if err := driver.Ping(ctx); err != nil {
+		log.Printf("Warning: %v", err)
 		log.Println("Falling back to array cache driver")
+		client.Close()
 		return createArrayDriver(config)
 	}
 
+	core.RegisterShutdown("redis cache", core.ShutdownPriorityConnections, func(ctx context.Context) error {
+		return client.Close()
+	})
+
 	log.Println("Redis cache connected successfully")
-	return core.NewRedisCacheDriver(client, config.Prefix, config.TTL)
+	return driver
 }
 
 // createFileDriver creates a file cache driver
@@ -73,5 +102,8 @@ func createFileDriver(config config.CacheConfig) core.CacheInterface {
 
 // createArrayDriver creates an array cache driver
 func createArrayDriver(config config.CacheConfig) core.CacheInterface {
+	if config.Array.MaxBytes > 0 {
+		return core.NewArrayCacheDriverWithMemoryLimit(config.Prefix, config.TTL, config.Array.MaxBytes)
+	}
 	return core.NewArrayCacheDriver(config.Prefix, config.TTL)
 }
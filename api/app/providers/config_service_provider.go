@@ -1,16 +1,72 @@
 package providers
 
 import (
+	"context"
+	"log"
+	"os"
+
 	"base_lara_go_project/app/core"
+	"base_lara_go_project/app/utils/env"
+	"base_lara_go_project/app/validators"
 	"base_lara_go_project/config"
 )
 
 // RegisterConfig loads all config files and registers them with the config registry
 func RegisterConfig() {
 	core.LoadConfig(map[string]map[string]interface{}{
-		"app":      config.AppConfig(),
-		"database": config.DatabaseConfig(),
-		"mail":     config.MailConfig(),
-		"queue":    config.QueueConfig(),
+		"app":        config.AppConfig(),
+		"database":   config.DatabaseConfig(),
+		"mail":       config.MailConfig(),
+		"queue":      config.QueueConfig(),
+		"logging":    config.LoggingConfig(),
+		"cors":       config.CorsConfig(),
+		"monitoring": config.MonitoringConfig(),
 	})
+
+	// Config files dropped into CONFIG_PATH are registered under their base name
+	if dir := os.Getenv("CONFIG_PATH"); dir != "" {
+		if err := core.LoadConfigDir(dir); err != nil {
+			log.Fatalf("Failed to load config files: %v", err)
+		}
+	}
+
+	// Overlays such as app.production.yaml are merged into their base config
+	core.ApplyEnvironmentOverlays(core.GetString("app.env"))
+
+	// Let operators change file-based config without a restart
+	if env.EnvBool("CONFIG_WATCH", false) {
+		stop, err := core.WatchConfigFiles()
+		if err != nil {
+			log.Fatalf("Failed to watch config files: %v", err)
+		}
+		core.RegisterShutdown("config watcher", core.ShutdownPriorityIntake, func(ctx context.Context) error {
+			stop()
+			return nil
+		})
+	}
+
+	// A bad value should stop boot here rather than fail obscurely later
+	if err := validators.ValidateSchema("database", databaseSchema()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// databaseSchema describes the database config for the default connection
+func databaseSchema() map[string]validators.ConfigRule {
+	schema := map[string]validators.ConfigRule{
+		"default": {Required: true, Rules: "oneof=mysql sqlite"},
+	}
+
+	switch connection := core.GetString("database.default"); connection {
+	case "mysql":
+		prefix := "connections." + connection + "."
+		schema[prefix+"host"] = validators.ConfigRule{Required: true}
+		schema[prefix+"port"] = validators.ConfigRule{Required: true, Type: validators.ConfigInt, Rules: "min=1,max=65535"}
+		schema[prefix+"database"] = validators.ConfigRule{Required: true}
+		schema[prefix+"username"] = validators.ConfigRule{Required: true}
+		schema[prefix+"password"] = validators.ConfigRule{}
+	case "sqlite":
+		schema["connections.sqlite.database"] = validators.ConfigRule{Required: true}
+	}
+	return schema
 }
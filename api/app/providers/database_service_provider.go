@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -47,6 +48,27 @@ func RegisterDatabase() {
 	// Set up the global database instance with our provider
 	core.DatabaseInstance = core.NewDatabaseProvider(DB)
 
+	// Close the connection pool once everything else has stopped
+	core.RegisterShutdown("database", core.ShutdownPriorityConnections, func(ctx context.Context) error {
+		sqlDB, err := DB.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.Close()
+	})
+
+	monitorClient(GlobalServiceContainer, monitoredClient{
+		name:     "database",
+		critical: true,
+		check: func(ctx context.Context) error {
+			sqlDB, err := DB.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		},
+	})
+
 	// Register cacheable models for automatic cache invalidation
 	core.RegisterCacheableModel(DB, &db.User{})
 }
@@ -1,6 +1,8 @@
 package providers
 
 import (
+	"log"
+
 	"base_lara_go_project/app/validators"
 
 	"github.com/gin-gonic/gin/binding"
@@ -9,8 +11,50 @@ import (
 
 func RegisterFormFieldValidators() {
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		v.RegisterValidation("nameField", validators.NameField)
+		// Report fields by their json names so error keys match the request payload
+		v.RegisterTagNameFunc(validators.JSONFieldName)
+
+		// A rule that can't reach the database fails binding with its error
+		binding.Validator = validators.ReturnRuleErrors(binding.Validator)
+
+		for name, rule := range map[string]validators.RuleFunc{
+			"nameField": validators.NameField,
+			"confirmed": validators.Confirmed,
+		} {
+			if err := validators.RegisterRule(name, rule); err != nil {
+				log.Fatalf("Failed to register %s rule: %v", name, err)
+			}
+		}
+		for name, rule := range map[string]validators.RuleFuncCtx{
+			"unique": validators.Unique,
+			"exists": validators.Exists,
+		} {
+			if err := validators.RegisterRuleCtx(name, rule); err != nil {
+				log.Fatalf("Failed to register %s rule: %v", name, err)
+			}
+		}
+		// These pass when the field is absent, nil pointers included
+		for name, rule := range map[string]validators.RuleFunc{
+			"not_in": validators.NotIn,
+			"date":   validators.Date,
+		} {
+			if err := validators.RegisterOptionalRule(name, rule); err != nil {
+				log.Fatalf("Failed to register %s rule: %v", name, err)
+			}
+		}
+		for name, rule := range map[string]validators.RuleFuncCtx{
+			"between":        validators.Between,
+			"regex":          validators.Regex,
+			"size":           validators.Size,
+			"digits":         validators.Digits,
+			"digits_between": validators.DigitsBetween,
+		} {
+			if err := validators.RegisterOptionalRuleCtx(name, rule); err != nil {
+				log.Fatalf("Failed to register %s rule: %v", name, err)
+			}
+		}
+		v.RegisterAlias("alpha_num", "alphanum")
 
-		// Add more custom validators here as you create them:
+		// Apps can add their own rules anywhere with validators.RegisterRule
 	}
 }
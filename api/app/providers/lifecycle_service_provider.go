@@ -0,0 +1,38 @@
+package providers
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"base_lara_go_project/app/core"
+)
+
+// WaitForShutdown blocks until SIGINT or SIGTERM is received, then shuts down
+// every registered component within the configured shutdown timeout
+func WaitForShutdown() {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-quit
+	signal.Stop(quit)
+
+	log.Printf("Received %s, shutting down...", sig)
+
+	ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout())
+	defer cancel()
+
+	// ShutdownAll logs the outcome itself, before log output is closed
+	core.ShutdownAll(ctx)
+}
+
+// ShutdownTimeout returns the configured shutdown timeout
+func ShutdownTimeout() time.Duration {
+	timeout := core.GetDuration("app.shutdown_timeout", 30*time.Second)
+	if timeout <= 0 {
+		return 30 * time.Second
+	}
+	return timeout
+}
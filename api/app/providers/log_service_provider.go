@@ -0,0 +1,133 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"base_lara_go_project/app/core"
+)
+
+// logOutput is the writer the standard and structured loggers write to
+var (
+	logOutput      io.Writer
+	logOutputMutex sync.Mutex
+)
+
+// RegisterLogging points the standard logger and the structured logger at
+// the configured channel. Writes stay synchronous until StartAsyncLogging,
+// so a log.Fatal during boot can't exit with its message still queued.
+func RegisterLogging() {
+	writer, err := logChannelWriter(core.GetString("logging.channel", "stderr"), true)
+	if err != nil {
+		log.Fatalf("Failed to configure logging: %v", err)
+	}
+
+	if _, err := core.ParseLogLevel(core.GetString("logging.level", "debug")); err != nil {
+		log.Fatalf("Invalid log level: %v", err)
+	}
+
+	setLogOutput(writer)
+
+	// Closing the outermost writer flushes and closes everything beneath it
+	core.RegisterShutdown("logging", core.ShutdownPriorityLogging, func(ctx context.Context) error {
+		logOutputMutex.Lock()
+		defer logOutputMutex.Unlock()
+		if closer, ok := logOutput.(io.Closer); ok {
+			return closer.Close()
+		}
+		return nil
+	})
+}
+
+// StartAsyncLogging moves log writes off the caller's goroutine when
+// logging.async is set. Call it once boot has finished.
+func StartAsyncLogging() {
+	if !core.GetBool("logging.async", false) {
+		return
+	}
+
+	logOutputMutex.Lock()
+	writer := logOutput
+	logOutputMutex.Unlock()
+
+	if _, ok := writer.(*core.AsyncWriter); ok || writer == nil {
+		return
+	}
+	setLogOutput(core.NewAsyncWriter(writer, 4096, 64*1024, time.Second))
+}
+
+// FlushLogging blocks until queued log entries have been written
+func FlushLogging() {
+	logOutputMutex.Lock()
+	writer := logOutput
+	logOutputMutex.Unlock()
+
+	if async, ok := writer.(*core.AsyncWriter); ok {
+		async.Flush()
+	}
+}
+
+// fatalf logs a message, flushes any queued entries and exits. Use it
+// instead of log.Fatalf once async logging may have started.
+func fatalf(format string, args ...interface{}) {
+	log.Printf(format, args...)
+	FlushLogging()
+	os.Exit(1)
+}
+
+// setLogOutput points the standard and structured loggers at writer
+func setLogOutput(writer io.Writer) {
+	level, _ := core.ParseLogLevel(core.GetString("logging.level", "debug"))
+
+	logOutputMutex.Lock()
+	logOutput = writer
+	logOutputMutex.Unlock()
+
+	log.SetOutput(writer)
+	core.SetLogger(core.NewLogger(writer, level, core.GetString("logging.format", "text") == "json"))
+}
+
+// logChannelWriter builds the writer for a log channel. A stack channel
+// writes to each of the channels it lists, which may not be stacks themselves.
+func logChannelWriter(channel string, allowStack bool) (io.Writer, error) {
+	switch channel {
+	case "stderr":
+		// Hide os.Stderr's Close so shutting logging down never closes stderr
+		return struct{ io.Writer }{os.Stderr}, nil
+	case "file":
+		fileWriter, err := core.NewRotatingFileWriter(
+			core.GetString("logging.channels.file.path", "storage/logs/app.log"),
+			int64(core.GetInt("logging.channels.file.max_size", 100))*1024*1024,
+			core.GetInt("logging.channels.file.max_files", 7),
+			core.GetBool("logging.channels.file.daily", true),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return fileWriter, nil
+	case "stack":
+		if !allowStack {
+			return nil, fmt.Errorf("stack log channel cannot contain another stack")
+		}
+
+		var writers []io.Writer
+		for _, name := range core.GetStringSlice("logging.channels.stack.channels") {
+			writer, err := logChannelWriter(name, false)
+			if err != nil {
+				return nil, err
+			}
+			writers = append(writers, writer)
+		}
+		if len(writers) == 0 {
+			return nil, fmt.Errorf("stack log channel has no channels configured")
+		}
+		return core.NewStackWriter(writers...), nil
+	}
+
+	return nil, fmt.Errorf("unsupported log channel: %s", channel)
+}
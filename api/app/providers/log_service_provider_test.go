@@ -0,0 +1,61 @@
+package providers
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"base_lara_go_project/app/core"
+)
+
+func readLog(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestAsyncLoggingStartsOnlyAfterBoot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	core.Set("logging", map[string]interface{}{
+		"channel": "file",
+		"level":   "debug",
+		"async":   "true",
+		"channels": map[string]interface{}{
+			"file": map[string]interface{}{"path": path, "daily": "false"},
+		},
+	})
+	defer core.Set("logging", map[string]interface{}{})
+	defer log.SetOutput(os.Stderr)
+
+	RegisterLogging()
+	log.Print("during boot")
+	if !strings.Contains(readLog(t, path), "during boot") {
+		t.Fatal("expected boot messages to be written before the call returns")
+	}
+
+	StartAsyncLogging()
+	async, ok := logOutput.(*core.AsyncWriter)
+	if !ok {
+		t.Fatalf("expected an async writer after boot, got %T", logOutput)
+	}
+	StartAsyncLogging()
+	if logOutput != async {
+		t.Fatal("expected a second call not to wrap the async writer again")
+	}
+
+	log.Print("after boot")
+	FlushLogging()
+	if !strings.Contains(readLog(t, path), "after boot") {
+		t.Fatal("expected FlushLogging to write queued messages")
+	}
+
+	if err := core.ShutdownAll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,9 +1,13 @@
 package providers
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
 	"log"
+	"net"
 	"strconv"
+	"sync/atomic"
 
 	"base_lara_go_project/app/core"
 	"base_lara_go_project/config"
@@ -12,6 +16,7 @@ import (
 	"gopkg.in/gomail.v2"
 )
 
+// RegisterMailer configures the global mail service from the mail config
 func RegisterMailer() {
 	err := godotenv.Load(".env")
 	if err != nil {
@@ -29,6 +34,8 @@ func RegisterMailer() {
 	portStr := mailerConfig["port"].(string)
 	username := mailerConfig["username"].(string)
 	password := mailerConfig["password"].(string)
+	encryption := mailerConfig["encryption"].(string)
+	verifyPeer := mailerConfig["verify_peer"].(string) != "false"
 	from := fromConfig["address"].(string)
 	fromName := fromConfig["name"].(string)
 
@@ -40,20 +47,127 @@ func RegisterMailer() {
 
 	// Create mail configuration
 	mailConfigInstance := &core.MailConfig{
-		Host:     host,
-		Port:     port,
-		Username: username,
-		Password: password,
-		From:     from,
-		FromName: fromName,
+		Host:       host,
+		Port:       port,
+		Username:   username,
+		Password:   password,
+		From:       from,
+		FromName:   fromName,
+		Encryption: encryption,
 	}
 
-	// Create mailer dialer
-	mailer := gomail.NewDialer(host, port, username, password)
+	switch encryption {
+	case "ssl", "tls", "starttls", "":
+	default:
+		log.Fatalf("Invalid MAIL_ENCRYPTION: %s", encryption)
+	}
+
+	// The provider is only built once mail is first sent, so processes that
+	// never send mail don't pay for it at startup
+	mailer := deferMailer(GlobalServiceContainer, func() (interface{}, error) {
+		return newMailProvider(mailConfigInstance, verifyPeer), nil
+	})
+	core.SetMailService(mailer)
 
-	// Create mail provider and set global instance
-	mailProvider := core.NewMailProvider(mailConfigInstance, mailer)
-	core.SetMailService(mailProvider)
+	// Mail is sent from the queue and retried, so an unreachable server only
+	// degrades the app; the check just opens a TCP connection to it
+	monitorClient(GlobalServiceContainer, monitoredClient{
+		name: "mail",
+		check: func(ctx context.Context) error {
+			var dialer net.Dialer
+			conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, portStr))
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		},
+		stats: mailer,
+	})
 
 	fmt.Printf("Mailer configured for %s:%d\n", host, port)
 }
+
+// newMailProvider builds a mail provider for config
+func newMailProvider(config *core.MailConfig, verifyPeer bool) *core.MailProvider {
+	dialer := gomail.NewDialer(config.Host, config.Port, config.Username, config.Password)
+	dialer.SSL = config.Encryption == "ssl" || config.Encryption == "tls"
+	if !verifyPeer {
+		dialer.TLSConfig = &tls.Config{ServerName: config.Host, InsecureSkipVerify: true}
+	}
+	return core.NewMailProvider(config, dialer)
+}
+
+// deferredMailer is the mail service handed out before the provider is
+// built; it resolves the provider from the container on first use
+type deferredMailer struct {
+	container *ServiceContainer
+	provider  atomic.Pointer[core.MailProvider]
+}
+
+// deferMailer defers factory under "mail" in container and returns a mail
+// service that builds it on first use
+func deferMailer(container *ServiceContainer, factory DeferredFactory) *deferredMailer {
+	container.Defer("mail", factory)
+	return &deferredMailer{container: container}
+}
+
+// Ensure deferredMailer can stand in for the mail provider
+var _ core.MailService = (*deferredMailer)(nil)
+
+// resolve returns the provider, building it if this is the first use
+func (m *deferredMailer) resolve() (*core.MailProvider, error) {
+	if provider := m.provider.Load(); provider != nil {
+		return provider, nil
+	}
+	provider, err := core.ResolveAs[*core.MailProvider](m.container, "mail")
+	if err != nil {
+		return nil, err
+	}
+	m.provider.Store(provider)
+	return provider, nil
+}
+
+// SendMail sends an email, building the provider first if needed
+func (m *deferredMailer) SendMail(to []string, subject, body string) error {
+	provider, err := m.resolve()
+	if err != nil {
+		return err
+	}
+	return provider.SendMail(to, subject, body)
+}
+
+// SendMailWithAttachments sends an email with attachments, building the provider first if needed
+func (m *deferredMailer) SendMailWithAttachments(to []string, subject, body string, attachments []core.MailAttachment) error {
+	provider, err := m.resolve()
+	if err != nil {
+		return err
+	}
+	return provider.SendMailWithAttachments(to, subject, body, attachments)
+}
+
+// SendMailAsync queues an email, building the provider first if needed
+func (m *deferredMailer) SendMailAsync(to []string, subject, body string, queueName string) error {
+	provider, err := m.resolve()
+	if err != nil {
+		return err
+	}
+	return provider.SendMailAsync(to, subject, body, queueName)
+}
+
+// ProcessMailJobFromQueue sends a queued email, building the provider first if needed
+func (m *deferredMailer) ProcessMailJobFromQueue(jobData []byte) error {
+	provider, err := m.resolve()
+	if err != nil {
+		return err
+	}
+	return provider.ProcessMailJobFromQueue(jobData)
+}
+
+// GetStats reports the provider's counts without building it; nothing has
+// been sent before it is built
+func (m *deferredMailer) GetStats() map[string]interface{} {
+	if provider := m.provider.Load(); provider != nil {
+		return provider.GetStats()
+	}
+	return map[string]interface{}{"sent": int64(0), "failed": int64(0)}
+}
@@ -0,0 +1,33 @@
+package providers
+
+import (
+	"testing"
+
+	"base_lara_go_project/app/core"
+)
+
+func TestDeferredMailerBuildsProviderOnFirstResolve(t *testing.T) {
+	container := NewServiceContainer()
+	builds := 0
+	mailer := deferMailer(container, func() (interface{}, error) {
+		builds++
+		return newMailProvider(&core.MailConfig{Host: "localhost", Port: 1025}, true), nil
+	})
+
+	if stats := mailer.GetStats(); stats["sent"] != int64(0) || builds != 0 {
+		t.Fatalf("expected stats without building the provider, got %v after %d builds", stats, builds)
+	}
+
+	// A malformed job fails after the provider is resolved, without dialing
+	if err := mailer.ProcessMailJobFromQueue([]byte("{")); err == nil {
+		t.Fatal("expected the malformed job to fail")
+	}
+	if builds != 1 {
+		t.Fatalf("expected the first use to build the provider once, got %d builds", builds)
+	}
+
+	mailer.ProcessMailJobFromQueue([]byte("{"))
+	if _, exists := container.Get("mail"); !exists || builds != 1 {
+		t.Fatalf("expected the built provider to be reused, got %d builds", builds)
+	}
+}
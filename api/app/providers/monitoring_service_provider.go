@@ -0,0 +1,50 @@
+package providers
+
+import (
+	"base_lara_go_project/app/core"
+)
+
+// monitoredTag groups the backend clients the health and metrics endpoints
+// report on
+const monitoredTag = "monitored"
+
+// monitoredClient is a backend client bound under monitoredTag
+type monitoredClient struct {
+	name string
+	// critical marks the app down rather than degraded while check fails
+	critical bool
+	check    core.HealthCheck
+	// stats is nil for clients that keep no stats
+	stats core.StatsProvider
+}
+
+// monitorClient binds client in container and tags it, so RegisterMonitoring
+// reports on it without a hard-coded list of clients
+func monitorClient(container *ServiceContainer, client monitoredClient) {
+	name := monitoredTag + "." + client.name
+	container.Register(name, client)
+	container.Tag([]string{monitoredTag}, []string{name})
+}
+
+// RegisterMonitoring registers a health check and stats for every client
+// tagged as monitored in the global service container
+func RegisterMonitoring() {
+	registerMonitoring(GlobalServiceContainer, core.HealthInstance, core.MetricsInstance)
+}
+
+// registerMonitoring registers the clients tagged in container with the
+// health and metrics registries
+func registerMonitoring(container *ServiceContainer, health *core.HealthRegistry, metrics *core.MetricsRegistry) {
+	for _, service := range container.Tagged(monitoredTag) {
+		client, ok := service.(monitoredClient)
+		if !ok {
+			continue
+		}
+		if client.check != nil {
+			health.Register(client.name, client.critical, client.check)
+		}
+		if client.stats != nil {
+			metrics.Register(client.name, client.stats)
+		}
+	}
+}
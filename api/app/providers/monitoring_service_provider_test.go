@@ -0,0 +1,59 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"base_lara_go_project/app/core"
+)
+
+// fixedStats reports the same stats on every call
+type fixedStats map[string]interface{}
+
+func (s fixedStats) GetStats() map[string]interface{} { return s }
+
+func TestRegisterMonitoringReportsEveryTaggedClient(t *testing.T) {
+	container := NewServiceContainer()
+	up := func(ctx context.Context) error { return nil }
+	monitorClient(container, monitoredClient{name: "database", critical: true, check: up})
+	monitorClient(container, monitoredClient{name: "queue", critical: true, check: up, stats: fixedStats{"pending": 3}})
+	monitorClient(container, monitoredClient{
+		name:  "cache",
+		check: func(ctx context.Context) error { return errors.New("connection refused") },
+		stats: fixedStats{"hits": 7},
+	})
+
+	health := core.NewHealthRegistry()
+	metrics := core.NewMetricsRegistry()
+	registerMonitoring(container, health, metrics)
+
+	report := health.Check(context.Background(), time.Second)
+	if len(report.Components) != 3 || report.Status != core.HealthStatusDegraded {
+		t.Fatalf("expected three components with only the cache down, got %+v", report)
+	}
+
+	var output strings.Builder
+	if err := metrics.WritePrometheus(context.Background(), &output); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"app_queue_pending 3", "app_cache_hits 7"} {
+		if !strings.Contains(output.String(), line) {
+			t.Fatalf("expected %q in metrics, got %s", line, output.String())
+		}
+	}
+}
+
+func TestRegisterMonitoringWithoutTaggedClients(t *testing.T) {
+	container := NewServiceContainer()
+	container.Register("cache", "not monitored")
+
+	health := core.NewHealthRegistry()
+	registerMonitoring(container, health, core.NewMetricsRegistry())
+
+	if report := health.Check(context.Background(), time.Second); len(report.Components) != 0 || report.Status != core.HealthStatusUp {
+		t.Fatalf("expected no components, got %+v", report)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strconv"
+	"time"
 
 	"base_lara_go_project/app/core"
 	"base_lara_go_project/config"
@@ -33,13 +35,24 @@ func RegisterQueue() {
 	queue := connectionConfig["queue"].(string)
 	endpoint := connectionConfig["endpoint"].(string)
 
+	waitTime, err := strconv.Atoi(connectionConfig["wait_time"].(string))
+	if err != nil || waitTime < 0 || waitTime > 20 {
+		log.Fatalf("Invalid SQS_WAIT_TIME_SECONDS: %s", connectionConfig["wait_time"])
+	}
+	visibilityTimeout, err := strconv.Atoi(connectionConfig["visibility_timeout"].(string))
+	if err != nil || visibilityTimeout < 0 {
+		log.Fatalf("Invalid SQS_VISIBILITY_TIMEOUT: %s", connectionConfig["visibility_timeout"])
+	}
+
 	// Create queue configuration
 	queueConfigInstance := &core.QueueConfig{
-		AccessKey: accessKey,
-		SecretKey: secretKey,
-		Region:    region,
-		Queue:     queue,
-		Endpoint:  endpoint,
+		AccessKey:         accessKey,
+		SecretKey:         secretKey,
+		Region:            region,
+		Queue:             queue,
+		Endpoint:          endpoint,
+		WaitTimeSeconds:   int32(waitTime),
+		VisibilityTimeout: int32(visibilityTimeout),
 	}
 
 	// Create custom AWS config for ElasticMQ
@@ -71,9 +84,23 @@ func RegisterQueue() {
 	// Create queue if it doesn't exist
 	createQueueIfNotExists(sqsClient, queue)
 
-	// Create queue provider and set global instance
+	// Create queue provider and verify connectivity before use
 	queueProvider := core.NewQueueProvider(queueConfigInstance, sqsClient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := queueProvider.Ping(ctx); err != nil {
+		log.Fatalf("Queue connection failed: %v", err)
+	}
+
 	core.SetQueueService(queueProvider)
+	monitorClient(GlobalServiceContainer, monitoredClient{
+		name:     "queue",
+		critical: true,
+		check:    queueProvider.Ping,
+		stats:    queueProvider,
+	})
 
 	fmt.Printf("Queue service configured for %s (endpoint: %s)\n", queue, endpoint)
 }
@@ -26,6 +26,7 @@ func (p *RepositoryServiceProvider) Register() {
 	repositories.RegisterServiceRepository(db)
 	repositories.RegisterRoleRepository(db)
 	repositories.RegisterPermissionRepository(db)
+	repositories.RegisterAuditLogRepository(db)
 }
 
 // Boot performs any bootstrapping after registration
@@ -1,7 +1,15 @@
 package providers
 
 import (
-	"github.com/gin-contrib/cors"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"base_lara_go_project/app/core"
+	"base_lara_go_project/app/http/middlewares"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -12,15 +20,37 @@ func RegisterRouteGroup(registration func(*gin.Engine)) {
 }
 
 func RegisterRoutes(router *gin.Engine) {
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"https://app.baselaragoproject.test"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-	}))
+	router.Use(middlewares.RequestID(), middlewares.CORS())
+	if timeout := core.GetDuration("app.request_timeout"); timeout > 0 {
+		router.Use(middlewares.Timeout(timeout))
+	}
 
 	for _, registration := range routeRegistrations {
 		registration(router)
 	}
 }
+
+// Serve starts an HTTP server for router in the background and registers it
+// for graceful shutdown: on shutdown it stops accepting connections and waits
+// up to app.drain_timeout for in-flight requests to finish
+func Serve(router *gin.Engine, addr string) *http.Server {
+	server := &http.Server{
+		Addr:    addr,
+		Handler: router,
+	}
+
+	core.RegisterShutdown("http server", core.ShutdownPriorityIntake, func(ctx context.Context) error {
+		drainCtx, cancel := context.WithTimeout(ctx, core.GetDuration("app.drain_timeout", 20*time.Second))
+		defer cancel()
+		return server.Shutdown(drainCtx)
+	})
+
+	go func() {
+		log.Printf("HTTP server listening on %s", addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatalf("Server failed: %v", err)
+		}
+	}()
+
+	return server
+}
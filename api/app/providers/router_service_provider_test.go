@@ -0,0 +1,145 @@
+package providers
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"base_lara_go_project/app/core"
+
+	"github.com/gin-gonic/gin"
+)
+
+// freeAddr returns a local address nothing is listening on
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+// withLifecycle gives the test its own global lifecycle registry
+func withLifecycle(t *testing.T) {
+	t.Helper()
+	previous := core.LifecycleInstance
+	core.LifecycleInstance = core.NewLifecycleRegistry()
+	t.Cleanup(func() { core.LifecycleInstance = previous })
+}
+
+func TestServeDrainsInFlightRequestOnShutdown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	withLifecycle(t)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	router := gin.New()
+	router.GET("/slow", func(c *gin.Context) {
+		close(started)
+		<-release
+		c.String(http.StatusOK, "finished")
+	})
+
+	addr := freeAddr(t)
+	Serve(router, addr)
+
+	type result struct {
+		body string
+		err  error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		// The server starts in the background, so retry until it is listening
+		deadline := time.Now().Add(3 * time.Second)
+		for {
+			response, err := http.Get("http://" + addr + "/slow")
+			if err != nil && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+				continue
+			}
+			if err != nil {
+				responses <- result{err: err}
+				return
+			}
+			body, err := io.ReadAll(response.Body)
+			response.Body.Close()
+			responses <- result{string(body), err}
+			return
+		}
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(3 * time.Second):
+		t.Fatal("expected the request to reach the handler")
+	}
+
+	shutdownDone := make(chan error, 1)
+	go func() { shutdownDone <- core.ShutdownAll(context.Background()) }()
+
+	// Shutdown waits for the in-flight request instead of cutting it off
+	select {
+	case err := <-shutdownDone:
+		t.Fatalf("expected shutdown to wait for the request, returned %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if _, err := net.DialTimeout("tcp", addr, 100*time.Millisecond); err == nil {
+		t.Fatal("expected the server to stop accepting connections while draining")
+	}
+
+	close(release)
+	if response := <-responses; response.err != nil || response.body != "finished" {
+		t.Fatalf("expected the in-flight request to complete, got %q, %v", response.body, response.err)
+	}
+	if err := <-shutdownDone; err != nil {
+		t.Fatalf("expected a clean shutdown, got %v", err)
+	}
+}
+
+func TestServeStopsWaitingAfterDrainTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	withLifecycle(t)
+	core.Set("app.drain_timeout", "50ms")
+	t.Cleanup(func() { core.Set("app.drain_timeout", nil) })
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	router := gin.New()
+	router.GET("/stuck", func(c *gin.Context) {
+		close(started)
+		<-release
+	})
+
+	addr := freeAddr(t)
+	Serve(router, addr)
+	go func() {
+		deadline := time.Now().Add(3 * time.Second)
+		for time.Now().Before(deadline) {
+			if response, err := http.Get("http://" + addr + "/stuck"); err == nil {
+				response.Body.Close()
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(3 * time.Second):
+		t.Fatal("expected the request to reach the handler")
+	}
+
+	begin := time.Now()
+	if err := core.ShutdownAll(context.Background()); err == nil {
+		t.Fatal("expected the stuck request to be reported")
+	}
+	if elapsed := time.Since(begin); elapsed > 2*time.Second {
+		t.Fatalf("expected shutdown to give up after the drain timeout, took %v", elapsed)
+	}
+}
@@ -1,22 +1,44 @@
 package providers
 
 import (
+	"base_lara_go_project/app/core"
 	"base_lara_go_project/app/facades"
 	"base_lara_go_project/app/services"
+	"errors"
+	"fmt"
 	"log"
+	"slices"
 	"sync"
 )
 
+// DeferredFactory builds a service the first time it is requested
+type DeferredFactory func() (interface{}, error)
+
+// deferredService is a service waiting to be built on first use
+type deferredService struct {
+	factory DeferredFactory
+	once    sync.Once
+	service interface{}
+	err     error
+}
+
 // ServiceContainer holds all registered services
 type ServiceContainer struct {
 	services map[string]interface{}
-	mutex    sync.RWMutex
+	deferred map[string]*deferredService
+	tags     map[string][]string
+	// contextual maps a consumer to the service it gets for each name it needs
+	contextual map[string]map[string]string
+	mutex      sync.RWMutex
 }
 
 // NewServiceContainer creates a new service container
 func NewServiceContainer() *ServiceContainer {
 	return &ServiceContainer{
-		services: make(map[string]interface{}),
+		services:   make(map[string]interface{}),
+		deferred:   make(map[string]*deferredService),
+		tags:       make(map[string][]string),
+		contextual: make(map[string]map[string]string),
 	}
 }
 
@@ -24,34 +46,159 @@ func NewServiceContainer() *ServiceContainer {
 func (sc *ServiceContainer) Register(name string, service interface{}) {
 	sc.mutex.Lock()
 	defer sc.mutex.Unlock()
+	delete(sc.deferred, name)
 	sc.services[name] = service
 }
 
-// Get retrieves a service by name
+// Defer registers a factory that builds the named service on first use, so
+// rarely used services don't slow down startup
+func (sc *ServiceContainer) Defer(name string, factory DeferredFactory) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	delete(sc.services, name)
+	sc.deferred[name] = &deferredService{factory: factory}
+}
+
+// Get retrieves a service by name, building it first if it was deferred
 func (sc *ServiceContainer) Get(name string) (interface{}, bool) {
 	sc.mutex.RLock()
-	defer sc.mutex.RUnlock()
 	service, exists := sc.services[name]
-	return service, exists
+	pending, isDeferred := sc.deferred[name]
+	sc.mutex.RUnlock()
+
+	if exists || !isDeferred {
+		return service, exists
+	}
+
+	// The factory runs outside the container lock so it can resolve its own
+	// dependencies; concurrent callers wait on the same once
+	pending.once.Do(func() {
+		pending.service, pending.err = pending.factory()
+	})
+	if pending.err != nil {
+		log.Printf("Failed to build deferred service %s: %v", name, pending.err)
+		return nil, false
+	}
+
+	sc.mutex.Lock()
+	if sc.deferred[name] == pending {
+		sc.services[name] = pending.service
+		delete(sc.deferred, name)
+	}
+	sc.mutex.Unlock()
+
+	return pending.service, true
+}
+
+// Tag groups services under one or more tags so they can be resolved together
+func (sc *ServiceContainer) Tag(tags []string, names []string) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	for _, tag := range tags {
+		for _, name := range names {
+			if !slices.Contains(sc.tags[tag], name) {
+				sc.tags[tag] = append(sc.tags[tag], name)
+			}
+		}
+	}
+}
+
+// Tagged resolves every service under a tag in the order they were tagged,
+// skipping any that are no longer registered
+func (sc *ServiceContainer) Tagged(tag string) []interface{} {
+	sc.mutex.RLock()
+	names := slices.Clone(sc.tags[tag])
+	sc.mutex.RUnlock()
+
+	services := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		if service, exists := sc.Get(name); exists {
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+// ContextualBinding builds a binding that applies to a single consumer
+type ContextualBinding struct {
+	container *ServiceContainer
+	consumer  string
+	needs     string
+}
+
+// When starts a contextual binding for a consumer, e.g.
+// When("report").Needs("cache").Give("redis_cache")
+func (sc *ServiceContainer) When(consumer string) *ContextualBinding {
+	return &ContextualBinding{container: sc, consumer: consumer}
+}
+
+// Needs names the service the consumer asks for
+func (b *ContextualBinding) Needs(name string) *ContextualBinding {
+	b.needs = name
+	return b
+}
+
+// Give names the service the consumer receives in its place
+func (b *ContextualBinding) Give(name string) {
+	b.container.mutex.Lock()
+	defer b.container.mutex.Unlock()
+
+	if b.container.contextual[b.consumer] == nil {
+		b.container.contextual[b.consumer] = make(map[string]string)
+	}
+	b.container.contextual[b.consumer][b.needs] = name
+}
+
+// GetFor retrieves the service a consumer gets for name, honoring contextual
+// bindings before falling back to the global one
+func (sc *ServiceContainer) GetFor(consumer string, name string) (interface{}, bool) {
+	sc.mutex.RLock()
+	given, bound := sc.contextual[consumer][name]
+	sc.mutex.RUnlock()
+
+	if bound {
+		return sc.Get(given)
+	}
+	return sc.Get(name)
+}
+
+// AutoResolve injects registered services into target's inject-tagged fields
+func (sc *ServiceContainer) AutoResolve(target interface{}) error {
+	return core.AutoResolve(sc, target)
 }
 
 // Global service container instance
 var GlobalServiceContainer = NewServiceContainer()
 
-// RegisterServices registers all services with facades
+// Ensure ServiceContainer can be used with core.ResolveAs
+var _ core.Container = (*ServiceContainer)(nil)
+
+// RegisterServices registers all services with facades, logging any failures
+// and carrying on with the services that did register
 func RegisterServices() {
+	if err := RegisterServicesStrict(); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// RegisterServicesStrict registers all services with facades and returns every
+// failure joined together, so the caller can refuse to start half-initialized
+func RegisterServicesStrict() error {
+	var errs []error
+
 	// Create base user service
 	userService, err := services.NewUserService()
 	if err == nil {
 		// Register the base service
-		GlobalServiceContainer.Register("user", userService)
+		core.BindSingleton(GlobalServiceContainer, "user", userService)
 
 		// Set up the service facade
 		facades.SetUserService(userService)
 
 		log.Println("User service registered successfully")
 	} else {
-		log.Printf("Failed to register user service: %v", err)
+		errs = append(errs, fmt.Errorf("failed to register user service: %w", err))
 	}
 
 	// Add more services here as they are created
@@ -60,15 +207,15 @@ func RegisterServices() {
 	// if err == nil {
 	//     GlobalServiceContainer.Register("role", roleService)
 	//     facades.SetRoleService(roleService)
+	// } else {
+	//     errs = append(errs, fmt.Errorf("failed to register role service: %w", err))
 	// }
+
+	return errors.Join(errs...)
 }
 
 // GetUserService is a global helper to get the user service
 func GetUserService() (*services.UserService, bool) {
-	if service, exists := GlobalServiceContainer.Get("user"); exists {
-		if userService, ok := service.(*services.UserService); ok {
-			return userService, true
-		}
-	}
-	return nil, false
+	userService, err := core.ResolveAs[*services.UserService](GlobalServiceContainer, "user")
+	return userService, err == nil
 }
@@ -0,0 +1,93 @@
+package providers
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTaggedResolvesEveryTaggedBinding(t *testing.T) {
+	container := NewServiceContainer()
+	container.Register("redis", "redis client")
+	container.Register("sqs", "sqs client")
+	container.Defer("smtp", func() (interface{}, error) { return "smtp client", nil })
+	container.Register("unrelated", "unrelated service")
+
+	container.Tag([]string{"clients"}, []string{"redis", "sqs"})
+	container.Tag([]string{"clients"}, []string{"smtp", "redis"})
+
+	want := []interface{}{"redis client", "sqs client", "smtp client"}
+	if got := container.Tagged("clients"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v in tag order without duplicates, got %v", want, got)
+	}
+}
+
+func TestTaggedWithoutBindingsIsEmpty(t *testing.T) {
+	container := NewServiceContainer()
+	container.Register("redis", "redis client")
+	container.Tag([]string{"clients"}, []string{"missing"})
+
+	for _, tag := range []string{"unknown", "clients"} {
+		if got := container.Tagged(tag); got == nil || len(got) != 0 {
+			t.Fatalf("expected an empty slice for %q, got %#v", tag, got)
+		}
+	}
+}
+
+// testCache stands in for a cache driver so instances can be told apart
+type testCache struct{ name string }
+
+func TestGetForGivesConsumersDifferentInstances(t *testing.T) {
+	container := NewServiceContainer()
+	local := &testCache{name: "local"}
+	distributed := &testCache{name: "redis"}
+	container.Register("cache", local)
+	container.Register("redis_cache", distributed)
+
+	container.When("ReportService").Needs("cache").Give("redis_cache")
+
+	report, _ := container.GetFor("ReportService", "cache")
+	user, _ := container.GetFor("UserService", "cache")
+	if report != distributed || user != local {
+		t.Fatalf("expected ReportService to get redis and UserService the local cache, got %v and %v", report, user)
+	}
+	if global, _ := container.Get("cache"); global != local {
+		t.Fatalf("expected the global binding to be unchanged, got %v", global)
+	}
+}
+
+func TestGetForContextualBindingToMissingService(t *testing.T) {
+	container := NewServiceContainer()
+	container.Register("cache", &testCache{name: "local"})
+	container.When("ReportService").Needs("cache").Give("redis_cache")
+
+	if service, exists := container.GetFor("ReportService", "cache"); exists {
+		t.Fatalf("expected the missing contextual service not to fall back, got %v", service)
+	}
+}
+
+func TestRegisterServicesStrictSurfacesFailures(t *testing.T) {
+	// No user repository is registered, so the user service cannot be built
+	err := RegisterServicesStrict()
+	if err == nil || !strings.Contains(err.Error(), "failed to register user service") {
+		t.Fatalf("expected the user service failure to be returned, got %v", err)
+	}
+	if _, ok := GetUserService(); ok {
+		t.Fatal("expected no user service to be registered")
+	}
+}
+
+func TestRegisterServicesLogsFailuresAndCarriesOn(t *testing.T) {
+	var output bytes.Buffer
+	log.SetOutput(&output)
+	defer log.SetOutput(os.Stderr)
+
+	RegisterServices()
+
+	if !strings.Contains(output.String(), "Warning: failed to register user service") {
+		t.Fatalf("expected the failure to be logged, got %q", output.String())
+	}
+}
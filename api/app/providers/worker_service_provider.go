@@ -0,0 +1,156 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+
+	"base_lara_go_project/app/core"
+)
+
+// Worker exit codes. A process manager should restart the worker whenever it
+// exits with WorkerExitRestart.
+const (
+	WorkerExitOK      = 0
+	WorkerExitRestart = 12
+)
+
+// workerCheckInterval is how often RunWorker checks the job and memory
+// limits; tests shorten it
+var workerCheckInterval = 5 * time.Second
+
+// WorkerConfig holds the limits after which a worker stops to be restarted
+type WorkerConfig struct {
+	// MaxJobs stops the worker after this many jobs; 0 means no limit
+	MaxJobs int64
+	// MemoryLimit stops the worker once the heap reaches this many bytes; 0 means no limit
+	MemoryLimit uint64
+}
+
+// LoadWorkerConfig reads the worker limits from the queue config
+func LoadWorkerConfig() WorkerConfig {
+	return WorkerConfig{
+		MaxJobs:     int64(core.GetInt("queue.worker.max_jobs")),
+		MemoryLimit: uint64(core.GetInt("queue.worker.memory_limit")) * 1024 * 1024,
+	}
+}
+
+// NewWorker builds the queue worker for the enabled queues, prioritized if
+// queue.prioritized is set, with the configured per-queue rate limits
+func NewWorker() *core.QueueWorker {
+	enabledQueues := core.GetStringSlice("queue.enabled_queues")
+
+	var worker *core.QueueWorker
+	if core.GetBool("queue.prioritized") {
+		worker = core.NewPriorityQueueWorker(enabledQueues, core.GetInt("queue.fairness_quota", 10))
+	} else {
+		worker = core.NewQueueWorker(enabledQueues)
+	}
+
+	for _, role := range []string{"jobs", "mail", "events"} {
+		worker.SetRateLimit(
+			core.GetString("queue.queues."+role),
+			core.GetFloat("queue.rate_limits."+role+".rate"),
+			core.GetInt("queue.rate_limits."+role+".burst", 1),
+		)
+	}
+	return worker
+}
+
+// RegisterWorkerMetrics reports the worker's job metrics and, when
+// queue.worker.metrics_addr is set, serves them at /metrics on that address
+// since the worker process has no HTTP server of its own
+func RegisterWorkerMetrics(worker *core.QueueWorker) {
+	core.RegisterStats("worker", worker)
+
+	addr := core.GetString("queue.worker.metrics_addr")
+	if addr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := core.MetricsInstance.WritePrometheus(r.Context(), w); err != nil {
+			log.Printf("Error writing worker metrics: %v", err)
+		}
+	})
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Worker metrics server failed: %v", err)
+		}
+	}()
+	core.RegisterShutdown("worker metrics server", core.ShutdownPriorityIntake, server.Shutdown)
+}
+
+// RunWorker starts the worker and blocks until ctx is done, SIGINT or SIGTERM
+// is received, or a limit in config is reached. Every registered component is
+// then shut down, with the worker drained after intake stops and before
+// connections are closed. It returns the exit
+// code for the process: WorkerExitRestart when a limit was reached.
+func RunWorker(ctx context.Context, worker *core.QueueWorker, config WorkerConfig) int {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	go worker.Start()
+
+	ticker := time.NewTicker(workerCheckInterval)
+	defer ticker.Stop()
+
+	code := WorkerExitOK
+wait:
+	for {
+		select {
+		case sig := <-quit:
+			log.Printf("Received %s, shutting down...", sig)
+			break wait
+		case <-ctx.Done():
+			log.Println("Worker context done, shutting down...")
+			break wait
+		case <-worker.Done():
+			log.Println("Worker stopped, shutting down...")
+			break wait
+		case <-ticker.C:
+			if reason := workerLimitReached(worker, config); reason != "" {
+				log.Printf("Worker %s, stopping for restart", reason)
+				code = WorkerExitRestart
+				break wait
+			}
+		}
+	}
+
+	// Draining in the drain band finishes in-flight jobs before the
+	// connections they use are closed, within the shared shutdown budget
+	core.RegisterShutdown("queue worker", core.ShutdownPriorityDrain, worker.Shutdown)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout())
+	defer cancel()
+
+	// ShutdownAll logs the outcome itself, before log output is closed
+	core.ShutdownAll(shutdownCtx)
+	return code
+}
+
+// workerLimitReached describes the limit the worker has reached, if any
+func workerLimitReached(worker *core.QueueWorker, config WorkerConfig) string {
+	if config.MaxJobs > 0 && worker.Processed() >= config.MaxJobs {
+		return "reached its job limit"
+	}
+	if config.MemoryLimit > 0 {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		if stats.HeapAlloc >= config.MemoryLimit {
+			return "exceeded its memory limit"
+		}
+	}
+	return ""
+}
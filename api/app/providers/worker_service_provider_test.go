@@ -0,0 +1,175 @@
+package providers
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"base_lara_go_project/app/core"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+func TestNewWorkerBuildsOneWorkerOfTheConfiguredKind(t *testing.T) {
+	defer core.Set("queue", map[string]interface{}{})
+
+	for _, prioritized := range []bool{false, true} {
+		core.Set("queue", map[string]interface{}{
+			"enabled_queues": "jobs,mail",
+			"prioritized":    prioritized,
+		})
+
+		worker := NewWorker()
+		if worker.Prioritized() != prioritized {
+			t.Fatalf("expected prioritized=%v, got %v", prioritized, worker.Prioritized())
+		}
+		worker.Stop()
+	}
+}
+
+// workerQueue is a queue service holding pending messages for one queue and
+// counting the messages deleted once their job succeeds
+type workerQueue struct {
+	core.QueueService
+	mutex   sync.Mutex
+	pending int
+	deleted int
+}
+
+func (q *workerQueue) ReceiveMessageFromQueueWithContext(ctx context.Context, queueName string, maxMessages int32) (*sqs.ReceiveMessageOutput, error) {
+	q.mutex.Lock()
+	if q.pending > 0 {
+		q.pending--
+		q.mutex.Unlock()
+		return &sqs.ReceiveMessageOutput{Messages: []types.Message{{Body: aws.String("{}"), ReceiptHandle: aws.String("handle")}}}, nil
+	}
+	q.mutex.Unlock()
+
+	// Long poll briefly, ending early once the worker stops
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(10 * time.Millisecond):
+		return &sqs.ReceiveMessageOutput{}, nil
+	}
+}
+
+func (q *workerQueue) DeleteMessageFromQueue(receiptHandle string, queueName string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.deleted++
+	return nil
+}
+
+func (q *workerQueue) deletedCount() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.deleted
+}
+
+// blockingJobs is a job dispatcher whose jobs signal started and then wait
+// for release
+type blockingJobs struct {
+	core.JobDispatcherService
+	started  chan struct{}
+	release  chan struct{}
+	finished atomic.Int32
+}
+
+func (j *blockingJobs) ProcessJobFromQueue(jobData []byte, jobType string) error {
+	j.started <- struct{}{}
+	<-j.release
+	j.finished.Add(1)
+	return nil
+}
+
+// withWorkerServices points the worker at queue and jobs, with a fresh
+// lifecycle registry for RunWorker to shut down
+func withWorkerServices(t *testing.T, queue *workerQueue, jobs *blockingJobs) {
+	t.Helper()
+	previousQueue, previousJobs := core.QueueServiceInstance, core.JobDispatcherServiceInstance
+	previousMessages, previousLifecycle := core.MessageProcessorServiceInstance, core.LifecycleInstance
+	core.SetQueueService(queue)
+	core.SetJobDispatcherService(jobs)
+	core.SetMessageProcessorService(core.NewMessageProcessorProvider())
+	core.LifecycleInstance = core.NewLifecycleRegistry()
+	t.Cleanup(func() {
+		core.SetQueueService(previousQueue)
+		core.SetJobDispatcherService(previousJobs)
+		core.SetMessageProcessorService(previousMessages)
+		core.LifecycleInstance = previousLifecycle
+	})
+}
+
+func TestRunWorkerDrainsInFlightJobOnSignal(t *testing.T) {
+	queue := &workerQueue{pending: 1}
+	jobs := &blockingJobs{started: make(chan struct{}, 1), release: make(chan struct{})}
+	withWorkerServices(t, queue, jobs)
+
+	// Keep SIGTERM from ending the test process if it arrives unhandled
+	sigterm := make(chan os.Signal, 1)
+	signal.Notify(sigterm, syscall.SIGTERM)
+	defer signal.Stop(sigterm)
+
+	exit := make(chan int, 1)
+	go func() { exit <- RunWorker(context.Background(), core.NewQueueWorker([]string{"jobs"}), WorkerConfig{}) }()
+
+	<-jobs.started
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case code := <-exit:
+		t.Fatalf("expected the worker to wait for its in-flight job, exited with %d", code)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(jobs.release)
+	select {
+	case code := <-exit:
+		if code != WorkerExitOK {
+			t.Fatalf("expected exit code %d on a signal, got %d", WorkerExitOK, code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the worker to exit once its job finished")
+	}
+	if jobs.finished.Load() != 1 || queue.deletedCount() != 1 {
+		t.Fatalf("expected the in-flight job to finish and be deleted, got %d finished and %d deleted", jobs.finished.Load(), queue.deletedCount())
+	}
+}
+
+func TestRunWorkerReturnsRestartCodeAtJobLimit(t *testing.T) {
+	previousInterval := workerCheckInterval
+	workerCheckInterval = 10 * time.Millisecond
+	t.Cleanup(func() { workerCheckInterval = previousInterval })
+
+	queue := &workerQueue{pending: 2}
+	jobs := &blockingJobs{started: make(chan struct{}, 2), release: make(chan struct{})}
+	close(jobs.release)
+	withWorkerServices(t, queue, jobs)
+
+	exit := make(chan int, 1)
+	go func() {
+		exit <- RunWorker(context.Background(), core.NewQueueWorker([]string{"jobs"}), WorkerConfig{MaxJobs: 2})
+	}()
+
+	select {
+	case code := <-exit:
+		if code != WorkerExitRestart {
+			t.Fatalf("expected exit code %d at the job limit, got %d", WorkerExitRestart, code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the worker to stop at its job limit")
+	}
+	if queue.deletedCount() != 2 {
+		t.Fatalf("expected both jobs to complete before exit, got %d deleted", queue.deletedCount())
+	}
+}
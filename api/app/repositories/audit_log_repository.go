@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"base_lara_go_project/app/core"
+	"base_lara_go_project/app/models/db"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// auditLogFields are the columns GetAuditLogByField may filter on
+var auditLogFields = map[string]string{
+	"id":        "id",
+	"user_id":   "user_id",
+	"action":    "action",
+	"table":     "table_name",
+	"record_id": "record_id",
+}
+
+// AuditLogRepository persists audit log entries
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(db *gorm.DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+// Record stores an audit log entry
+func (r *AuditLogRepository) Record(entry core.AuditLog) error {
+	oldValues, err := json.Marshal(entry.OldValues)
+	if err != nil {
+		return fmt.Errorf("failed to encode old values: %w", err)
+	}
+	newValues, err := json.Marshal(entry.NewValues)
+	if err != nil {
+		return fmt.Errorf("failed to encode new values: %w", err)
+	}
+
+	return r.db.Create(&db.AuditLog{
+		UserID:    entry.UserID,
+		Action:    entry.Action,
+		Table:     entry.Table,
+		RecordID:  entry.RecordID,
+		OldValues: string(oldValues),
+		NewValues: string(newValues),
+	}).Error
+}
+
+// ForRecord returns the audit log of a record, oldest first
+func (r *AuditLogRepository) ForRecord(table string, recordID uint) ([]core.AuditLog, error) {
+	return r.find(r.db.Where("table_name = ? AND record_id = ?", table, recordID))
+}
+
+// ByField returns audit log entries of a table matching a field, oldest first
+func (r *AuditLogRepository) ByField(table string, field string, value interface{}) ([]core.AuditLog, error) {
+	column, ok := auditLogFields[field]
+	if !ok {
+		return nil, fmt.Errorf("cannot filter audit log by field %s", field)
+	}
+	return r.find(r.db.Where("table_name = ?", table).Where(column+" = ?", value))
+}
+
+// find runs an audit log query and converts the rows
+func (r *AuditLogRepository) find(query *gorm.DB) ([]core.AuditLog, error) {
+	var rows []db.AuditLog
+	if err := query.Order("created_at ASC, id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]core.AuditLog, 0, len(rows))
+	for _, row := range rows {
+		entry := core.AuditLog{
+			ID:        row.ID,
+			UserID:    row.UserID,
+			Action:    row.Action,
+			Table:     row.Table,
+			RecordID:  row.RecordID,
+			CreatedAt: row.CreatedAt.Format(time.RFC3339),
+		}
+		json.Unmarshal([]byte(row.OldValues), &entry.OldValues)
+		json.Unmarshal([]byte(row.NewValues), &entry.NewValues)
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Ensure AuditLogRepository implements core.AuditStore
+var _ core.AuditStore = (*AuditLogRepository)(nil)
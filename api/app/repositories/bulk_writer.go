@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxPlaceholders keeps each statement under MySQL's prepared statement limit
+const maxPlaceholders = 65535
+
+// CreateMany inserts models with one multi-row INSERT per chunk, sizing the
+// chunks so no statement exceeds the placeholder limit
+func CreateMany[T any](db *gorm.DB, models []*T) error {
+	if len(models) == 0 {
+		return nil
+	}
+
+	batchSize, err := insertBatchSize[T](db)
+	if err != nil {
+		return err
+	}
+	return db.CreateInBatches(models, batchSize).Error
+}
+
+// UpsertMany inserts models, updating updateColumns on rows that conflict on
+// conflictColumns. MySQL resolves conflicts on any unique key and ignores
+// conflictColumns; other dialects use them as the ON CONFLICT target.
+func UpsertMany[T any](db *gorm.DB, models []*T, conflictColumns []string, updateColumns []string) error {
+	if len(models) == 0 {
+		return nil
+	}
+	if len(updateColumns) == 0 {
+		return fmt.Errorf("upsert needs at least one column to update")
+	}
+
+	batchSize, err := insertBatchSize[T](db)
+	if err != nil {
+		return err
+	}
+
+	conflict := clause.OnConflict{DoUpdates: clause.AssignmentColumns(updateColumns)}
+	for _, column := range conflictColumns {
+		conflict.Columns = append(conflict.Columns, clause.Column{Name: column})
+	}
+	return db.Clauses(conflict).CreateInBatches(models, batchSize).Error
+}
+
+// insertBatchSize returns how many rows of T fit in one INSERT
+func insertBatchSize[T any](db *gorm.DB) (int, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return 0, err
+	}
+
+	columns := len(stmt.Schema.DBNames)
+	if columns == 0 {
+		return 0, fmt.Errorf("%s has no columns to insert", stmt.Schema.Name)
+	}
+	return maxPlaceholders / columns, nil
+}
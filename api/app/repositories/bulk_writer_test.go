@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type bulkRow struct {
+	ID    uint `gorm:"primaryKey"`
+	Sku   string
+	Title string
+}
+
+func bulkRows(n int) []*bulkRow {
+	rows := make([]*bulkRow, n)
+	for i := range rows {
+		rows[i] = &bulkRow{Sku: fmt.Sprintf("sku-%d", i), Title: "item"}
+	}
+	return rows
+}
+
+func TestCreateManyIssuesOneInsertPerChunk(t *testing.T) {
+	recorder := &queryRecorder{}
+	db := openRecorderDB(t, recorder)
+
+	if err := CreateMany(db, bulkRows(3)); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.queries) != 1 {
+		t.Fatalf("expected a single statement, got %d", len(recorder.queries))
+	}
+	want := "INSERT INTO `bulk_rows` (`sku`,`title`) VALUES (?,?),(?,?),(?,?)"
+	if recorder.queries[0] != want {
+		t.Fatalf("expected %q, got %q", want, recorder.queries[0])
+	}
+
+	// bulkRow has three columns, so a chunk holds maxPlaceholders/3 rows
+	recorder.queries = nil
+	perChunk := maxPlaceholders / 3
+	if err := CreateMany(db, bulkRows(2*perChunk+1)); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.queries) != 3 {
+		t.Fatalf("expected one statement per chunk, got %d", len(recorder.queries))
+	}
+	for i, query := range recorder.queries {
+		if placeholders := strings.Count(query, "?"); placeholders > maxPlaceholders {
+			t.Fatalf("statement %d has %d placeholders, over the limit", i, placeholders)
+		}
+	}
+	if rows := strings.Count(recorder.queries[2], "(?,?)"); rows != 1 {
+		t.Fatalf("expected the last chunk to hold the remaining row, got %d rows", rows)
+	}
+}
+
+func TestUpsertManyUpdatesConflictingRows(t *testing.T) {
+	recorder := &queryRecorder{}
+	db := openRecorderDB(t, recorder)
+
+	if err := UpsertMany(db, bulkRows(2), []string{"sku"}, []string{"title"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.queries) != 1 {
+		t.Fatalf("expected a single statement, got %q", recorder.queries)
+	}
+	want := "INSERT INTO `bulk_rows` (`sku`,`title`) VALUES (?,?),(?,?) ON DUPLICATE KEY UPDATE `title`=VALUES(`title`)"
+	if recorder.queries[0] != want {
+		t.Fatalf("expected %q, got %q", want, recorder.queries[0])
+	}
+
+	if err := UpsertMany(db, bulkRows(1), []string{"sku"}, nil); err == nil {
+		t.Fatal("expected an upsert without update columns to be rejected")
+	}
+	if err := UpsertMany[bulkRow](db, nil, []string{"sku"}, []string{"title"}); err != nil || len(recorder.queries) != 1 {
+		t.Fatalf("expected no statement for no rows, got %v", err)
+	}
+}
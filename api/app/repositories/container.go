@@ -72,6 +72,22 @@ type UserRepositoryInterface interface {
 // Ensure UserRepository implements UserRepositoryInterface
 var _ UserRepositoryInterface = (*UserRepository)(nil)
 
+// RegisterAuditLogRepository registers the audit log repository
+func RegisterAuditLogRepository(db *gorm.DB) {
+	auditLogRepo := NewAuditLogRepository(db)
+	GlobalRepositoryContainer.Register("audit_log", auditLogRepo)
+}
+
+// GetAuditLogRepository is a global helper to get the audit log repository
+func GetAuditLogRepository() (*AuditLogRepository, bool) {
+	if repo, exists := GlobalRepositoryContainer.Get("audit_log"); exists {
+		if auditLogRepo, ok := repo.(*AuditLogRepository); ok {
+			return auditLogRepo, true
+		}
+	}
+	return nil, false
+}
+
 // RegisterCategoryRepository registers the category repository
 func RegisterCategoryRepository(db *gorm.DB) {
 	categoryRepo := NewCategoryRepository(db)
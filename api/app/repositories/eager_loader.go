@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// LoadRelation eager loads a has-one, has-many or belongs-to relation onto
+// models that are already loaded, using a single IN query however many
+// models there are. Many-to-many and polymorphic relations are not supported;
+// load those with Preload when querying the parents instead.
+func LoadRelation[T any](db *gorm.DB, models []*T, relation string) error {
+	if len(models) == 0 {
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return err
+	}
+
+	rel, ok := stmt.Schema.Relationships.Relations[relation]
+	if !ok {
+		return fmt.Errorf("%s has no relation %s", stmt.Schema.Name, relation)
+	}
+	if rel.JoinTable != nil || rel.Polymorphic != nil || len(rel.References) != 1 {
+		return fmt.Errorf("relation %s.%s can't be batch loaded, use Preload", stmt.Schema.Name, relation)
+	}
+
+	// parentKey is the field on the models, relatedKey the matching field on
+	// the related rows
+	ref := rel.References[0]
+	parentKey, relatedKey := ref.ForeignKey, ref.PrimaryKey
+	if ref.OwnPrimaryKey {
+		parentKey, relatedKey = ref.PrimaryKey, ref.ForeignKey
+	}
+
+	ctx := context.Background()
+	keys := make([]interface{}, 0, len(models))
+	seen := make(map[string]struct{}, len(models))
+	for _, model := range models {
+		key, zero := parentKey.ValueOf(ctx, reflect.ValueOf(model))
+		if zero {
+			continue
+		}
+		if _, exists := seen[relationKey(key)]; !exists {
+			seen[relationKey(key)] = struct{}{}
+			keys = append(keys, relationValue(key))
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	related := reflect.New(reflect.SliceOf(reflect.PointerTo(rel.FieldSchema.ModelType)))
+	if err := db.Where(fmt.Sprintf("%s IN ?", relatedKey.DBName), keys).Find(related.Interface()).Error; err != nil {
+		return err
+	}
+
+	// Group related rows by the key that ties them to their parent
+	byKey := make(map[string][]reflect.Value)
+	rows := related.Elem()
+	for i := 0; i < rows.Len(); i++ {
+		key, _ := relatedKey.ValueOf(ctx, rows.Index(i))
+		byKey[relationKey(key)] = append(byKey[relationKey(key)], rows.Index(i))
+	}
+
+	for _, model := range models {
+		key, zero := parentKey.ValueOf(ctx, reflect.ValueOf(model))
+		if zero {
+			continue
+		}
+		assignRelation(rel, rel.Field.ReflectValueOf(ctx, reflect.ValueOf(model)), byKey[relationKey(key)])
+	}
+	return nil
+}
+
+// assignRelation sets a relation field from its loaded rows, which are
+// pointers to the related model
+func assignRelation(rel *schema.Relationship, field reflect.Value, rows []reflect.Value) {
+	if rel.Type == schema.HasMany {
+		values := reflect.MakeSlice(field.Type(), 0, len(rows))
+		pointers := field.Type().Elem().Kind() == reflect.Ptr
+		for _, row := range rows {
+			if pointers {
+				values = reflect.Append(values, row)
+			} else {
+				values = reflect.Append(values, row.Elem())
+			}
+		}
+		field.Set(values)
+		return
+	}
+
+	if len(rows) == 0 {
+		field.Set(reflect.Zero(field.Type()))
+		return
+	}
+	if field.Kind() == reflect.Ptr {
+		field.Set(rows[0])
+	} else {
+		field.Set(rows[0].Elem())
+	}
+}
+
+// relationValue dereferences a key so nullable foreign keys can be compared
+func relationValue(key interface{}) interface{} {
+	value := reflect.ValueOf(key)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	return value.Interface()
+}
+
+// relationKey normalizes a key so e.g. uint and *uint values group together
+func relationKey(key interface{}) string {
+	return fmt.Sprint(relationValue(key))
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type eagerAuthor struct {
+	ID    uint `gorm:"primaryKey"`
+	Name  string
+	Posts []*eagerPost `gorm:"foreignKey:AuthorID"`
+}
+
+type eagerPost struct {
+	ID       uint `gorm:"primaryKey"`
+	AuthorID uint
+	Title    string
+	Author   *eagerAuthor `gorm:"foreignKey:AuthorID"`
+}
+
+func postTitles(posts []*eagerPost) []string {
+	titles := make([]string, len(posts))
+	for i, post := range posts {
+		titles[i] = post.Title
+	}
+	return titles
+}
+
+func TestLoadRelationHasManyInOneQuery(t *testing.T) {
+	recorder := &queryRecorder{
+		columns: []string{"id", "author_id", "title"},
+		rows: [][]driver.Value{
+			{int64(10), int64(1), "first"},
+			{int64(11), int64(3), "third"},
+			{int64(12), int64(1), "second"},
+		},
+	}
+	db := openRecorderDB(t, recorder)
+	authors := []*eagerAuthor{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 1}}
+
+	if err := LoadRelation(db, authors, "Posts"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recorder.queries) != 1 {
+		t.Fatalf("expected a single query, got %q", recorder.queries)
+	}
+	query, args, _ := recorder.last()
+	if want := "SELECT * FROM `eager_posts` WHERE author_id IN (?,?,?)"; query != want {
+		t.Fatalf("expected %q, got %q", want, query)
+	}
+	if len(args) != 3 {
+		t.Fatalf("expected each parent key once, got %v", args)
+	}
+
+	want := [][]string{{"first", "second"}, {}, {"third"}, {"first", "second"}}
+	for i, author := range authors {
+		if got := postTitles(author.Posts); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("author %d: expected posts %v, got %v", i, want[i], got)
+		}
+	}
+}
+
+func TestLoadRelationBelongsToInOneQuery(t *testing.T) {
+	recorder := &queryRecorder{
+		columns: []string{"id", "name"},
+		rows:    [][]driver.Value{{int64(1), "ann"}, {int64(2), "bob"}},
+	}
+	db := openRecorderDB(t, recorder)
+	posts := []*eagerPost{{ID: 10, AuthorID: 1}, {ID: 11, AuthorID: 2}, {ID: 12, AuthorID: 1}, {ID: 13}}
+
+	if err := LoadRelation(db, posts, "Author"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recorder.queries) != 1 || !strings.HasSuffix(recorder.queries[0], "WHERE id IN (?,?)") {
+		t.Fatalf("expected one query for both authors, got %q", recorder.queries)
+	}
+	names := make([]string, len(posts))
+	for i, post := range posts {
+		if post.Author != nil {
+			names[i] = post.Author.Name
+		}
+	}
+	if want := []string{"ann", "bob", "ann", ""}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("expected authors %v, got %v", want, names)
+	}
+}
+
+func TestLoadRelationSkipsQueryWithoutKeys(t *testing.T) {
+	recorder := &queryRecorder{}
+	db := openRecorderDB(t, recorder)
+
+	if err := LoadRelation(db, []*eagerAuthor{}, "Posts"); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadRelation(db, []*eagerPost{{ID: 10}}, "Author"); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.queries) != 0 {
+		t.Fatalf("expected no queries, got %q", recorder.queries)
+	}
+
+	if err := LoadRelation(db, []*eagerAuthor{{ID: 1}}, "Comments"); err == nil {
+		t.Fatal("expected an unknown relation to be rejected")
+	}
+}
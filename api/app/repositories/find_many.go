@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// FindMany loads the models with the given IDs in a single query and returns
+// them in the order of ids. IDs with no matching row are skipped.
+func FindMany[T any](db *gorm.DB, ids []uint) ([]*T, error) {
+	if len(ids) == 0 {
+		return []*T{}, nil
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
+	}
+	primaryKey := stmt.Schema.PrioritizedPrimaryField
+	if primaryKey == nil {
+		return nil, fmt.Errorf("%s has no primary key", stmt.Schema.Name)
+	}
+
+	var rows []*T
+	if err := db.Where(fmt.Sprintf("%s IN ?", primaryKey.DBName), ids).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	byID := make(map[string]*T, len(rows))
+	for _, row := range rows {
+		key, _ := primaryKey.ValueOf(ctx, reflect.ValueOf(row))
+		byID[relationKey(key)] = row
+	}
+
+	models := make([]*T, 0, len(rows))
+	for _, id := range ids {
+		if row, exists := byID[relationKey(id)]; exists {
+			models = append(models, row)
+		}
+	}
+	return models, nil
+}
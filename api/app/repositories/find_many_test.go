@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestFindManyLoadsInOneQueryInRequestedOrder(t *testing.T) {
+	// The database returns rows in its own order
+	recorder := &queryRecorder{rows: [][]driver.Value{
+		{int64(2), "two"},
+		{int64(5), "five"},
+		{int64(9), "nine"},
+	}}
+	db := openRecorderDB(t, recorder)
+
+	docs, err := FindMany[pagedDoc](db, []uint{9, 4, 2, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recorder.queries) != 1 {
+		t.Fatalf("expected a single query, got %q", recorder.queries)
+	}
+	query, args, _ := recorder.last()
+	if want := "SELECT * FROM `paged_docs` WHERE id IN (?,?,?,?)"; query != want {
+		t.Fatalf("expected %q, got %q", want, query)
+	}
+	if len(args) != 4 {
+		t.Fatalf("expected every id as an argument, got %v", args)
+	}
+
+	// Missing ids are skipped and the rest follow the order asked for
+	titles := make([]string, len(docs))
+	for i, doc := range docs {
+		titles[i] = doc.Title
+	}
+	if want := []string{"nine", "two", "five"}; !reflect.DeepEqual(titles, want) {
+		t.Fatalf("expected %v, got %v", want, titles)
+	}
+}
+
+func TestFindManyWithoutIDsSkipsQuery(t *testing.T) {
+	recorder := &queryRecorder{}
+	docs, err := FindMany[pagedDoc](openRecorderDB(t, recorder), nil)
+	if err != nil || docs == nil || len(docs) != 0 {
+		t.Fatalf("expected an empty result, got %v, %v", docs, err)
+	}
+	if len(recorder.queries) != 0 {
+		t.Fatalf("expected no query, got %q", recorder.queries)
+	}
+}
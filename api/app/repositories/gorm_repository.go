@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"base_lara_go_project/app/core"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// primaryKeyColumn orders and filters by the model's primary key
+var primaryKeyColumn = clause.Column{Table: clause.CurrentTable, Name: clause.PrimaryKey}
+
+// GormRepository implements core.RepositoryInterface for *T over gorm. It
+// counts and pages in the database and binds its queries to a context, so
+// services built on it never load a whole table to cut a page out of it.
+type GormRepository[T any] struct {
+	db    *gorm.DB
+	query *gorm.DB
+}
+
+// NewGormRepository creates a repository for *T, which must implement core.ModelInterface
+func NewGormRepository[T any](db *gorm.DB) *GormRepository[T] {
+	return &GormRepository[T]{db: db, query: db.Model(new(T)).Session(&gorm.Session{})}
+}
+
+// WithContext returns a copy of the repository whose queries run with ctx
+func (r *GormRepository[T]) WithContext(ctx context.Context) core.RepositoryInterface {
+	return &GormRepository[T]{db: r.db.WithContext(ctx), query: r.query.WithContext(ctx)}
+}
+
+// Find finds a model by ID
+func (r *GormRepository[T]) Find(id uint) (core.ModelInterface, error) {
+	row := new(T)
+	if err := r.query.First(row, id).Error; err != nil {
+		return nil, err
+	}
+	return toModel(row)
+}
+
+// FindBy finds the first model whose field equals value
+func (r *GormRepository[T]) FindBy(field string, value interface{}) (core.ModelInterface, error) {
+	row := new(T)
+	if err := r.query.Where(clause.Eq{Column: clause.Column{Name: field}, Value: value}).First(row).Error; err != nil {
+		return nil, err
+	}
+	return toModel(row)
+}
+
+// Create inserts a model
+func (r *GormRepository[T]) Create(model core.ModelInterface) error {
+	return r.db.Create(model).Error
+}
+
+// Update saves every field of a model
+func (r *GormRepository[T]) Update(model core.ModelInterface) error {
+	return r.db.Save(model).Error
+}
+
+// Delete deletes a model
+func (r *GormRepository[T]) Delete(model core.ModelInterface) error {
+	return r.db.Delete(model).Error
+}
+
+// All gets every model, ignoring any conditions
+func (r *GormRepository[T]) All() ([]core.ModelInterface, error) {
+	var rows []*T
+	if err := r.db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return toModels(rows)
+}
+
+// Where returns a copy of the repository with a condition added to its query
+func (r *GormRepository[T]) Where(query interface{}, args ...interface{}) core.RepositoryInterface {
+	return &GormRepository[T]{db: r.db, query: r.query.Where(query, args...).Session(&gorm.Session{})}
+}
+
+// First gets the first model matching the query
+func (r *GormRepository[T]) First() (core.ModelInterface, error) {
+	row := new(T)
+	if err := r.query.First(row).Error; err != nil {
+		return nil, err
+	}
+	return toModel(row)
+}
+
+// Get gets every model matching the query
+func (r *GormRepository[T]) Get() ([]core.ModelInterface, error) {
+	var rows []*T
+	if err := r.query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return toModels(rows)
+}
+
+// Count counts the models matching the query
+func (r *GormRepository[T]) Count() (int64, error) {
+	var count int64
+	err := r.query.Count(&count).Error
+	return count, err
+}
+
+// Page gets up to limit models matching the query, ordered by ID, skipping offset
+func (r *GormRepository[T]) Page(offset, limit int) ([]core.ModelInterface, error) {
+	var rows []*T
+	err := r.query.Order(clause.OrderByColumn{Column: primaryKeyColumn}).Offset(offset).Limit(limit).Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return toModels(rows)
+}
+
+// After gets up to limit models matching the query with an ID above afterID, ordered by ID
+func (r *GormRepository[T]) After(afterID uint, limit int) ([]core.ModelInterface, error) {
+	var rows []*T
+	err := r.query.Where(clause.Gt{Column: primaryKeyColumn, Value: afterID}).
+		Order(clause.OrderByColumn{Column: primaryKeyColumn}).Limit(limit).Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return toModels(rows)
+}
+
+// toModel converts a loaded row to a core.ModelInterface
+func toModel[T any](row *T) (core.ModelInterface, error) {
+	model, ok := any(row).(core.ModelInterface)
+	if !ok {
+		return nil, fmt.Errorf("%T does not implement ModelInterface", row)
+	}
+	return model, nil
+}
+
+// toModels converts loaded rows to core.ModelInterface values
+func toModels[T any](rows []*T) ([]core.ModelInterface, error) {
+	models := make([]core.ModelInterface, 0, len(rows))
+	for _, row := range rows {
+		model, err := toModel(row)
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
+
+// Ensure GormRepository supports paging and contexts
+var (
+	_ core.PagingRepository  = (*GormRepository[struct{}])(nil)
+	_ core.ContextRepository = (*GormRepository[struct{}])(nil)
+)
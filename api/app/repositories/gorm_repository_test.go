@@ -0,0 +1,211 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// pagedDoc is a model served through a GormRepository
+type pagedDoc struct {
+	ID    uint `gorm:"primaryKey"`
+	Title string
+}
+
+func (d *pagedDoc) GetID() uint              { return d.ID }
+func (d *pagedDoc) GetTableName() string     { return "paged_docs" }
+func (d *pagedDoc) GetCreatedAt() time.Time  { return time.Time{} }
+func (d *pagedDoc) GetUpdatedAt() time.Time  { return time.Time{} }
+func (d *pagedDoc) GetDeletedAt() *time.Time { return nil }
+
+type ctxKey struct{}
+
+// queryRecorder is a fake database recording each query and the context it
+// ran with. COUNT queries return count; other selects return rows, named by
+// columns or id and title when columns is unset.
+type queryRecorder struct {
+	mutex    sync.Mutex
+	queries  []string
+	args     [][]driver.NamedValue
+	contexts []context.Context
+	count    int64
+	columns  []string
+	rows     [][]driver.Value
+}
+
+func (r *queryRecorder) record(ctx context.Context, query string, args []driver.NamedValue) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.queries = append(r.queries, query)
+	r.args = append(r.args, args)
+	r.contexts = append(r.contexts, ctx)
+}
+
+func (r *queryRecorder) last() (string, []driver.NamedValue, context.Context) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	n := len(r.queries) - 1
+	return r.queries[n], r.args[n], r.contexts[n]
+}
+
+type recordingConn struct{ recorder *queryRecorder }
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *recordingConn) Close() error                        { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error)           { return c, nil }
+func (c *recordingConn) Commit() error                       { return nil }
+func (c *recordingConn) Rollback() error                     { return nil }
+
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.recorder.record(ctx, query, args)
+	return insertResult(1), nil
+}
+
+func (c *recordingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.recorder.record(ctx, query, args)
+	if strings.HasPrefix(query, "SELECT count(*)") {
+		return &valueRows{columns: []string{"count(*)"}, values: [][]driver.Value{{c.recorder.count}}}, nil
+	}
+	columns := c.recorder.columns
+	if columns == nil {
+		columns = []string{"id", "title"}
+	}
+	return &valueRows{columns: columns, values: c.recorder.rows}, nil
+}
+
+type valueRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *valueRows) Columns() []string { return r.columns }
+func (r *valueRows) Close() error      { return nil }
+
+func (r *valueRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+type recordingConnector struct{ recorder *queryRecorder }
+
+func (c recordingConnector) Connect(context.Context) (driver.Conn, error) {
+	return &recordingConn{recorder: c.recorder}, nil
+}
+func (c recordingConnector) Driver() driver.Driver { return nil }
+
+func openRecorderDB(t *testing.T, recorder *queryRecorder) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sql.OpenDB(recordingConnector{recorder: recorder}),
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func openRecorder(t *testing.T, recorder *queryRecorder) *GormRepository[pagedDoc] {
+	t.Helper()
+	return NewGormRepository[pagedDoc](openRecorderDB(t, recorder))
+}
+
+func TestGormRepositoryCountsInDatabase(t *testing.T) {
+	recorder := &queryRecorder{count: 42}
+	repository := openRecorder(t, recorder)
+
+	count, err := repository.Where(map[string]interface{}{"title": "draft"}).(*GormRepository[pagedDoc]).Count()
+	if err != nil || count != 42 {
+		t.Fatalf("expected the database count, got %d, %v", count, err)
+	}
+	query, _, _ := recorder.last()
+	if want := "SELECT count(*) FROM `paged_docs` WHERE `paged_docs`.`title` = ?"; query != want {
+		t.Fatalf("expected %q, got %q", want, query)
+	}
+}
+
+func TestGormRepositoryPagesInDatabase(t *testing.T) {
+	recorder := &queryRecorder{rows: [][]driver.Value{{int64(5), "e"}, {int64(6), "f"}}}
+	repository := openRecorder(t, recorder)
+
+	models, err := repository.Page(4, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(models) != 2 || models[0].GetID() != 5 || models[1].GetID() != 6 {
+		t.Fatalf("expected rows 5 and 6, got %v", models)
+	}
+	query, args, _ := recorder.last()
+	if want := "SELECT * FROM `paged_docs` ORDER BY `paged_docs`.`id` LIMIT ? OFFSET ?"; query != want {
+		t.Fatalf("expected %q, got %q", want, query)
+	}
+	if len(args) != 2 || args[0].Value != int64(2) || args[1].Value != int64(4) {
+		t.Fatalf("expected limit 2 and offset 4, got %v", args)
+	}
+}
+
+func TestGormRepositoryPagesAfterCursorInDatabase(t *testing.T) {
+	recorder := &queryRecorder{}
+	repository := openRecorder(t, recorder)
+
+	if _, err := repository.After(10, 3); err != nil {
+		t.Fatal(err)
+	}
+	query, args, _ := recorder.last()
+	if want := "SELECT * FROM `paged_docs` WHERE `paged_docs`.`id` > ? ORDER BY `paged_docs`.`id` LIMIT ?"; query != want {
+		t.Fatalf("expected %q, got %q", want, query)
+	}
+	if len(args) != 2 || args[0].Value != int64(10) || args[1].Value != int64(3) {
+		t.Fatalf("expected cursor 10 and limit 3, got %v", args)
+	}
+}
+
+func TestGormRepositoryRunsQueriesWithContext(t *testing.T) {
+	recorder := &queryRecorder{}
+	repository := openRecorder(t, recorder)
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	scoped := repository.WithContext(ctx)
+
+	if err := scoped.Create(&pagedDoc{Title: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, queryCtx := recorder.last(); queryCtx.Value(ctxKey{}) != "request" {
+		t.Fatal("expected the insert to run with the bound context")
+	}
+
+	if _, err := scoped.Where(map[string]interface{}{"title": "new"}).Get(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, queryCtx := recorder.last(); queryCtx.Value(ctxKey{}) != "request" {
+		t.Fatal("expected a scoped query to keep the bound context")
+	}
+}
+
+func TestGormRepositoryWhereDoesNotLeakIntoOtherQueries(t *testing.T) {
+	recorder := &queryRecorder{}
+	repository := openRecorder(t, recorder)
+
+	repository.Where(map[string]interface{}{"title": "draft"}).Get()
+	if _, err := repository.Get(); err != nil {
+		t.Fatal(err)
+	}
+	query, _, _ := recorder.last()
+	if query != "SELECT * FROM `paged_docs`" {
+		t.Fatalf("expected an unscoped query, got %q", query)
+	}
+}
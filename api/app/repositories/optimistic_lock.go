@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"base_lara_go_project/app/core"
+
+	"gorm.io/gorm"
+)
+
+// SaveVersioned saves every field of model. Models that implement
+// core.Versioned are only written if their version is unchanged since they
+// were read, and their version is bumped; otherwise core.ErrStaleObject is
+// returned and the model is left as it was. Like Save, a model without a
+// primary key yet is created instead.
+func SaveVersioned[T any](db *gorm.DB, model *T) error {
+	versioned, ok := any(model).(core.Versioned)
+	if !ok {
+		return db.Save(model).Error
+	}
+
+	// Without a primary key condition the versioned UPDATE below would hit
+	// every row at this version
+	isNew, err := hasZeroPrimaryKey(db, model)
+	if err != nil {
+		return err
+	}
+	if isNew {
+		return db.Create(model).Error
+	}
+
+	current := versioned.GetVersion()
+	versioned.SetVersion(current + 1)
+
+	result := db.Model(model).Where("version = ?", current).Select("*").Updates(model)
+	if result.Error != nil {
+		versioned.SetVersion(current)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		versioned.SetVersion(current)
+		return core.ErrStaleObject
+	}
+	return nil
+}
+
+// hasZeroPrimaryKey reports whether any primary key field of model is unset
+func hasZeroPrimaryKey(db *gorm.DB, model interface{}) (bool, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return false, err
+	}
+	if len(stmt.Schema.PrimaryFields) == 0 {
+		return false, fmt.Errorf("optimistic locking needs a primary key on %s", stmt.Schema.Name)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(model))
+	for _, field := range stmt.Schema.PrimaryFields {
+		if _, isZero := field.ValueOf(context.Background(), value); isZero {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"base_lara_go_project/app/core"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// versionedDoc is a model opted into optimistic locking
+type versionedDoc struct {
+	ID    uint `gorm:"primaryKey"`
+	Title string
+	core.Versioning
+}
+
+// versionStore is a fake database holding the version of each row. A
+// versioned UPDATE only affects the row when the version it names matches.
+type versionStore struct {
+	mutex    sync.Mutex
+	versions map[int64]int64
+	nextID   int64
+	queries  []string
+}
+
+func (s *versionStore) exec(query string, args []driver.NamedValue) (driver.Result, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.queries = append(s.queries, query)
+
+	switch {
+	case strings.HasPrefix(query, "UPDATE"):
+		// SaveVersioned's conditions come last: version = ? AND id = ?
+		if !strings.Contains(query, "WHERE version = ? AND `id` = ?") {
+			return nil, errors.New("unexpected update: " + query)
+		}
+		version := args[len(args)-2].Value.(int64)
+		id := args[len(args)-1].Value.(int64)
+		if current, ok := s.versions[id]; !ok || current != version {
+			return driver.RowsAffected(0), nil
+		}
+		s.versions[id] = version + 1
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(query, "INSERT"):
+		s.nextID++
+		s.versions[s.nextID] = 1
+		return insertResult(s.nextID), nil
+	}
+	return nil, errors.New("unexpected statement: " + query)
+}
+
+type insertResult int64
+
+func (r insertResult) LastInsertId() (int64, error) { return int64(r), nil }
+func (r insertResult) RowsAffected() (int64, error) { return 1, nil }
+
+// fakeConn is a database/sql connection backed by a versionStore
+type fakeConn struct{ store *versionStore }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return c, nil }
+func (c *fakeConn) Commit() error                       { return nil }
+func (c *fakeConn) Rollback() error                     { return nil }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.store.exec(query, args)
+}
+
+func (c *fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return emptyRows{}, nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return nil }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+type fakeConnector struct{ store *versionStore }
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{store: c.store}, nil
+}
+func (c fakeConnector) Driver() driver.Driver { return nil }
+
+func openVersionStore(t *testing.T, versions map[int64]int64) (*gorm.DB, *versionStore) {
+	t.Helper()
+	store := &versionStore{versions: versions}
+	for id := range versions {
+		if id > store.nextID {
+			store.nextID = id
+		}
+	}
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sql.OpenDB(fakeConnector{store: store}),
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, store
+}
+
+func TestSaveVersionedBumpsVersion(t *testing.T) {
+	db, store := openVersionStore(t, map[int64]int64{1: 3})
+
+	doc := &versionedDoc{ID: 1, Title: "draft", Versioning: core.Versioning{Version: 3}}
+	if err := SaveVersioned(db, doc); err != nil {
+		t.Fatalf("expected save to succeed, got %v", err)
+	}
+	if doc.Version != 4 || store.versions[1] != 4 {
+		t.Fatalf("expected version 4 in model and store, got %d and %d", doc.Version, store.versions[1])
+	}
+}
+
+func TestSaveVersionedRejectsConcurrentUpdate(t *testing.T) {
+	db, store := openVersionStore(t, map[int64]int64{1: 1})
+
+	// Two requests read the same row at version 1
+	first := &versionedDoc{ID: 1, Title: "first", Versioning: core.Versioning{Version: 1}}
+	second := &versionedDoc{ID: 1, Title: "second", Versioning: core.Versioning{Version: 1}}
+
+	if err := SaveVersioned(db, first); err != nil {
+		t.Fatalf("expected the first update to succeed, got %v", err)
+	}
+	err := SaveVersioned(db, second)
+	if !errors.Is(err, core.ErrStaleObject) {
+		t.Fatalf("expected ErrStaleObject for the second update, got %v", err)
+	}
+	if second.Version != 1 {
+		t.Fatalf("expected the stale model to keep version 1, got %d", second.Version)
+	}
+	if store.versions[1] != 2 {
+		t.Fatalf("expected the stored version to be 2, got %d", store.versions[1])
+	}
+}
+
+func TestSaveVersionedCreatesModelWithoutPrimaryKey(t *testing.T) {
+	db, store := openVersionStore(t, map[int64]int64{1: 1, 2: 1})
+
+	doc := &versionedDoc{Title: "new", Versioning: core.Versioning{Version: 1}}
+	if err := SaveVersioned(db, doc); err != nil {
+		t.Fatalf("expected create to succeed, got %v", err)
+	}
+
+	for _, query := range store.queries {
+		if strings.HasPrefix(query, "UPDATE") {
+			t.Fatalf("a model without a primary key must not be updated: %s", query)
+		}
+	}
+	if doc.ID != 3 {
+		t.Fatalf("expected the created model to get ID 3, got %d", doc.ID)
+	}
+}
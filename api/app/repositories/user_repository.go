@@ -233,6 +233,44 @@ func (r *UserRepository) Paginate(page, perPage int) ([]interfaces.UserInterface
 	return users, total, nil
 }
 
+// PaginateCursor gets up to limit users with an ID after the cursor, ordered by
+// ID. The returned cursor is empty once the last page has been reached. Unlike
+// offset pagination, rows inserted mid-iteration never shift later pages.
+func (r *UserRepository) PaginateCursor(cursor string, limit int) ([]interfaces.UserInterface, string, error) {
+	afterID, err := core.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if limit <= 0 {
+		return nil, "", fmt.Errorf("cursor page limit must be positive, got %d", limit)
+	}
+
+	// Fetch one extra row to know whether another page follows
+	var dbUsers []db.User
+	err = r.db.Preload("Roles.Permissions").
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit + 1).
+		Find(&dbUsers).Error
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(dbUsers) > limit {
+		dbUsers = dbUsers[:limit]
+		nextCursor = core.EncodeCursor(dbUsers[len(dbUsers)-1].ID)
+	}
+
+	var users []interfaces.UserInterface
+	for _, dbUser := range dbUsers {
+		cacheUser := r.convertDBToCache(&dbUser)
+		users = append(users, cacheUser)
+	}
+
+	return users, nextCursor, nil
+}
+
 // UpdateOrCreate updates or creates a user
 func (r *UserRepository) UpdateOrCreate(conditions map[string]interface{}, data map[string]interface{}) (interfaces.UserInterface, error) {
 	dbUser := &db.User{}
@@ -75,6 +75,16 @@ func (s *UserService) PaginateWithContext(ctx context.Context, page, perPage int
 	return s.userRepo.Paginate(page, perPage) // Repository doesn't support context yet
 }
 
+// PaginateCursor gets the users after a cursor along with the next cursor
+func (s *UserService) PaginateCursor(cursor string, limit int) ([]interfaces.UserInterface, string, error) {
+	return s.userRepo.PaginateCursor(cursor, limit)
+}
+
+// PaginateCursorWithContext gets the users after a cursor with context
+func (s *UserService) PaginateCursorWithContext(ctx context.Context, cursor string, limit int) ([]interfaces.UserInterface, string, error) {
+	return s.userRepo.PaginateCursor(cursor, limit) // Repository doesn't support context yet
+}
+
 // Update updates a user
 func (s *UserService) Update(id uint, data map[string]interface{}) (interfaces.UserInterface, error) {
 	return s.userRepo.Update(id, data)
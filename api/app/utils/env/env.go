@@ -0,0 +1,52 @@
+package env
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Env returns the environment variable key, or def when it is unset or empty
+func Env(key string, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// EnvBool returns the environment variable key as a bool. 1, true, yes and on
+// are true and 0, false, no and off are false, in any case; anything else,
+// including an unset variable, returns def.
+func EnvBool(key string, def bool) bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(key))) {
+	case "1", "true", "yes", "on":
+		return true
+	case "0", "false", "no", "off":
+		return false
+	}
+	return def
+}
+
+// EnvInt returns the environment variable key as an int, or def when it is
+// unset or not an integer
+func EnvInt(key string, def int) int {
+	if value, err := strconv.Atoi(strings.TrimSpace(os.Getenv(key))); err == nil {
+		return value
+	}
+	return def
+}
+
+// EnvDuration returns the environment variable key as a duration, or def when
+// it is unset or unparseable. Values such as "30s" are parsed as durations and
+// bare integers are treated as seconds, as config durations are.
+func EnvDuration(key string, def time.Duration) time.Duration {
+	value := strings.TrimSpace(os.Getenv(key))
+	if d, err := time.ParseDuration(value); err == nil {
+		return d
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	return def
+}
@@ -0,0 +1,111 @@
+package env
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+const key = "ENV_HELPER_TEST"
+
+// setEnv sets the test variable, or leaves it unset when value is nil
+func setEnv(t *testing.T, value *string) {
+	t.Helper()
+	if value != nil {
+		t.Setenv(key, *value)
+		return
+	}
+	// Setenv restores the variable after the test; Unsetenv then removes it
+	t.Setenv(key, "")
+	os.Unsetenv(key)
+}
+
+func str(s string) *string { return &s }
+
+func TestEnv(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		value *string
+		want  string
+	}{
+		{"unset", nil, "default"},
+		{"empty", str(""), "default"},
+		{"set", str("value"), "value"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			setEnv(t, tc.value)
+			if got := Env(key, "default"); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestEnvBool(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		value *string
+		def   bool
+		want  bool
+	}{
+		{"unset", nil, true, true},
+		{"true", str("true"), false, true},
+		{"one", str("1"), false, true},
+		{"yes any case", str(" YES "), false, true},
+		{"on", str("on"), false, true},
+		{"false", str("false"), true, false},
+		{"zero", str("0"), true, false},
+		{"no", str("no"), true, false},
+		{"off", str("Off"), true, false},
+		{"bad value", str("maybe"), true, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			setEnv(t, tc.value)
+			if got := EnvBool(key, tc.def); got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestEnvInt(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		value *string
+		want  int
+	}{
+		{"unset", nil, 7},
+		{"set", str("42"), 42},
+		{"negative", str("-3"), -3},
+		{"padded", str(" 5 "), 5},
+		{"bad value", str("forty"), 7},
+		{"float", str("1.5"), 7},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			setEnv(t, tc.value)
+			if got := EnvInt(key, 7); got != tc.want {
+				t.Fatalf("expected %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestEnvDuration(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		value *string
+		want  time.Duration
+	}{
+		{"unset", nil, time.Minute},
+		{"duration", str("1500ms"), 1500 * time.Millisecond},
+		{"bare seconds", str("30"), 30 * time.Second},
+		{"bad value", str("soon"), time.Minute},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			setEnv(t, tc.value)
+			if got := EnvDuration(key, time.Minute); got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package token
 
 import (
 	"base_lara_go_project/config"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -11,6 +12,11 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// ErrMissingToken is returned when a request carries no token
+var ErrMissingToken = errors.New("missing token")
+
+// GenerateToken issues a signed token for a user that expires after the
+// configured token_hour_lifespan
 func GenerateToken(userId uint, role string) (string, error) {
 	appConfig := config.AppConfig()
 	tokenLifespan, err := strconv.Atoi(appConfig["token_hour_lifespan"].(string))
@@ -28,75 +34,73 @@ func GenerateToken(userId uint, role string) (string, error) {
 	return token.SignedString([]byte(appConfig["secret"].(string)))
 }
 
-func IsTokenValid(c *gin.Context) error {
-	tokenString := ExtractToken(c)
+// ParseToken verifies a token's HS256 signature and expiry and returns its claims
+func ParseToken(tokenString string) (jwt.MapClaims, error) {
+	if tokenString == "" {
+		return nil, ErrMissingToken
+	}
+
 	appConfig := config.AppConfig()
-	_, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
-		}
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		return []byte(appConfig["secret"].(string)), nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return claims, nil
 }
 
-func ExtractToken(c *gin.Context) string {
-	token := c.Query("token")
-	if token != "" {
-		return token
+// UserIDFromClaims reads the user ID out of parsed claims
+func UserIDFromClaims(claims jwt.MapClaims) (uint, error) {
+	userID, ok := claims["user_id"].(float64)
+	if !ok || userID < 0 {
+		return 0, fmt.Errorf("user_id claim missing or invalid")
+	}
+	uid, err := strconv.ParseUint(fmt.Sprintf("%.0f", userID), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint(uid), nil
+}
+
+// RoleFromClaims reads the role out of parsed claims
+func RoleFromClaims(claims jwt.MapClaims) (string, error) {
+	role, ok := claims["role"].(string)
+	if !ok {
+		return "", fmt.Errorf("role claim missing or invalid")
 	}
-	bearerToken := c.Request.Header.Get("Authorization")
-	if len(strings.Split(bearerToken, " ")) == 2 {
-		return strings.Split(bearerToken, " ")[1]
+	return role, nil
+}
+
+func IsTokenValid(c *gin.Context) error {
+	_, err := ParseToken(ExtractToken(c))
+	return err
+}
+
+// ExtractToken reads the bearer token from the Authorization header. Tokens
+// are never read from the query string, where they would leak into access
+// logs and Referer headers.
+func ExtractToken(c *gin.Context) string {
+	scheme, bearerToken, found := strings.Cut(c.Request.Header.Get("Authorization"), " ")
+	if found && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(bearerToken)
 	}
 	return ""
 }
 
 func ExtractTokenID(c *gin.Context) (uint, error) {
-	tokenString := ExtractToken(c)
-	appConfig := config.AppConfig()
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(appConfig["secret"].(string)), nil
-	})
+	claims, err := ParseToken(ExtractToken(c))
 	if err != nil {
 		return 0, err
 	}
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if ok && token.Valid {
-		uid, err := strconv.ParseUint(fmt.Sprintf("%.0f", claims["user_id"]), 10, 32)
-		if err != nil {
-			return 0, err
-		}
-		return uint(uid), nil
-	}
-	return 0, nil
+	return UserIDFromClaims(claims)
 }
 
 func ExtractTokenRole(c *gin.Context) (string, error) {
-	tokenString := ExtractToken(c)
-	appConfig := config.AppConfig()
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(appConfig["secret"].(string)), nil
-	})
+	claims, err := ParseToken(ExtractToken(c))
 	if err != nil {
 		return "", err
 	}
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if ok && token.Valid {
-		role, ok := claims["role"].(string)
-		if !ok {
-			return "", fmt.Errorf("role claim missing or invalid")
-		}
-		return role, nil
-	}
-	return "", fmt.Errorf("invalid token")
+	return RoleFromClaims(claims)
 }
@@ -0,0 +1,106 @@
+package token
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret"
+
+func useSecret(t *testing.T) {
+	t.Helper()
+	t.Setenv("API_SECRET", testSecret)
+	t.Setenv("TOKEN_HOUR_LIFESPAN", "1")
+}
+
+// signed signs claims with method and secret
+func signed(t *testing.T, method jwt.SigningMethod, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+	tokenString, err := jwt.NewWithClaims(method, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokenString
+}
+
+func TestParseToken(t *testing.T) {
+	useSecret(t)
+	valid, err := GenerateToken(42, "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(valid, ".")
+	forged := signed(t, jwt.SigningMethodHS256, testSecret, jwt.MapClaims{"user_id": 1, "role": "admin", "exp": time.Now().Add(time.Hour).Unix()})
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"valid", valid, true},
+		{"missing", "", false},
+		{"expired", signed(t, jwt.SigningMethodHS256, testSecret, jwt.MapClaims{"user_id": 42, "exp": time.Now().Add(-time.Minute).Unix()}), false},
+		{"without expiry", signed(t, jwt.SigningMethodHS256, testSecret, jwt.MapClaims{"user_id": 42}), false},
+		{"tampered payload", parts[0] + "." + strings.Split(forged, ".")[1] + "." + parts[2], false},
+		{"wrong secret", signed(t, jwt.SigningMethodHS256, "other-secret", jwt.MapClaims{"user_id": 42, "exp": time.Now().Add(time.Hour).Unix()}), false},
+		{"wrong algorithm", signed(t, jwt.SigningMethodHS512, testSecret, jwt.MapClaims{"user_id": 42, "exp": time.Now().Add(time.Hour).Unix()}), false},
+		{"garbage", "not.a.token", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claims, err := ParseToken(test.token)
+			if test.valid != (err == nil) {
+				t.Fatalf("expected valid=%v, got %v", test.valid, err)
+			}
+			if test.valid {
+				if id, err := UserIDFromClaims(claims); err != nil || id != 42 {
+					t.Fatalf("expected user 42, got %d, %v", id, err)
+				}
+				if role, err := RoleFromClaims(claims); err != nil || role != "admin" {
+					t.Fatalf("expected role admin, got %q, %v", role, err)
+				}
+			}
+		})
+	}
+
+	if _, err := ParseToken(""); !errors.Is(err, ErrMissingToken) {
+		t.Fatalf("expected ErrMissingToken, got %v", err)
+	}
+}
+
+func TestExtractTokenReadsOnlyTheBearerHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name   string
+		target string
+		header string
+		want   string
+	}{
+		{"bearer header", "/", "Bearer abc", "abc"},
+		{"lowercase scheme", "/", "bearer abc", "abc"},
+		{"other scheme", "/", "Basic abc", ""},
+		{"query string", "/?token=abc", "", ""},
+		{"none", "/", "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, test.target, nil)
+			if test.header != "" {
+				c.Request.Header.Set("Authorization", test.header)
+			}
+			if got := ExtractToken(c); got != test.want {
+				t.Fatalf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
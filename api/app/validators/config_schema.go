@@ -0,0 +1,185 @@
+package validators
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"base_lara_go_project/app/core"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Config value types checked by ConfigRule. Values loaded from the
+// environment are strings, so numeric, bool and duration types accept any
+// string that parses as one. Numbers decoded from JSON config files arrive as
+// float64 or json.Number, so int accepts those when they are whole.
+const (
+	ConfigString   = "string"
+	ConfigInt      = "int"
+	ConfigFloat    = "float"
+	ConfigBool     = "bool"
+	ConfigDuration = "duration"
+	ConfigList     = "list"
+)
+
+// ConfigRule describes one config key. Rules holds validator tags, the same
+// ones used in binding tags (e.g. "oneof=mysql sqlite" or "min=1,max=65535"),
+// and is checked against the value after it has been converted to Type.
+type ConfigRule struct {
+	Required bool
+	Type     string
+	Rules    string
+}
+
+// ValidateSchema checks the config block name against schema, whose keys are
+// dotted paths relative to the block. Every problem found is reported in the
+// returned error rather than only the first.
+func ValidateSchema(name string, schema map[string]ConfigRule) error {
+	keys := make([]string, 0, len(schema))
+	for key := range schema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []error
+	for _, key := range keys {
+		fullKey := name + "." + key
+		if err := checkConfigRule(core.Get(fullKey), schema[key]); err != nil {
+			problems = append(problems, fmt.Errorf("%s %w", fullKey, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid %s config:\n%w", name, errors.Join(problems...))
+	}
+	return nil
+}
+
+// checkConfigRule checks a single value against its rule
+func checkConfigRule(value interface{}, rule ConfigRule) error {
+	if value == nil || value == "" {
+		if rule.Required {
+			return errors.New("is required")
+		}
+		return nil
+	}
+
+	typed, err := convertConfigValue(value, rule.Type)
+	if err != nil {
+		return err
+	}
+	if rule.Rules == "" {
+		return nil
+	}
+
+	err = validateVar(context.Background(), typed, rule.Rules)
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		failed := validationErrors[0]
+		if failed.Param() != "" {
+			return fmt.Errorf("value %v fails rule %s=%s", value, failed.Tag(), failed.Param())
+		}
+		return fmt.Errorf("value %v fails rule %s", value, failed.Tag())
+	}
+	return err
+}
+
+// convertConfigValue converts a config value to the Go type for typeName
+func convertConfigValue(value interface{}, typeName string) (interface{}, error) {
+	text, isString := value.(string)
+
+	switch typeName {
+	case "", ConfigString:
+		if !isString {
+			return nil, fmt.Errorf("must be a string, got %T", value)
+		}
+		return text, nil
+	case ConfigInt:
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case int64:
+			return int(v), nil
+		case float64:
+			if v == math.Trunc(v) && !math.IsInf(v, 0) {
+				return int(v), nil
+			}
+		case json.Number:
+			if n, err := v.Int64(); err == nil {
+				return int(n), nil
+			}
+		case string:
+			if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+				return n, nil
+			}
+		}
+		return nil, fmt.Errorf("must be an integer, got %v", value)
+	case ConfigFloat:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case json.Number:
+			if f, err := v.Float64(); err == nil {
+				return f, nil
+			}
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return f, nil
+			}
+		}
+		return nil, fmt.Errorf("must be a number, got %v", value)
+	case ConfigBool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+				return b, nil
+			}
+		}
+		return nil, fmt.Errorf("must be a boolean, got %v", value)
+	case ConfigDuration:
+		switch v := value.(type) {
+		case time.Duration:
+			return v, nil
+		case int:
+			return time.Duration(v) * time.Second, nil
+		case int64:
+			return time.Duration(v) * time.Second, nil
+		case float64:
+			return time.Duration(v * float64(time.Second)), nil
+		case json.Number:
+			if f, err := v.Float64(); err == nil {
+				return time.Duration(f * float64(time.Second)), nil
+			}
+		case string:
+			if d, err := time.ParseDuration(strings.TrimSpace(v)); err == nil {
+				return d, nil
+			}
+			if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+				return time.Duration(n) * time.Second, nil
+			}
+		}
+		return nil, fmt.Errorf("must be a duration, got %v", value)
+	case ConfigList:
+		switch v := value.(type) {
+		case []string, []interface{}:
+			return v, nil
+		case string:
+			return strings.Split(v, ","), nil
+		}
+		return nil, fmt.Errorf("must be a list, got %T", value)
+	}
+	return nil, fmt.Errorf("has unknown schema type %q", typeName)
+}
@@ -0,0 +1,71 @@
+package validators
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"base_lara_go_project/app/core"
+)
+
+func testSchema() map[string]ConfigRule {
+	return map[string]ConfigRule{
+		"driver":  {Required: true, Rules: "oneof=mysql sqlite"},
+		"host":    {Required: true},
+		"port":    {Required: true, Type: ConfigInt, Rules: "min=1,max=65535"},
+		"timeout": {Type: ConfigDuration},
+		"debug":   {Type: ConfigBool},
+	}
+}
+
+func TestValidateSchemaAcceptsValidBlock(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"env strings": {"driver": "mysql", "host": "db", "port": "3306", "timeout": "5s", "debug": "true"},
+		"go values":   {"driver": "mysql", "host": "db", "port": 3306, "timeout": 5, "debug": true},
+		"json floats": {"driver": "mysql", "host": "db", "port": float64(3306), "timeout": float64(2.5)},
+		"json number": {"driver": "sqlite", "host": "db", "port": json.Number("3306"), "timeout": json.Number("5")},
+	}
+
+	for name, block := range tests {
+		t.Run(name, func(t *testing.T) {
+			core.Set("schema_valid", block)
+			if err := ValidateSchema("schema_valid", testSchema()); err != nil {
+				t.Fatalf("expected valid config, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateSchemaReportsEveryProblem(t *testing.T) {
+	core.Set("schema_invalid", map[string]interface{}{
+		"driver":  "postgres",
+		"port":    float64(3306.5),
+		"timeout": "soon",
+	})
+
+	err := ValidateSchema("schema_invalid", testSchema())
+	if err == nil {
+		t.Fatal("expected an error for an invalid config block")
+	}
+
+	message := err.Error()
+	for _, want := range []string{
+		"schema_invalid.driver value postgres fails rule oneof=mysql sqlite",
+		"schema_invalid.host is required",
+		"schema_invalid.port must be an integer",
+		"schema_invalid.timeout must be a duration",
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("error %q does not mention %q", message, want)
+		}
+	}
+}
+
+func TestValidateSchemaChecksRulesOnConvertedValue(t *testing.T) {
+	core.Set("schema_range", map[string]interface{}{"driver": "mysql", "host": "db", "port": float64(70000)})
+
+	err := ValidateSchema("schema_range", testSchema())
+	if err == nil || !strings.Contains(err.Error(), "fails rule max=65535") {
+		t.Fatalf("expected port to fail max=65535, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"base_lara_go_project/app/core"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// database is the connection used by the unique and exists rules.
+// When nil, the global database instance is used.
+var database core.DatabaseInterface
+
+// SetDatabase injects the database connection used by the database rules
+func SetDatabase(db core.DatabaseInterface) {
+	database = db
+}
+
+// getDatabase returns the injected database or the global instance
+func getDatabase() core.DatabaseInterface {
+	if database != nil {
+		return database
+	}
+	return core.DatabaseInstance
+}
+
+// Unique fails when a row already has the field's value in the given column.
+// Usage: `binding:"unique=users email"`. An optional third parameter names a
+// sibling field holding an ID to ignore, e.g. `binding:"unique=users email ID"`,
+// so a record being updated doesn't collide with itself. Soft deleted rows
+// are ignored.
+func Unique(ctx context.Context, fl validator.FieldLevel) bool {
+	params := strings.Fields(fl.Param())
+	if len(params) < 2 {
+		return ruleFailed(ctx, "unique", fmt.Errorf("table and column parameters are required, got %q", fl.Param()))
+	}
+
+	query, err := liveRows(params[0])
+	if err != nil {
+		return ruleFailed(ctx, "unique", err)
+	}
+	query = query.Where(params[1]+" = ?", fl.Field().Interface())
+
+	if len(params) > 2 {
+		exceptID := fl.Parent().FieldByName(params[2])
+		if exceptID.IsValid() && !exceptID.IsZero() {
+			query = query.Where("id <> ?", exceptID.Interface())
+		}
+	}
+
+	count, err := countRows(query)
+	if err != nil {
+		return ruleFailed(ctx, "unique", err)
+	}
+	return count == 0
+}
+
+// Exists fails unless a row has the field's value in the given column.
+// Usage: `binding:"exists=roles name"`. Soft deleted rows don't count.
+func Exists(ctx context.Context, fl validator.FieldLevel) bool {
+	params := strings.Fields(fl.Param())
+	if len(params) < 2 {
+		return ruleFailed(ctx, "exists", fmt.Errorf("table and column parameters are required, got %q", fl.Param()))
+	}
+
+	query, err := liveRows(params[0])
+	if err != nil {
+		return ruleFailed(ctx, "exists", err)
+	}
+
+	count, err := countRows(query.Where(params[1]+" = ?", fl.Field().Interface()))
+	if err != nil {
+		return ruleFailed(ctx, "exists", err)
+	}
+	return count > 0
+}
+
+// softDeleteTables caches whether each table has a deleted_at column
+var softDeleteTables sync.Map
+
+// liveRows starts a query on table that skips soft deleted rows, if the
+// table has a deleted_at column
+func liveRows(table string) (core.DatabaseInterface, error) {
+	db := getDatabase()
+	query := db.Table(table)
+
+	softDeletes, known := softDeleteTables.Load(table)
+	if !known {
+		found, err := hasColumn(db, table, "deleted_at")
+		if err != nil {
+			return nil, err
+		}
+		softDeletes = found
+		softDeleteTables.Store(table, softDeletes)
+	}
+	if softDeletes.(bool) {
+		query = query.Where("deleted_at IS NULL")
+	}
+	return query, nil
+}
+
+// hasColumn reports whether table has column
+func hasColumn(db core.DatabaseInterface, table, column string) (bool, error) {
+	rows, err := db.GetDB().Table(table).Limit(0).Rows()
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(columns, column), nil
+}
+
+// countRows counts the rows matched by a query
+func countRows(query core.DatabaseInterface) (int64, error) {
+	var count int64
+	err := query.Count(&count)
+	return count, err
+}
@@ -0,0 +1,181 @@
+package validators
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"base_lara_go_project/app/core"
+
+	"github.com/gin-gonic/gin/binding"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeTables is a fake database answering column lookups from columns and
+// counts with count, or failing every query when fail is set
+type fakeTables struct {
+	mutex   sync.Mutex
+	columns map[string][]string
+	count   int64
+	fail    bool
+	counts  []string
+}
+
+func (f *fakeTables) lastCount() string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.counts[len(f.counts)-1]
+}
+
+type fakeTablesConn struct{ tables *fakeTables }
+
+func (c fakeTablesConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c fakeTablesConn) Close() error                        { return nil }
+func (c fakeTablesConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c fakeTablesConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	f := c.tables
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.fail {
+		return nil, errors.New("connection refused")
+	}
+	if strings.HasPrefix(query, "SELECT count(*)") {
+		f.counts = append(f.counts, query)
+		return &fakeRows{columns: []string{"count(*)"}, values: []driver.Value{f.count}}, nil
+	}
+	for table, columns := range f.columns {
+		if strings.Contains(query, "`"+table+"`") {
+			return &fakeRows{columns: columns}, nil
+		}
+	}
+	return nil, errors.New("unexpected query " + query)
+}
+
+type fakeRows struct {
+	columns []string
+	values  []driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.values == nil {
+		return io.EOF
+	}
+	copy(dest, r.values)
+	r.values = nil
+	return nil
+}
+
+type fakeTablesConnector struct{ tables *fakeTables }
+
+func (c fakeTablesConnector) Connect(context.Context) (driver.Conn, error) {
+	return fakeTablesConn{c.tables}, nil
+}
+func (c fakeTablesConnector) Driver() driver.Driver { return nil }
+
+// useFakeTables points the database rules at a fake database
+func useFakeTables(t *testing.T, tables *fakeTables) {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sql.OpenDB(fakeTablesConnector{tables}),
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetDatabase(core.NewDatabaseProvider(db))
+	softDeleteTables = sync.Map{}
+	t.Cleanup(func() {
+		SetDatabase(nil)
+		softDeleteTables = sync.Map{}
+	})
+
+	for name, rule := range map[string]RuleFuncCtx{"unique": Unique, "exists": Exists} {
+		if err := RegisterRuleCtx(name, rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func newFakeTables() *fakeTables {
+	return &fakeTables{columns: map[string][]string{
+		"users": {"id", "email", "deleted_at"},
+		"roles": {"id", "name"},
+	}}
+}
+
+func TestDatabaseRulesSkipSoftDeletedRows(t *testing.T) {
+	tables := newFakeTables()
+	useFakeTables(t, tables)
+
+	if _, err := ValidateMapE(map[string]interface{}{"email": "a@example.com"}, map[string]string{"email": "unique=users email"}); err != nil {
+		t.Fatal(err)
+	}
+	if query := tables.lastCount(); !strings.Contains(query, "deleted_at IS NULL") {
+		t.Fatalf("expected soft deleted users to be ignored, got %q", query)
+	}
+
+	tables.count = 1
+	if _, err := ValidateMapE(map[string]interface{}{"role": "admin"}, map[string]string{"role": "exists=roles name"}); err != nil {
+		t.Fatal(err)
+	}
+	if query := tables.lastCount(); strings.Contains(query, "deleted_at") {
+		t.Fatalf("expected no soft delete condition on a table without deleted_at, got %q", query)
+	}
+}
+
+func TestDatabaseRulesReturnQueryErrors(t *testing.T) {
+	tables := newFakeTables()
+	tables.fail = true
+	useFakeTables(t, tables)
+
+	for field, rule := range map[string]string{"email": "unique=users email", "role": "exists=roles name"} {
+		_, err := ValidateMapE(map[string]interface{}{field: "value"}, map[string]string{field: rule})
+
+		var ruleErr *RuleError
+		if !errors.As(err, &ruleErr) {
+			t.Fatalf("expected a RuleError for %s, got %v", rule, err)
+		}
+		if _, ok := AsValidationErrors(err); ok {
+			t.Fatalf("a query failure must not be reported as invalid input: %v", err)
+		}
+	}
+
+	// A failed lookup isn't cached, so the rule checks again once the database is back
+	tables.fail = false
+	if _, err := ValidateMapE(map[string]interface{}{"email": "a@example.com"}, map[string]string{"email": "unique=users email"}); err != nil {
+		t.Fatal(err)
+	}
+	if query := tables.lastCount(); !strings.Contains(query, "deleted_at IS NULL") {
+		t.Fatalf("expected soft deleted users to be ignored, got %q", query)
+	}
+}
+
+func TestBindingReturnsRuleErrors(t *testing.T) {
+	tables := newFakeTables()
+	tables.fail = true
+	useFakeTables(t, tables)
+
+	var input struct {
+		Email string `json:"email" binding:"unique=users email"`
+	}
+	input.Email = "a@example.com"
+
+	err := ReturnRuleErrors(binding.Validator).ValidateStruct(&input)
+	var ruleErr *RuleError
+	if !errors.As(err, &ruleErr) || ruleErr.Rule != "unique" {
+		t.Fatalf("expected the unique rule's query error, got %v", err)
+	}
+}
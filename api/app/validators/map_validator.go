@@ -0,0 +1,241 @@
+package validators
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidateMap validates a decoded JSON payload against rules keyed by dotted
+// paths into nested maps, e.g. "user.email": "required,email", without a
+// struct to bind into. It returns the validated values, nested the same way
+// and limited to the keys that have rules, and messages keyed by path in the
+// same shape as FormatErrors, which is nil when the payload is valid.
+//
+// A rule that couldn't be checked, such as a database rule whose query
+// failed, is reported as a message; use ValidateMapE to get its error.
+//
+// As in Laravel, a missing or null value only fails its required rule.
+// Rules comparing against another field, such as eqfield or confirmed, need
+// a struct and are not supported here.
+func ValidateMap(data map[string]interface{}, rules map[string]string) (map[string]interface{}, map[string][]string) {
+	paths := rulePaths(rules)
+	results := make([]mapResult, len(paths))
+	for i, path := range paths {
+		results[i] = checkMapPath(context.Background(), data, path, rules[path])
+	}
+	return collectMapResults(paths, results)
+}
+
+// ValidateMapE validates like ValidateMap, returning the validated values
+// and, when validation fails, a ValidationErrors recording each failed rule
+func ValidateMapE(data map[string]interface{}, rules map[string]string) (map[string]interface{}, error) {
+	paths := rulePaths(rules)
+	results := make([]mapResult, len(paths))
+	for i, path := range paths {
+		results[i] = checkMapPath(context.Background(), data, path, rules[path])
+	}
+
+	var failures ValidationErrors
+	for i, result := range results {
+		if result.err != nil {
+			return nil, result.err
+		}
+		if !result.passed {
+			failures = append(failures, FieldError{Field: paths[i], Rule: result.rule, Message: result.message})
+		}
+	}
+	if len(failures) > 0 {
+		return nil, failures
+	}
+
+	validated, _ := collectMapResults(paths, results)
+	return validated, nil
+}
+
+// asyncRuleConcurrency caps how many rule chains ValidateMapAsync runs at
+// once, so database rules don't exhaust the connection pool
+const asyncRuleConcurrency = 8
+
+// ValidateMapAsync validates like ValidateMap but runs each path's rules
+// concurrently, which pays off when rules such as unique or exists query the
+// database. The payload is only read, so every rule sees the same data. It
+// returns ctx's error if ctx is done before every rule has finished; rules
+// already running are left to finish in the background.
+func ValidateMapAsync(ctx context.Context, data map[string]interface{}, rules map[string]string) (map[string]interface{}, map[string][]string, error) {
+	paths := rulePaths(rules)
+	results := make([]mapResult, len(paths))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		var wg sync.WaitGroup
+		slots := make(chan struct{}, asyncRuleConcurrency)
+		for i, path := range paths {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				wg.Wait()
+				return
+			}
+			wg.Add(1)
+			go func(i int, path string) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				results[i] = checkMapPath(ctx, data, path, rules[path])
+			}(i, path)
+		}
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		for _, result := range results {
+			if result.err != nil {
+				return nil, nil, result.err
+			}
+		}
+		validated, failures := collectMapResults(paths, results)
+		return validated, failures, nil
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+// mapResult is the outcome of checking one path
+type mapResult struct {
+	value   interface{}
+	present bool
+	rule    string
+	message string
+	passed  bool
+	// err is set when a rule couldn't be checked
+	err error
+}
+
+// rulePaths returns the paths of rules in order, so messages are stable
+func rulePaths(rules map[string]string) []string {
+	paths := make([]string, 0, len(rules))
+	for path := range rules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// checkMapPath checks the value at path against its rules
+func checkMapPath(ctx context.Context, data map[string]interface{}, path, tags string) mapResult {
+	value, present := nestedValue(data, path)
+	rule, message, passed, err := checkMapValue(ctx, path, value, present, tags)
+	return mapResult{value: value, present: present, rule: rule, message: message, passed: passed, err: err}
+}
+
+// collectMapResults builds the validated values and messages from the
+// results for paths
+func collectMapResults(paths []string, results []mapResult) (map[string]interface{}, map[string][]string) {
+	validated := make(map[string]interface{})
+	var failures map[string][]string
+	for i, path := range paths {
+		result := results[i]
+		if !result.passed {
+			if failures == nil {
+				failures = make(map[string][]string)
+			}
+			failures[path] = append(failures[path], result.message)
+			continue
+		}
+		if result.present {
+			setNestedValue(validated, path, result.value)
+		}
+	}
+	return validated, failures
+}
+
+// checkMapValue checks one value against its rules, returning the first
+// rule it fails along with its message, or a RuleError if a rule couldn't be
+// checked
+func checkMapValue(ctx context.Context, path string, value interface{}, present bool, tags string) (string, string, bool, error) {
+	if !present || value == nil {
+		for _, tag := range strings.Split(tags, ",") {
+			if strings.TrimSpace(tag) == "required" {
+				return "required", formatMessage(path, "required", "", reflect.Invalid, nil), false, nil
+			}
+		}
+		return "", "", true, nil
+	}
+
+	err := validateVar(ctx, value, tags)
+	var ruleErr *RuleError
+	if errors.As(err, &ruleErr) {
+		return "", err.Error(), false, err
+	}
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		failed := validationErrors[0]
+		return failed.Tag(), formatMessage(path, failed.Tag(), failed.Param(), failed.Kind(), value), false, nil
+	}
+	if err != nil {
+		return "", err.Error(), false, nil
+	}
+	return "", "", true, nil
+}
+
+// nestedValue looks up a dotted path in nested maps
+func nestedValue(data map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = data
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// setNestedValue stores a copy of value at a dotted path, creating nested
+// maps. Maps are copied so that a rule on a parent and a child path never
+// writes the child into the caller's payload.
+func setNestedValue(data map[string]interface{}, path string, value interface{}) {
+	parts := strings.Split(path, ".")
+	current := data
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = copyMapValue(value)
+}
+
+// copyMapValue deep-copies the maps and slices within a decoded value
+func copyMapValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = copyMapValue(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = copyMapValue(item)
+		}
+		return copied
+	}
+	return value
+}
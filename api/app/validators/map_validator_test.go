@@ -0,0 +1,186 @@
+package validators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// payload returns a freshly decoded-style nested payload
+func payload() map[string]interface{} {
+	return map[string]interface{}{
+		"user": map[string]interface{}{
+			"email":   "ada@example.com",
+			"name":    "Ada",
+			"address": map[string]interface{}{"city": "London", "zip": "N1"},
+		},
+		"tags": []interface{}{"a", "b"},
+	}
+}
+
+func TestValidateMapLeavesInputUnchanged(t *testing.T) {
+	rules := map[string]string{
+		"user":              "required",
+		"user.email":        "required,email",
+		"user.address":      "required",
+		"user.address.city": "required",
+		"tags":              "required",
+	}
+
+	data := payload()
+	validated, failures := ValidateMap(data, rules)
+	if failures != nil {
+		t.Fatalf("expected the payload to pass, got %v", failures)
+	}
+	if !reflect.DeepEqual(data, payload()) {
+		t.Fatalf("expected validation not to mutate the input, got %v", data)
+	}
+
+	// Writing to the result must not reach the caller's maps either
+	validated["user"].(map[string]interface{})["email"] = "changed"
+	validated["tags"].([]interface{})[0] = "changed"
+	if !reflect.DeepEqual(data, payload()) {
+		t.Fatalf("expected the result not to share maps with the input, got %v", data)
+	}
+}
+
+func TestValidateMapAsyncLeavesInputUnchanged(t *testing.T) {
+	rules := map[string]string{"user": "required", "user.name": "required", "user.address.zip": "required"}
+
+	data := payload()
+	if _, failures, err := ValidateMapAsync(context.Background(), data, rules); err != nil || failures != nil {
+		t.Fatalf("expected the payload to pass, got %v, %v", failures, err)
+	}
+	if !reflect.DeepEqual(data, payload()) {
+		t.Fatalf("expected validation not to mutate the input, got %v", data)
+	}
+}
+
+func TestValidateMapReportsMissingAndInvalidPaths(t *testing.T) {
+	data := map[string]interface{}{"user": map[string]interface{}{"email": "not-an-email"}}
+	validated, failures := ValidateMap(data, map[string]string{
+		"user.email": "required,email",
+		"user.name":  "required",
+		"user.age":   "omitempty,min=18",
+	})
+
+	if len(failures["user.email"]) != 1 || len(failures["user.name"]) != 1 {
+		t.Fatalf("expected email and name to fail, got %v", failures)
+	}
+	if _, ok := failures["user.age"]; ok {
+		t.Fatal("expected an absent optional path to pass")
+	}
+	if _, ok := validated["user"]; ok {
+		t.Fatalf("expected no validated values for failing paths, got %v", validated)
+	}
+}
+
+// slowRuleDelay is how long test_slow takes to check a value
+const slowRuleDelay = 50 * time.Millisecond
+
+// registerSlowRule registers test_slow, which takes slowRuleDelay and
+// passes values other than "bad", like a rule querying the database
+func registerSlowRule(t *testing.T) {
+	t.Helper()
+	forgetRules(t, "test_slow")
+	err := RegisterRule("test_slow", func(fl validator.FieldLevel) bool {
+		time.Sleep(slowRuleDelay)
+		return fl.Field().String() != "bad"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// slowPayload returns count fields checked by test_slow, with every third
+// one failing
+func slowPayload(count int) (map[string]interface{}, map[string]string) {
+	data := make(map[string]interface{}, count)
+	rules := make(map[string]string, count)
+	for i := 0; i < count; i++ {
+		path := fmt.Sprintf("field_%d", i)
+		data[path] = "good"
+		if i%3 == 0 {
+			data[path] = "bad"
+		}
+		rules[path] = "required,test_slow"
+	}
+	return data, rules
+}
+
+func TestValidateMapAsyncMatchesValidateMap(t *testing.T) {
+	registerSlowRule(t)
+	data, rules := slowPayload(asyncRuleConcurrency)
+	rules["missing"] = "required"
+
+	validated, failures := ValidateMap(data, rules)
+	asyncValidated, asyncFailures, err := ValidateMapAsync(context.Background(), data, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(asyncValidated, validated) {
+		t.Fatalf("expected the same validated values, got %v and %v", asyncValidated, validated)
+	}
+	if !reflect.DeepEqual(asyncFailures, failures) {
+		t.Fatalf("expected the same failures, got %v and %v", asyncFailures, failures)
+	}
+	if len(failures) != 4 {
+		t.Fatalf("expected three bad fields and the missing one to fail, got %v", failures)
+	}
+}
+
+func TestValidateMapAsyncRunsRulesConcurrently(t *testing.T) {
+	registerSlowRule(t)
+	data, rules := slowPayload(asyncRuleConcurrency)
+
+	started := time.Now()
+	if _, _, err := ValidateMapAsync(context.Background(), data, rules); err != nil {
+		t.Fatal(err)
+	}
+	// Run one after another the rules would take asyncRuleConcurrency delays
+	if elapsed := time.Since(started); elapsed >= slowRuleDelay*asyncRuleConcurrency/2 {
+		t.Fatalf("expected the rules to run concurrently, took %v", elapsed)
+	}
+}
+
+// blockingRules counts the blocking rules registered
+var blockingRules atomic.Int64
+
+func TestValidateMapAsyncReturnsWhenContextIsDone(t *testing.T) {
+	release, returned := make(chan struct{}), make(chan struct{})
+	// The rule is left running; let it finish before later tests register
+	// rules on the shared validator
+	defer func() {
+		close(release)
+		<-returned
+	}()
+	// The validator caches rules by tag, so each run needs a rule of its own
+	name := fmt.Sprintf("test_blocking_%d", blockingRules.Add(1))
+	forgetRules(t, name)
+	err := RegisterRule(name, func(validator.FieldLevel) bool {
+		<-release
+		close(returned)
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	data := map[string]interface{}{"name": "Ada"}
+	validated, failures, err := ValidateMapAsync(ctx, data, map[string]string{"name": name})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context's error, got %v", err)
+	}
+	if validated != nil || failures != nil {
+		t.Fatalf("expected no results once the context is done, got %v, %v", validated, failures)
+	}
+}
@@ -0,0 +1,199 @@
+package validators
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// defaultMessages are the Laravel-style messages for each validation tag.
+// Keys suffixed with .string or .array apply when the field is of that kind.
+var defaultMessages = map[string]string{
+	"required":       "The :attribute field is required.",
+	"email":          "The :attribute must be a valid email address.",
+	"min":            "The :attribute must be at least :min.",
+	"min.string":     "The :attribute must be at least :min characters.",
+	"min.array":      "The :attribute must have at least :min items.",
+	"max":            "The :attribute may not be greater than :max.",
+	"max.string":     "The :attribute may not be greater than :max characters.",
+	"max.array":      "The :attribute may not have more than :max items.",
+	"between":        "The :attribute must be between :min and :max.",
+	"between.string": "The :attribute must be between :min and :max characters.",
+	"between.array":  "The :attribute must have between :min and :max items.",
+	"size":           "The :attribute must be :size.",
+	"size.string":    "The :attribute must be :size characters.",
+	"size.array":     "The :attribute must contain :size items.",
+	"digits":         "The :attribute must be :size digits.",
+	"digits_between": "The :attribute must be between :min and :max digits.",
+	"eqfield":        "The :attribute and :other must match.",
+	"nefield":        "The :attribute and :other must be different.",
+	"gtfield":        "The :attribute must be greater than :other.",
+	"gtfield.string": "The :attribute must be longer than :other.",
+	"gtefield":       "The :attribute must be greater than or equal to :other.",
+	"ltfield":        "The :attribute must be less than :other.",
+	"ltfield.string": "The :attribute must be shorter than :other.",
+	"ltefield":       "The :attribute must be less than or equal to :other.",
+	"confirmed":      "The :attribute confirmation does not match.",
+	"unique":         "The :attribute has already been taken.",
+	"exists":         "The selected :attribute is invalid.",
+	"oneof":          "The selected :attribute is invalid.",
+	"not_in":         "The selected :attribute is invalid.",
+	"numeric":        "The :attribute must be a number.",
+	"boolean":        "The :attribute field must be true or false.",
+	"alpha":          "The :attribute may only contain letters.",
+	"alphanum":       "The :attribute may only contain letters and numbers.",
+	"url":            "The :attribute format is invalid.",
+	"regex":          "The :attribute format is invalid.",
+	"date":           "The :attribute is not a valid date.",
+	"e164":           "The :attribute must be a valid phone number.",
+	"nameField":      "The :attribute may only contain letters, spaces, hyphens and apostrophes.",
+}
+
+// fallbackMessage is used for tags without a registered message
+const fallbackMessage = "The :attribute field is invalid."
+
+var (
+	customMessages = make(map[string]string)
+	attributeNames = make(map[string]string)
+	messagesMutex  sync.RWMutex
+)
+
+// SetMessages registers custom messages, keyed either by tag ("required") or
+// by field and tag ("email.required"). Messages may use the same placeholders
+// as the defaults.
+func SetMessages(messages map[string]string) {
+	messagesMutex.Lock()
+	defer messagesMutex.Unlock()
+
+	for key, message := range messages {
+		customMessages[key] = message
+	}
+}
+
+// SetAttributeNames registers display names used for :attribute and :other,
+// e.g. "email" => "Email address"
+func SetAttributeNames(names map[string]string) {
+	messagesMutex.Lock()
+	defer messagesMutex.Unlock()
+
+	for field, name := range names {
+		attributeNames[field] = name
+	}
+}
+
+// FormatErrors converts validation errors into messages keyed by field.
+// It returns nil when err is not a validation error.
+func FormatErrors(err error) map[string][]string {
+	validationErrors, ok := AsValidationErrors(err)
+	if !ok {
+		return nil
+	}
+	return validationErrors.ToMap()
+}
+
+// FormatError renders the message for a single field error with its
+// placeholders replaced
+func FormatError(fieldError validator.FieldError) string {
+	return formatMessage(fieldError.Field(), fieldError.Tag(), fieldError.Param(), fieldError.Kind(), fieldError.Value())
+}
+
+// formatMessage renders the message for field failing tag with its
+// placeholders replaced
+func formatMessage(field, tag, param string, kind reflect.Kind, value interface{}) string {
+	messagesMutex.RLock()
+	defer messagesMutex.RUnlock()
+
+	message := findMessage(field, tag, kind)
+
+	min, max := ruleBounds(tag, param)
+	replacer := strings.NewReplacer(
+		":attribute", attributeName(field),
+		":other", attributeName(param),
+		":min", min,
+		":max", max,
+		":size", param,
+		":value", fmt.Sprint(value),
+	)
+	return replacer.Replace(message)
+}
+
+// findMessage resolves the message for a field and tag, preferring custom
+// messages over defaults and kind-specific defaults over generic ones
+func findMessage(field, tag string, kind reflect.Kind) string {
+	if message, ok := customMessages[field+"."+tag]; ok {
+		return message
+	}
+	if message, ok := customMessages[tag]; ok {
+		return message
+	}
+
+	switch kind {
+	case reflect.String:
+		if message, ok := defaultMessages[tag+".string"]; ok {
+			return message
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if message, ok := defaultMessages[tag+".array"]; ok {
+			return message
+		}
+	}
+
+	if message, ok := defaultMessages[tag]; ok {
+		return message
+	}
+	return fallbackMessage
+}
+
+// ruleBounds extracts the :min and :max placeholder values from a rule's parameters
+func ruleBounds(tag, param string) (string, string) {
+	switch tag {
+	case "min", "gte", "gt":
+		return param, ""
+	case "max", "lte", "lt":
+		return "", param
+	case "between", "digits_between":
+		params := strings.Fields(param)
+		if len(params) == 2 {
+			return params[0], params[1]
+		}
+	}
+	return "", ""
+}
+
+// attributeName returns the display name of a field, falling back to a
+// humanized version of the field key ("first_name" or "FirstName" => "first name")
+func attributeName(field string) string {
+	if name, ok := attributeNames[field]; ok {
+		return name
+	}
+
+	var words strings.Builder
+	for i, r := range field {
+		switch {
+		case r == '_':
+			words.WriteRune(' ')
+		case unicode.IsUpper(r):
+			if i > 0 {
+				words.WriteRune(' ')
+			}
+			words.WriteRune(unicode.ToLower(r))
+		default:
+			words.WriteRune(r)
+		}
+	}
+	return words.String()
+}
+
+// JSONFieldName names a struct field by its json tag, falling back to the Go
+// field name. Fields tagged "-" are reported by their Go name.
+func JSONFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,197 @@
+package validators
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type profileInput struct {
+	Email           string   `json:"email" validate:"required"`
+	FirstName       string   `json:"first_name" validate:"max=5"`
+	Tags            []string `json:"tags" validate:"min=2"`
+	Password        string   `json:"password"`
+	ConfirmPassword string   `json:"confirm_password" validate:"eqfield=Password"`
+}
+
+// withMessages restores the registered custom messages and attribute names
+// once the test finishes
+func withMessages(t *testing.T) {
+	t.Helper()
+	messagesMutex.Lock()
+	savedMessages, savedNames := customMessages, attributeNames
+	customMessages, attributeNames = make(map[string]string), make(map[string]string)
+	messagesMutex.Unlock()
+
+	t.Cleanup(func() {
+		messagesMutex.Lock()
+		customMessages, attributeNames = savedMessages, savedNames
+		messagesMutex.Unlock()
+	})
+}
+
+func validateProfile(t *testing.T, input profileInput) map[string][]string {
+	t.Helper()
+	v := validator.New()
+	v.RegisterTagNameFunc(JSONFieldName)
+
+	messages := FormatErrors(v.Struct(input))
+	if messages == nil {
+		t.Fatal("expected validation errors")
+	}
+	return messages
+}
+
+func TestDefaultMessagesReplacePlaceholders(t *testing.T) {
+	withMessages(t)
+
+	messages := validateProfile(t, profileInput{
+		FirstName:       "Bartholomew",
+		Tags:            []string{"a"},
+		Password:        "secret",
+		ConfirmPassword: "other",
+	})
+
+	want := map[string][]string{
+		"email":            {"The email field is required."},
+		"first_name":       {"The first name may not be greater than 5 characters."},
+		"tags":             {"The tags must have at least 2 items."},
+		"confirm_password": {"The confirm password and password must match."},
+	}
+	if !reflect.DeepEqual(messages, want) {
+		t.Fatalf("expected %v, got %v", want, messages)
+	}
+}
+
+func TestCustomMessagesUseAttributeNamesAndParameters(t *testing.T) {
+	withMessages(t)
+	SetAttributeNames(map[string]string{"email": "Email address", "Password": "chosen password"})
+	SetMessages(map[string]string{
+		"required":       ":attribute is missing.",
+		"first_name.max": ":attribute ':value' is over :max letters.",
+		"eqfield":        ":attribute must equal the :other.",
+	})
+
+	messages := validateProfile(t, profileInput{
+		FirstName:       "Bartholomew",
+		Tags:            []string{"a", "b"},
+		Password:        "secret",
+		ConfirmPassword: "other",
+	})
+
+	want := map[string][]string{
+		"email":            {"Email address is missing."},
+		"first_name":       {"first name 'Bartholomew' is over 5 letters."},
+		"confirm_password": {"confirm password must equal the chosen password."},
+	}
+	if !reflect.DeepEqual(messages, want) {
+		t.Fatalf("expected %v, got %v", want, messages)
+	}
+}
+
+func TestRangeRulesFillMinAndMax(t *testing.T) {
+	withMessages(t)
+
+	cases := []struct {
+		tag, param string
+		want       string
+	}{
+		{"between", "3 9", "The age must be between 3 and 9."},
+		{"digits_between", "4 6", "The age must be between 4 and 6 digits."},
+		{"digits", "4", "The age must be 4 digits."},
+		{"unknown_rule", "", "The age field is invalid."},
+	}
+	for _, tc := range cases {
+		if got := formatMessage("age", tc.tag, tc.param, reflect.Int, 1); got != tc.want {
+			t.Errorf("%s: expected %q, got %q", tc.tag, tc.want, got)
+		}
+	}
+}
+
+type contactDetails struct {
+	PhoneNumber string `json:"phone_number" validate:"required"`
+}
+
+type auditFields struct {
+	CreatedBy string `json:"created_by" validate:"required"`
+}
+
+type registrationInput struct {
+	auditFields
+	FirstName string         `json:"first_name,omitempty" validate:"required"`
+	LastName  string         `json:"-" validate:"required"`
+	Nickname  string         `validate:"required"`
+	Contact   contactDetails `json:"contact"`
+}
+
+func TestErrorsAreKeyedByJSONFieldNames(t *testing.T) {
+	withMessages(t)
+	v := validator.New()
+	v.RegisterTagNameFunc(JSONFieldName)
+
+	validationErrors, ok := AsValidationErrors(v.Struct(registrationInput{}))
+	if !ok {
+		t.Fatal("expected validation errors")
+	}
+
+	fields := map[string]bool{}
+	for _, fieldError := range validationErrors {
+		fields[fieldError.Field] = true
+	}
+	// Embedded and nested structs report their own json names, and fields
+	// without a usable json name fall back to the Go field name
+	want := map[string]bool{
+		"created_by":   true,
+		"first_name":   true,
+		"LastName":     true,
+		"Nickname":     true,
+		"phone_number": true,
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("expected errors for %v, got %v", want, fields)
+	}
+	if messages := validationErrors.ToMap(); messages["first_name"][0] != "The first name field is required." {
+		t.Fatalf("expected the message to name the json field, got %v", messages["first_name"])
+	}
+}
+
+type rangeInput struct {
+	MinAge   int    `json:"min_age"`
+	MaxAge   int    `json:"max_age" validate:"gtfield=MinAge"`
+	Limit    int    `json:"limit" validate:"ltefield=MaxAge"`
+	Short    string `json:"short"`
+	Long     string `json:"long" validate:"gtfield=Short"`
+	Password string `json:"password" validate:"nefield=Short"`
+	Missing  int    `json:"missing" validate:"gtefield=Absent"`
+}
+
+func TestCrossFieldComparisonRules(t *testing.T) {
+	withMessages(t)
+	v := validator.New()
+	v.RegisterTagNameFunc(JSONFieldName)
+
+	failed := func(input rangeInput) map[string][]string {
+		validationErrors, _ := AsValidationErrors(v.Struct(input))
+		return validationErrors.ToMap()
+	}
+
+	// Numbers compare by value and strings by length; a comparison with a
+	// field that does not exist always fails
+	messages := failed(rangeInput{MinAge: 18, MaxAge: 65, Limit: 30, Short: "ab", Long: "abc", Password: "secret"})
+	if want := map[string][]string{"missing": {"The missing must be greater than or equal to absent."}}; !reflect.DeepEqual(messages, want) {
+		t.Fatalf("expected only the missing comparison to fail, got %v", messages)
+	}
+
+	messages = failed(rangeInput{MinAge: 65, MaxAge: 18, Limit: 19, Short: "abc", Long: "xyz", Password: "abc"})
+	want := map[string][]string{
+		"max_age":  {"The max age must be greater than min age."},
+		"limit":    {"The limit must be less than or equal to max age."},
+		"long":     {"The long must be longer than short."},
+		"password": {"The password and short must be different."},
+		"missing":  {"The missing must be greater than or equal to absent."},
+	}
+	if !reflect.DeepEqual(messages, want) {
+		t.Fatalf("expected %v, got %v", want, messages)
+	}
+}
@@ -0,0 +1,119 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// RuleError is returned when a rule can't decide whether a field passes,
+// such as a database rule whose query failed or a rule given malformed
+// parameters. Validation returns it instead of reporting a failed field.
+type RuleError struct {
+	Rule string
+	Err  error
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("%s rule could not be checked: %v", e.Rule, e.Err)
+}
+
+func (e *RuleError) Unwrap() error {
+	return e.Err
+}
+
+// ruleErrorsKey is the context key of a validation's ruleErrors
+type ruleErrorsKey struct{}
+
+// ruleErrors keeps the first RuleError reported during one validation
+type ruleErrors struct {
+	mutex sync.Mutex
+	first *RuleError
+}
+
+// withRuleErrors returns a context the rules of one validation report their
+// RuleErrors through
+func withRuleErrors(ctx context.Context) (context.Context, *ruleErrors) {
+	errs := &ruleErrors{}
+	return context.WithValue(ctx, ruleErrorsKey{}, errs), errs
+}
+
+// result returns the first RuleError reported, or err if there was none
+func (e *ruleErrors) result(err error) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.first != nil {
+		return e.first
+	}
+	return err
+}
+
+// ruleFailed reports that rule couldn't check a field and fails the field.
+// The validation running with ctx returns the error; without one, as when a
+// rule is used on a bare validator, the error is logged.
+func ruleFailed(ctx context.Context, rule string, err error) bool {
+	ruleErr := &RuleError{Rule: rule, Err: err}
+
+	errs, ok := ctx.Value(ruleErrorsKey{}).(*ruleErrors)
+	if !ok {
+		log.Printf("%v", ruleErr)
+		return false
+	}
+
+	errs.mutex.Lock()
+	defer errs.mutex.Unlock()
+	if errs.first == nil {
+		errs.first = ruleErr
+	}
+	return false
+}
+
+// validateVar validates value against tags, returning any RuleError
+// reported by its rules
+func validateVar(ctx context.Context, value interface{}, tags string) error {
+	ctx, errs := withRuleErrors(ctx)
+	return errs.result(Engine().VarCtx(ctx, value, tags))
+}
+
+// ruleErrorValidator returns a RuleError reported during binding as the
+// binding error
+type ruleErrorValidator struct {
+	binding.StructValidator
+}
+
+// ValidateStruct validates obj like gin's default validator, returning any
+// RuleError reported by its rules
+func (v ruleErrorValidator) ValidateStruct(obj any) error {
+	engine, ok := v.Engine().(*validator.Validate)
+	if !ok || obj == nil {
+		return v.StructValidator.ValidateStruct(obj)
+	}
+
+	value := reflect.ValueOf(obj)
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.Elem().Kind() != reflect.Struct {
+			return v.ValidateStruct(value.Elem().Interface())
+		}
+	case reflect.Struct:
+	default:
+		return v.StructValidator.ValidateStruct(obj)
+	}
+
+	ctx, errs := withRuleErrors(context.Background())
+	return errs.result(engine.StructCtx(ctx, obj))
+}
+
+// ReturnRuleErrors wraps a binding validator so a rule that can't be checked
+// fails binding with a RuleError rather than a validation message
+func ReturnRuleErrors(v binding.StructValidator) binding.StructValidator {
+	if _, ok := v.(ruleErrorValidator); ok {
+		return v
+	}
+	return ruleErrorValidator{v}
+}
@@ -0,0 +1,93 @@
+package validators
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// RuleFunc reports whether a field passes a rule
+type RuleFunc func(fl validator.FieldLevel) bool
+
+// RuleFuncCtx reports whether a field passes a rule that can fail to decide,
+// such as one querying the database. It reports that through ruleFailed
+// with ctx, which carries the validation's RuleError back to its caller.
+type RuleFuncCtx func(ctx context.Context, fl validator.FieldLevel) bool
+
+// registeredRule is a rule along with how it was registered
+type registeredRule struct {
+	fn RuleFuncCtx
+	// evenIfNil runs the rule for nil pointers instead of failing them
+	evenIfNil bool
+}
+
+var (
+	// rules holds every rule registered with RegisterRule, by name
+	rules      = map[string]registeredRule{}
+	rulesMutex sync.Mutex
+)
+
+// RegisterRule registers a named rule once for the whole app, like Laravel's
+// Validator::extend, so it can be used in binding tags (`binding:"phone"`)
+// and schema rules alike. Registering a name again replaces the rule.
+func RegisterRule(name string, fn RuleFunc) error {
+	return registerRule(name, registeredRule{fn: withoutContext(fn)})
+}
+
+// RegisterRuleCtx registers a named rule like RegisterRule for a rule that
+// takes the validation's context
+func RegisterRuleCtx(name string, fn RuleFuncCtx) error {
+	return registerRule(name, registeredRule{fn: fn})
+}
+
+// RegisterOptionalRule registers a rule that passes when the field is
+// absent. Unlike RegisterRule, the rule is also run for nil pointers, which
+// the validator otherwise fails without calling it.
+func RegisterOptionalRule(name string, fn RuleFunc) error {
+	return registerRule(name, registeredRule{fn: withoutContext(fn), evenIfNil: true})
+}
+
+// RegisterOptionalRuleCtx registers a rule like RegisterOptionalRule for a
+// rule that takes the validation's context
+func RegisterOptionalRuleCtx(name string, fn RuleFuncCtx) error {
+	return registerRule(name, registeredRule{fn: fn, evenIfNil: true})
+}
+
+// withoutContext adapts a rule that doesn't need the validation's context
+func withoutContext(fn RuleFunc) RuleFuncCtx {
+	return func(ctx context.Context, fl validator.FieldLevel) bool {
+		return fn(fl)
+	}
+}
+
+// registerRule stores a rule and registers it with gin's validator
+func registerRule(name string, rule registeredRule) error {
+	rulesMutex.Lock()
+	defer rulesMutex.Unlock()
+
+	rules[name] = rule
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		return v.RegisterValidationCtx(name, validator.FuncCtx(rule.fn), rule.evenIfNil)
+	}
+	return nil
+}
+
+// Engine returns the validator used for request binding. If gin's engine has
+// been replaced, a new validator with every registered rule is returned.
+func Engine() *validator.Validate {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		return v
+	}
+
+	v := validator.New()
+	v.RegisterTagNameFunc(JSONFieldName)
+
+	rulesMutex.Lock()
+	defer rulesMutex.Unlock()
+	for name, rule := range rules {
+		v.RegisterValidationCtx(name, validator.FuncCtx(rule.fn), rule.evenIfNil)
+	}
+	return v
+}
@@ -0,0 +1,108 @@
+package validators
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var testPhonePattern = regexp.MustCompile(`^\+?[0-9]{7,15}$`)
+
+// testPhone is a custom rule standing in for one an app would register
+func testPhone(fl validator.FieldLevel) bool {
+	return testPhonePattern.MatchString(fl.Field().String())
+}
+
+// forgetRules removes rules registered by a test once it finishes
+func forgetRules(t *testing.T, names ...string) {
+	t.Helper()
+	t.Cleanup(func() {
+		rulesMutex.Lock()
+		defer rulesMutex.Unlock()
+		for _, name := range names {
+			delete(rules, name)
+		}
+	})
+}
+
+func TestRegisteredRuleRunsAlongsideBuiltIns(t *testing.T) {
+	forgetRules(t, "test_phone")
+	if err := RegisterRule("test_phone", testPhone); err != nil {
+		t.Fatal(err)
+	}
+
+	type contact struct {
+		Name  string `json:"name" binding:"required,min=2"`
+		Phone string `json:"phone" binding:"required,test_phone"`
+	}
+
+	if err := Engine().Struct(contact{Name: "Ada", Phone: "+441234567"}); err != nil {
+		t.Fatalf("expected a valid contact to pass, got %v", err)
+	}
+
+	err := Engine().Struct(contact{Name: "A", Phone: "call me"})
+	failed := map[string]string{}
+	for _, fieldErr := range err.(validator.ValidationErrors) {
+		failed[fieldErr.StructField()] = fieldErr.Tag()
+	}
+	if failed["Name"] != "min" || failed["Phone"] != "test_phone" {
+		t.Fatalf("expected min and test_phone to fail, got %v", failed)
+	}
+
+	_, failures := ValidateMap(
+		map[string]interface{}{"phone": "12"},
+		map[string]string{"phone": "required,test_phone", "name": "required"},
+	)
+	if len(failures["phone"]) != 1 || len(failures["name"]) != 1 {
+		t.Fatalf("expected the rule to be usable by name in map rules, got %v", failures)
+	}
+}
+
+func TestRegisterRuleReplacesExistingRule(t *testing.T) {
+	forgetRules(t, "test_replaced")
+	if err := RegisterRule("test_replaced", func(validator.FieldLevel) bool { return false }); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterRule("test_replaced", func(validator.FieldLevel) bool { return true }); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Engine().Var("anything", "test_replaced"); err != nil {
+		t.Fatalf("expected the second registration to win, got %v", err)
+	}
+}
+
+func TestRegisterRuleConcurrently(t *testing.T) {
+	const count = 20
+	names := make([]string, count)
+	for i := range names {
+		names[i] = fmt.Sprintf("test_concurrent_%d", i)
+	}
+	forgetRules(t, names...)
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			pass := i%2 == 0
+			if err := RegisterRule(name, func(validator.FieldLevel) bool { return pass }); err != nil {
+				t.Error(err)
+			}
+		}(i, name)
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		err := Engine().Var("value", name)
+		if i%2 == 0 && err != nil {
+			t.Errorf("expected %s to pass, got %v", name, err)
+		}
+		if i%2 != 0 && err == nil {
+			t.Errorf("expected %s to fail", name)
+		}
+	}
+}
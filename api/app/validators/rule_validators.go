@@ -0,0 +1,299 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// The rules below fill the gaps between Laravel's rule set and the validator's
+// baked-in tags (numeric, boolean, alpha, alphanum, oneof, url, ...). Like
+// Laravel, they pass when the field is absent so they can be combined with
+// required only where needed, and are registered with RegisterOptionalRule so
+// that includes nil pointers. Zero numbers and false are values, not absence,
+// and are always checked. A rule given malformed parameters can't decide, so
+// it reports a RuleError like a database rule whose query failed.
+
+// dateLayouts are the formats accepted by the date rule
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// regexCache holds the compiled regex rule patterns, or their compile
+// errors, keyed by their source
+var regexCache sync.Map
+
+// compiledPattern is a regex rule pattern after compiling
+type compiledPattern struct {
+	regexp *regexp.Regexp
+	err    error
+}
+
+// isAbsent reports whether a field holds no value: a nil pointer, interface,
+// slice or map, or an empty string
+func isAbsent(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return field.IsNil()
+	case reflect.String:
+		return field.Len() == 0
+	}
+	return false
+}
+
+// NotIn fails when the field matches one of the space-separated values.
+// Usage: `binding:"not_in=admin root"`.
+func NotIn(fl validator.FieldLevel) bool {
+	if isAbsent(fl.Field()) {
+		return true
+	}
+
+	value := fmt.Sprint(fl.Field().Interface())
+	for _, disallowed := range strings.Fields(fl.Param()) {
+		if value == disallowed {
+			return false
+		}
+	}
+	return true
+}
+
+// Between checks that a number lies within, or a string or slice's length is
+// within, the inclusive range. Usage: `binding:"between=2 10"`.
+func Between(ctx context.Context, fl validator.FieldLevel) bool {
+	if isAbsent(fl.Field()) {
+		return true
+	}
+
+	params := strings.Fields(fl.Param())
+	if len(params) != 2 {
+		return ruleFailed(ctx, "between", fmt.Errorf("min and max parameters are required, got %q", fl.Param()))
+	}
+	min, errMin := strconv.ParseFloat(params[0], 64)
+	max, errMax := strconv.ParseFloat(params[1], 64)
+	if errMin != nil || errMax != nil {
+		return ruleFailed(ctx, "between", fmt.Errorf("parameters must be numeric, got %q", fl.Param()))
+	}
+
+	size, ok := fieldSize(fl.Field())
+	if !ok {
+		return false
+	}
+	return size >= min && size <= max
+}
+
+// Regex checks the field against a regular expression. Commas and pipes in
+// the pattern must be escaped as 0x2C and 0x7C. Usage: `binding:"regex=^[A-Z]{3}$"`.
+func Regex(ctx context.Context, fl validator.FieldLevel) bool {
+	if isAbsent(fl.Field()) {
+		return true
+	}
+
+	compiled, err := compilePattern(fl.Param())
+	if err != nil {
+		return ruleFailed(ctx, "regex", err)
+	}
+	return compiled.MatchString(fl.Field().String())
+}
+
+// RegisterPattern registers a named rule matching pattern, compiling it now
+// so an invalid pattern fails at startup rather than on a request.
+// Usage: RegisterPattern("sku", `^[A-Z]{3}-\d{4}$`), then `binding:"sku"`.
+func RegisterPattern(name, pattern string) error {
+	compiled, err := compilePattern(pattern)
+	if err != nil {
+		return err
+	}
+	return RegisterOptionalRule(name, func(fl validator.FieldLevel) bool {
+		if isAbsent(fl.Field()) {
+			return true
+		}
+		return compiled.MatchString(fl.Field().String())
+	})
+}
+
+// compilePattern compiles a regex rule pattern once, keeping the error of an
+// invalid pattern too
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := regexCache.Load(pattern); ok {
+		compiled := cached.(compiledPattern)
+		return compiled.regexp, compiled.err
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		err = fmt.Errorf("invalid regex pattern %q: %w", pattern, err)
+	}
+	regexCache.Store(pattern, compiledPattern{compiled, err})
+	return compiled, err
+}
+
+// Date checks that a string field parses as a date or date-time
+func Date(fl validator.FieldLevel) bool {
+	if isAbsent(fl.Field()) {
+		return true
+	}
+
+	value := fl.Field().String()
+	for _, layout := range dateLayouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Confirmed checks that a matching confirmation field holds the same value,
+// e.g. Password must equal PasswordConfirmation (json "password_confirmation")
+func Confirmed(fl validator.FieldLevel) bool {
+	parent := fl.Parent()
+	if parent.Kind() == reflect.Ptr {
+		parent = parent.Elem()
+	}
+	if parent.Kind() != reflect.Struct {
+		return false
+	}
+
+	confirmation := parent.FieldByName(fl.StructFieldName() + "Confirmation")
+	if !confirmation.IsValid() {
+		confirmation = fieldByJSONName(parent, jsonName(parent, fl.StructFieldName())+"_confirmation")
+	}
+	if !confirmation.IsValid() {
+		return false
+	}
+
+	return reflect.DeepEqual(fl.Field().Interface(), confirmation.Interface())
+}
+
+// Size checks that a number equals, or a string or slice's length is exactly,
+// the parameter. Usage: `binding:"size=5"`.
+func Size(ctx context.Context, fl validator.FieldLevel) bool {
+	if isAbsent(fl.Field()) {
+		return true
+	}
+
+	want, err := strconv.ParseFloat(fl.Param(), 64)
+	if err != nil {
+		return ruleFailed(ctx, "size", fmt.Errorf("parameter must be numeric, got %q", fl.Param()))
+	}
+
+	size, ok := fieldSize(fl.Field())
+	return ok && size == want
+}
+
+// Digits checks that the field is a whole number with exactly the given
+// number of digits, keeping leading zeros in strings such as postal codes.
+// Usage: `binding:"digits=5"`.
+func Digits(ctx context.Context, fl validator.FieldLevel) bool {
+	if isAbsent(fl.Field()) {
+		return true
+	}
+
+	want, err := strconv.Atoi(fl.Param())
+	if err != nil {
+		return ruleFailed(ctx, "digits", fmt.Errorf("parameter must be an integer, got %q", fl.Param()))
+	}
+
+	count, ok := digitCount(fl.Field())
+	return ok && count == want
+}
+
+// DigitsBetween checks that the field is a whole number whose digit count lies
+// within the inclusive range. Usage: `binding:"digits_between=4 6"`.
+func DigitsBetween(ctx context.Context, fl validator.FieldLevel) bool {
+	if isAbsent(fl.Field()) {
+		return true
+	}
+
+	params := strings.Fields(fl.Param())
+	if len(params) != 2 {
+		return ruleFailed(ctx, "digits_between", fmt.Errorf("min and max parameters are required, got %q", fl.Param()))
+	}
+	min, errMin := strconv.Atoi(params[0])
+	max, errMax := strconv.Atoi(params[1])
+	if errMin != nil || errMax != nil {
+		return ruleFailed(ctx, "digits_between", fmt.Errorf("parameters must be integers, got %q", fl.Param()))
+	}
+
+	count, ok := digitCount(fl.Field())
+	return ok && count >= min && count <= max
+}
+
+// digitCount counts the digits of an integer or of a string made only of digits
+func digitCount(field reflect.Value) (int, bool) {
+	var digits string
+	switch field.Kind() {
+	case reflect.String:
+		digits = field.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		digits = strconv.FormatInt(field.Int(), 10)
+		digits = strings.TrimPrefix(digits, "-")
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		digits = strconv.FormatUint(field.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		// Decoded JSON numbers are floats; only whole numbers have digits
+		f := field.Float()
+		if f != float64(int64(f)) {
+			return 0, false
+		}
+		digits = strings.TrimPrefix(strconv.FormatInt(int64(f), 10), "-")
+	default:
+		return 0, false
+	}
+
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+	}
+	return len(digits), len(digits) > 0
+}
+
+// fieldSize returns the Laravel "size" of a value: numeric value for numbers,
+// character count for strings and length for slices, arrays and maps
+func fieldSize(field reflect.Value) (float64, bool) {
+	switch field.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(field.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(field.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(field.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(field.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), true
+	}
+	return 0, false
+}
+
+// jsonName returns the json tag name of a struct field, or the field name
+func jsonName(parent reflect.Value, fieldName string) string {
+	field, ok := parent.Type().FieldByName(fieldName)
+	if !ok {
+		return fieldName
+	}
+	return JSONFieldName(field)
+}
+
+// fieldByJSONName finds a struct field by its json tag name
+func fieldByJSONName(parent reflect.Value, name string) reflect.Value {
+	parentType := parent.Type()
+	for i := 0; i < parentType.NumField(); i++ {
+		if strings.Split(parentType.Field(i).Tag.Get("json"), ",")[0] == name {
+			return parent.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
@@ -0,0 +1,167 @@
+package validators
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ruleValidator returns a validator with the package's rules registered the
+// way RegisterOptionalRule registers them
+func ruleValidator(t *testing.T) *validator.Validate {
+	t.Helper()
+	v := validator.New()
+	for name, rule := range map[string]RuleFunc{"not_in": NotIn, "date": Date} {
+		if err := v.RegisterValidation(name, validator.Func(rule), true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, rule := range map[string]RuleFuncCtx{
+		"between":        Between,
+		"regex":          Regex,
+		"size":           Size,
+		"digits":         Digits,
+		"digits_between": DigitsBetween,
+	} {
+		if err := v.RegisterValidationCtx(name, validator.FuncCtx(rule), true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return v
+}
+
+func intPtr(i int) *int { return &i }
+
+type ruleCase struct {
+	name  string
+	value interface{}
+	tag   string
+	valid bool
+}
+
+func runRuleCases(t *testing.T, cases []ruleCase) {
+	t.Helper()
+	v := ruleValidator(t)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := v.Var(tc.value, tc.tag)
+			if tc.valid && err != nil {
+				t.Fatalf("expected %v to pass %s, got %v", tc.value, tc.tag, err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatalf("expected %v to fail %s", tc.value, tc.tag)
+			}
+		})
+	}
+}
+
+func TestRulesCheckZeroValues(t *testing.T) {
+	runRuleCases(t, []ruleCase{
+		{"between zero int", 0, "between=1 10", false},
+		{"between zero float", 0.0, "between=1 10", false},
+		{"between zero in range", 0, "between=0 10", true},
+		{"between zero pointer", intPtr(0), "between=1 10", false},
+		{"between nil pointer", (*int)(nil), "between=1 10", true},
+		{"between empty string", "", "between=2 10", true},
+		{"between string", "a", "between=2 10", false},
+		{"not_in zero", 0, "not_in=0 1", false},
+		{"not_in false", false, "not_in=false", false},
+		{"not_in allowed", 2, "not_in=0 1", true},
+		{"date empty string", "", "date", true},
+		{"date", "2024-02-30", "date", false},
+		{"regex empty string", "", "regex=^[A-Z]+$", true},
+		{"regex", "abc", "regex=^[A-Z]+$", false},
+	})
+}
+
+func TestSizeAndDigitRulesTellNilFromZero(t *testing.T) {
+	runRuleCases(t, []ruleCase{
+		{"size zero", 0, "size=5", false},
+		{"size zero matches", 0, "size=0", true},
+		{"size zero pointer", intPtr(0), "size=5", false},
+		{"size nil pointer", (*int)(nil), "size=5", true},
+		{"size empty string", "", "size=5", true},
+		{"size nil slice", []string(nil), "size=2", true},
+		{"size empty slice", []string{}, "size=2", false},
+		{"digits zero", 0, "digits=5", false},
+		{"digits zero single digit", 0, "digits=1", true},
+		{"digits zero pointer", intPtr(0), "digits=5", false},
+		{"digits nil pointer", (*int)(nil), "digits=5", true},
+		{"digits empty string", "", "digits=5", true},
+		{"digits leading zeros", "00501", "digits=5", true},
+		{"digits_between zero", 0, "digits_between=2 4", false},
+		{"digits_between zero float", 0.0, "digits_between=2 4", false},
+		{"digits_between zero pointer", intPtr(0), "digits_between=2 4", false},
+		{"digits_between nil pointer", (*int)(nil), "digits_between=2 4", true},
+		{"digits_between in range", 1234, "digits_between=2 4", true},
+	})
+}
+
+func TestMalformedRuleParametersReturnRuleErrors(t *testing.T) {
+	for name, rule := range map[string]RuleFuncCtx{
+		"between":        Between,
+		"regex":          Regex,
+		"size":           Size,
+		"digits":         Digits,
+		"digits_between": DigitsBetween,
+	} {
+		if err := RegisterOptionalRuleCtx(name, rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		rule  string
+		value interface{}
+		tag   string
+	}{
+		{"between", 5, "between=2"},
+		{"between", 5, "between=a b"},
+		{"regex", "abc", "regex=[a-z"},
+		{"size", "abc", "size=three"},
+		{"digits", 123, "digits=1.5"},
+		{"digits_between", 123, "digits_between=4"},
+		{"digits_between", 123, "digits_between=a b"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tag, func(t *testing.T) {
+			_, err := ValidateMapE(map[string]interface{}{"field": test.value}, map[string]string{"field": test.tag})
+			var ruleErr *RuleError
+			if !errors.As(err, &ruleErr) || ruleErr.Rule != test.rule {
+				t.Fatalf("expected a %s RuleError, got %v", test.rule, err)
+			}
+			if _, ok := AsValidationErrors(err); ok {
+				t.Fatalf("malformed parameters must not be reported as invalid input: %v", err)
+			}
+		})
+	}
+}
+
+func TestMalformedRuleParametersFailFieldWithoutValidation(t *testing.T) {
+	v := ruleValidator(t)
+
+	for _, tag := range []string{"regex=[a-z", "between=2", "size=three"} {
+		if err := v.Var("abc", tag); err == nil {
+			t.Fatalf("expected %s to fail the field on a bare validator", tag)
+		}
+	}
+}
+
+func TestRegisterPatternCompilesAtRegistration(t *testing.T) {
+	if err := RegisterPattern("broken_pattern", "[a-z"); err == nil {
+		t.Fatal("expected an invalid pattern to be rejected at registration")
+	}
+	if err := RegisterPattern("sku", `^[A-Z]{3}-\d{4}$`); err != nil {
+		t.Fatal(err)
+	}
+
+	v := Engine()
+	if err := v.Var("ABC-1234", "sku"); err != nil {
+		t.Fatalf("expected a matching value to pass, got %v", err)
+	}
+	if err := v.Var("abc", "sku"); err == nil {
+		t.Fatal("expected a non-matching value to fail")
+	}
+}
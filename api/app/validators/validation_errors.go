@@ -0,0 +1,75 @@
+package validators
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"base_lara_go_project/app/core"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes one failed rule on one field
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors is returned when input fails validation. It keeps which
+// rule failed on each field so handlers can errors.As it and render it
+// consistently.
+type ValidationErrors []FieldError
+
+// Error lists every message
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldError := range e {
+		messages[i] = fieldError.Message
+	}
+	return strings.Join(messages, " ")
+}
+
+// StatusCode returns the HTTP status for failed validation: 400, or 422 as
+// in Laravel when app.validation_unprocessable is enabled
+func (e ValidationErrors) StatusCode() int {
+	if core.GetBool("app.validation_unprocessable", false) {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusBadRequest
+}
+
+// ToMap returns the messages keyed by field, as FormatErrors does
+func (e ValidationErrors) ToMap() map[string][]string {
+	formatted := make(map[string][]string, len(e))
+	for _, fieldError := range e {
+		formatted[fieldError.Field] = append(formatted[fieldError.Field], fieldError.Message)
+	}
+	return formatted
+}
+
+// AsValidationErrors extracts ValidationErrors from err, converting the
+// validator's own errors returned by request binding. It reports false when
+// err is not a validation error.
+func AsValidationErrors(err error) (ValidationErrors, bool) {
+	var validationErrors ValidationErrors
+	if errors.As(err, &validationErrors) {
+		return validationErrors, true
+	}
+
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return nil, false
+	}
+
+	converted := make(ValidationErrors, len(fieldErrors))
+	for i, fieldError := range fieldErrors {
+		converted[i] = FieldError{
+			Field:   fieldError.Field(),
+			Rule:    fieldError.Tag(),
+			Message: FormatError(fieldError),
+		}
+	}
+	return converted, true
+}
@@ -0,0 +1,79 @@
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"base_lara_go_project/app/core"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+type signupInput struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+func TestAsValidationErrorsKeepsRuleNamesFromBinding(t *testing.T) {
+	Engine().RegisterTagNameFunc(JSONFieldName)
+
+	err := binding.Validator.ValidateStruct(&signupInput{Email: "not-an-email"})
+	validationErrors, ok := AsValidationErrors(fmt.Errorf("binding: %w", err))
+	if !ok {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+
+	rules := map[string]string{}
+	for _, fieldError := range validationErrors {
+		rules[fieldError.Field] = fieldError.Rule
+	}
+	if want := map[string]string{"email": "email", "password": "required"}; !reflect.DeepEqual(rules, want) {
+		t.Fatalf("expected failed rules %v, got %v", want, rules)
+	}
+}
+
+func TestValidateMapERecordsFailedRules(t *testing.T) {
+	_, err := ValidateMapE(
+		map[string]interface{}{"age": 15},
+		map[string]string{"name": "required", "age": "required,gte=18"},
+	)
+
+	var validationErrors ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	rules := map[string]string{}
+	for _, fieldError := range validationErrors {
+		rules[fieldError.Field] = fieldError.Rule
+	}
+	if want := map[string]string{"name": "required", "age": "gte"}; !reflect.DeepEqual(rules, want) {
+		t.Fatalf("expected failed rules %v, got %v", want, rules)
+	}
+	if messages := validationErrors.ToMap(); len(messages["name"]) != 1 || len(messages["age"]) != 1 {
+		t.Fatalf("expected one message per field, got %v", messages)
+	}
+}
+
+func TestAsValidationErrorsIgnoresOtherErrors(t *testing.T) {
+	if _, ok := AsValidationErrors(errors.New("unexpected EOF")); ok {
+		t.Fatal("expected a non-validation error to be reported as such")
+	}
+}
+
+func TestValidationErrorsStatusCodeIsOptIn(t *testing.T) {
+	validationErrors := ValidationErrors{{Field: "email", Rule: "required", Message: "The email field is required."}}
+
+	core.Set("app", map[string]interface{}{})
+	if status := validationErrors.StatusCode(); status != http.StatusBadRequest {
+		t.Fatalf("expected 400 by default, got %d", status)
+	}
+
+	core.Set("app", map[string]interface{}{"validation_unprocessable": "true"})
+	defer core.Set("app", map[string]interface{}{})
+	if status := validationErrors.StatusCode(); status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 when opted in, got %d", status)
+	}
+}
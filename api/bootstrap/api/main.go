@@ -4,8 +4,8 @@ import (
 	"base_lara_go_project/app/core"
 	"base_lara_go_project/app/facades"
 	"base_lara_go_project/app/providers"
-	"base_lara_go_project/config"
 	_ "base_lara_go_project/routes/api/v1/auth"
+	_ "base_lara_go_project/routes/system"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,6 +13,7 @@ import (
 func main() {
 	// register config first
 	providers.RegisterConfig()
+	providers.RegisterLogging()
 
 	// register service providers
 	providers.RegisterFormFieldValidators()
@@ -26,6 +27,9 @@ func main() {
 	providers.RegisterRepository()
 	providers.RegisterServices()
 
+	// Report on every client the providers above registered
+	providers.RegisterMonitoring()
+
 	// Initialize core systems
 	core.InitializeRegistry()
 	core.InitializeEventDispatcher()
@@ -54,8 +58,15 @@ func main() {
 
 	providers.RunMigrations()
 
+	// Config is read on every request from here on, so serve it from a snapshot
+	core.Freeze()
+
+	// Boot is over, so a fatal error can no longer exit before logs are written
+	providers.StartAsyncLogging()
+
 	router := gin.Default()
 	providers.RegisterRoutes(router)
-	appConfig := config.AppConfig()
-	router.Run(":" + appConfig["port"].(string))
+	providers.Serve(router, ":"+core.GetString("app.port", "8080"))
+
+	providers.WaitForShutdown()
 }
@@ -4,8 +4,9 @@ import (
 	"base_lara_go_project/app/core"
 	"base_lara_go_project/app/facades"
 	"base_lara_go_project/app/providers"
-	"base_lara_go_project/config"
+	"context"
 	"log"
+	"os"
 )
 
 func main() {
@@ -13,6 +14,7 @@ func main() {
 
 	// Register config first
 	providers.RegisterConfig()
+	providers.RegisterLogging()
 
 	// Register all service providers
 	providers.RegisterFormFieldValidators()
@@ -24,7 +26,14 @@ func main() {
 	providers.RegisterMessageProcessor()
 	providers.RegisterEventDispatcher()
 	providers.RegisterRepository()
-	providers.RegisterServices() // Register service provider
+
+	// A worker missing services fails jobs later in confusing ways, so refuse to start
+	if err := providers.RegisterServicesStrict(); err != nil {
+		log.Fatalf("Failed to register services: %v", err)
+	}
+
+	// Report on every client the providers above registered
+	providers.RegisterMonitoring()
 
 	// Initialize core systems
 	core.InitializeRegistry()
@@ -56,11 +65,13 @@ func main() {
 
 	log.Println("All service providers registered successfully")
 
+	// Boot is over, so a fatal error can no longer exit before logs are written
+	providers.StartAsyncLogging()
+
 	// Start a worker for all enabled queues
-	queueConfig := config.QueueConfig()
-	enabledQueues := queueConfig["enabled_queues"].([]string)
-	worker := core.NewQueueWorker(enabledQueues)
+	worker := providers.NewWorker()
+	providers.RegisterWorkerMetrics(worker)
 
-	log.Printf("Starting queue worker with %d enabled queues", len(enabledQueues))
-	worker.Start()
+	log.Printf("Starting queue worker with %d enabled queues", len(core.GetStringSlice("queue.enabled_queues")))
+	os.Exit(providers.RunWorker(context.Background(), worker, providers.LoadWorkerConfig()))
 }
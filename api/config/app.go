@@ -1,7 +1,7 @@
 package config
 
 import (
-	"os"
+	"base_lara_go_project/app/utils/env"
 )
 
 func AppConfig() map[string]interface{} {
@@ -13,12 +13,14 @@ func AppConfig() map[string]interface{} {
 		"port":                getEnv("APP_PORT", "8080"),
 		"secret":              getEnv("API_SECRET", "changeme"),
 		"token_hour_lifespan": getEnv("TOKEN_HOUR_LIFESPAN", "1"),
+		"shutdown_timeout":    getEnv("SHUTDOWN_TIMEOUT", "30"),
+		"drain_timeout":       getEnv("HTTP_DRAIN_TIMEOUT", "20"),
+		"request_timeout":     getEnv("HTTP_REQUEST_TIMEOUT", "0"),
+		// Answer failed validation with 422 instead of 400
+		"validation_unprocessable": getEnv("VALIDATION_UNPROCESSABLE", "false"),
 	}
 }
 
 func getEnv(key, fallback string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return fallback
+	return env.Env(key, fallback)
 }
@@ -9,19 +9,24 @@ import (
 
 // CacheConfig holds the cache configuration
 type CacheConfig struct {
-	Store  string        `json:"store"`
-	Prefix string        `json:"prefix"`
-	TTL    time.Duration `json:"ttl"`
-	Redis  RedisConfig   `json:"redis"`
-	File   FileConfig    `json:"file"`
+	Store     string        `json:"store"`
+	Prefix    string        `json:"prefix"`
+	TTL       time.Duration `json:"ttl"`
+	TTLJitter float64       `json:"ttl_jitter"`
+	Redis     RedisConfig   `json:"redis"`
+	File      FileConfig    `json:"file"`
+	Array     ArrayConfig   `json:"array"`
 }
 
 // RedisConfig holds Redis-specific configuration
 type RedisConfig struct {
-	Host     string `json:"host"`
-	Port     int    `json:"port"`
-	Password string `json:"password"`
-	Database int    `json:"database"`
+	Host              string        `json:"host"`
+	Port              int           `json:"port"`
+	Password          string        `json:"password"`
+	Database          int           `json:"database"`
+	MinBudget         time.Duration `json:"min_budget"`
+	ScanCount         int64         `json:"scan_count"`
+	CompressThreshold int           `json:"compress_threshold"`
 }
 
 // FileConfig holds file cache configuration
@@ -29,6 +34,11 @@ type FileConfig struct {
 	Path string `json:"path"`
 }
 
+// ArrayConfig holds in-memory cache configuration
+type ArrayConfig struct {
+	MaxBytes int64 `json:"max_bytes"`
+}
+
 // GetCacheConfig returns the cache configuration
 func GetCacheConfig() CacheConfig {
 	// Load environment variables
@@ -42,6 +52,22 @@ func GetCacheConfig() CacheConfig {
 		}
 	}
 
+	// Parse TTL jitter percentage (default off)
+	ttlJitter := 0.0
+	if jitterStr := getEnv("CACHE_TTL_JITTER", ""); jitterStr != "" {
+		if jitter, err := strconv.ParseFloat(jitterStr, 64); err == nil {
+			ttlJitter = jitter
+		}
+	}
+
+	// Parse the in-memory cache size limit in MB (default unlimited)
+	arrayMaxMB := 0
+	if maxStr := getEnv("CACHE_ARRAY_MAX_MB", ""); maxStr != "" {
+		if maxMB, err := strconv.Atoi(maxStr); err == nil {
+			arrayMaxMB = maxMB
+		}
+	}
+
 	// Parse Redis port
 	redisPort := 6379
 	if portStr := getEnv("REDIS_PORT", ""); portStr != "" {
@@ -58,6 +84,30 @@ func GetCacheConfig() CacheConfig {
 		}
 	}
 
+	// Parse minimum remaining deadline (ms) required before hitting Redis
+	redisMinBudget := 0
+	if budgetStr := getEnv("REDIS_MIN_BUDGET_MS", ""); budgetStr != "" {
+		if budget, err := strconv.Atoi(budgetStr); err == nil {
+			redisMinBudget = budget
+		}
+	}
+
+	// Parse the SCAN COUNT used by pattern deletes
+	redisScanCount := 500
+	if countStr := getEnv("REDIS_SCAN_COUNT", ""); countStr != "" {
+		if count, err := strconv.Atoi(countStr); err == nil {
+			redisScanCount = count
+		}
+	}
+
+	// Parse the size (bytes) from which values are compressed (default off)
+	redisCompressThreshold := 0
+	if thresholdStr := getEnv("REDIS_COMPRESS_THRESHOLD", ""); thresholdStr != "" {
+		if threshold, err := strconv.Atoi(thresholdStr); err == nil {
+			redisCompressThreshold = threshold
+		}
+	}
+
 	// Handle Redis password - treat "null" as empty string
 	redisPassword := getEnv("REDIS_PASSWORD", "")
 	if redisPassword == "null" {
@@ -65,17 +115,24 @@ func GetCacheConfig() CacheConfig {
 	}
 
 	return CacheConfig{
-		Store:  getEnv("CACHE_STORE", "array"),
-		Prefix: getEnv("CACHE_PREFIX", "base_lara_go_cache_"),
-		TTL:    time.Duration(ttlSeconds) * time.Second,
+		Store:     getEnv("CACHE_STORE", "array"),
+		Prefix:    getEnv("CACHE_PREFIX", "base_lara_go_cache_"),
+		TTL:       time.Duration(ttlSeconds) * time.Second,
+		TTLJitter: ttlJitter,
 		Redis: RedisConfig{
-			Host:     getEnv("REDIS_HOST", "redis"),
-			Port:     redisPort,
-			Password: redisPassword,
-			Database: redisDB,
+			Host:              getEnv("REDIS_HOST", "redis"),
+			Port:              redisPort,
+			Password:          redisPassword,
+			Database:          redisDB,
+			MinBudget:         time.Duration(redisMinBudget) * time.Millisecond,
+			ScanCount:         int64(redisScanCount),
+			CompressThreshold: redisCompressThreshold,
 		},
 		File: FileConfig{
 			Path: getEnv("CACHE_FILE_PATH", "storage/framework/cache/data"),
 		},
+		Array: ArrayConfig{
+			MaxBytes: int64(arrayMaxMB) << 20,
+		},
 	}
 }
@@ -0,0 +1,13 @@
+package config
+
+func CorsConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"allowed_origins":   getEnv("CORS_ALLOWED_ORIGINS", "https://app.baselaragoproject.test"),
+		"allowed_methods":   getEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS"),
+		"allowed_headers":   getEnv("CORS_ALLOWED_HEADERS", "Origin,Content-Type,Accept,Authorization"),
+		"exposed_headers":   getEnv("CORS_EXPOSED_HEADERS", "Content-Length"),
+		"allow_credentials": getEnv("CORS_ALLOW_CREDENTIALS", "true"),
+		"echo_origin":       getEnv("CORS_ECHO_ORIGIN", "false"),
+		"max_age":           getEnv("CORS_MAX_AGE", "43200"),
+	}
+}
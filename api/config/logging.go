@@ -0,0 +1,21 @@
+package config
+
+func LoggingConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"channel": getEnv("LOG_CHANNEL", "stderr"),
+		"level":   getEnv("LOG_LEVEL", "debug"),
+		"format":  getEnv("LOG_FORMAT", "text"),
+		"async":   getEnv("LOG_ASYNC", "false"),
+		"channels": map[string]interface{}{
+			"stack": map[string]interface{}{
+				"channels": getEnv("LOG_STACK", "stderr,file"),
+			},
+			"file": map[string]interface{}{
+				"path":      getEnv("LOG_PATH", "storage/logs/app.log"),
+				"max_size":  getEnv("LOG_MAX_SIZE_MB", "100"),
+				"max_files": getEnv("LOG_MAX_FILES", "7"),
+				"daily":     getEnv("LOG_DAILY", "true"),
+			},
+		},
+	}
+}
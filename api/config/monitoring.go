@@ -0,0 +1,9 @@
+package config
+
+func MonitoringConfig() map[string]interface{} {
+	return map[string]interface{}{
+		// Bearer token required for /metrics and /health/details; they
+		// refuse every request while it is empty
+		"token": getEnv("MONITORING_TOKEN", ""),
+	}
+}
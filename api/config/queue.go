@@ -5,12 +5,14 @@ func QueueConfig() map[string]interface{} {
 		"default": getEnv("QUEUE_CONNECTION", "sqs"),
 		"connections": map[string]interface{}{
 			"sqs": map[string]interface{}{
-				"driver":   "sqs",
-				"key":      getEnv("SQS_ACCESS_KEY", "local"),
-				"secret":   getEnv("SQS_SECRET_KEY", "local"),
-				"region":   getEnv("SQS_REGION", "us-east-1"),
-				"queue":    getEnv("SQS_QUEUE", "default"),
-				"endpoint": getEnv("SQS_ENDPOINT", "http://localhost:9324"),
+				"driver":             "sqs",
+				"key":                getEnv("SQS_ACCESS_KEY", "local"),
+				"secret":             getEnv("SQS_SECRET_KEY", "local"),
+				"region":             getEnv("SQS_REGION", "us-east-1"),
+				"queue":              getEnv("SQS_QUEUE", "default"),
+				"endpoint":           getEnv("SQS_ENDPOINT", "http://localhost:9324"),
+				"wait_time":          getEnv("SQS_WAIT_TIME_SECONDS", "10"),
+				"visibility_timeout": getEnv("SQS_VISIBILITY_TIMEOUT", "60"),
 			},
 		},
 		"queues": map[string]interface{}{
@@ -18,6 +20,33 @@ func QueueConfig() map[string]interface{} {
 			"mail":   getEnv("SQS_QUEUE_MAIL", "default"),
 			"events": getEnv("SQS_QUEUE_EVENTS", "default"),
 		},
+		// Jobs per second each queue may process; 0 disables the limit
+		"rate_limits": map[string]interface{}{
+			"jobs": map[string]interface{}{
+				"rate":  getEnv("QUEUE_JOBS_RATE", "0"),
+				"burst": getEnv("QUEUE_JOBS_BURST", "1"),
+			},
+			"mail": map[string]interface{}{
+				"rate":  getEnv("QUEUE_MAIL_RATE", "0"),
+				"burst": getEnv("QUEUE_MAIL_BURST", "1"),
+			},
+			"events": map[string]interface{}{
+				"rate":  getEnv("QUEUE_EVENTS_RATE", "0"),
+				"burst": getEnv("QUEUE_EVENTS_BURST", "1"),
+			},
+		},
+		// A worker exits to be restarted after max_jobs jobs or once its heap
+		// reaches memory_limit megabytes; 0 disables either limit
+		"worker": map[string]interface{}{
+			"max_jobs":     getEnv("QUEUE_WORKER_MAX_JOBS", "0"),
+			"memory_limit": getEnv("QUEUE_WORKER_MEMORY_LIMIT", "0"),
+			// Address such as ":9100" to serve worker metrics on; empty disables it
+			"metrics_addr": getEnv("QUEUE_WORKER_METRICS_ADDR", ""),
+		},
+		// Prioritized workers drain enabled_queues in order, giving lower
+		// queues one turn after fairness_quota rounds on higher ones (0 never)
+		"prioritized":    getEnv("QUEUE_PRIORITIZED", "false"),
+		"fairness_quota": getEnv("QUEUE_FAIRNESS_QUOTA", "10"),
 		"enabled_queues": []string{
 			getEnv("SQS_QUEUE_JOBS", "default"),
 			getEnv("SQS_QUEUE_MAIL", "default"),
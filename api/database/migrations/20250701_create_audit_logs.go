@@ -0,0 +1,18 @@
+package migrations
+
+import (
+	db "base_lara_go_project/app/models/db"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var CreateAuditLogs = &gormigrate.Migration{
+	ID: "20250701_create_audit_logs",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&db.AuditLog{})
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable("audit_logs")
+	},
+}
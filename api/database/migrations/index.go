@@ -8,5 +8,6 @@ func AllMigrations() []*gormigrate.Migration {
 		CreateRoles,
 		CreatePermissions,
 		CreatePivotTables,
+		CreateAuditLogs,
 	}
 }
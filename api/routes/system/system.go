@@ -0,0 +1,23 @@
+package system
+
+import (
+	"base_lara_go_project/app/http/controllers"
+	"base_lara_go_project/app/http/middlewares"
+	"base_lara_go_project/app/providers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Routes(router *gin.Engine) {
+	// Probes are public and only answer with a status
+	router.GET("/health", controllers.Health)
+	router.GET("/ready", controllers.Ready)
+
+	monitoring := router.Group("/", middlewares.RequireMonitoringToken())
+	monitoring.GET("/metrics", controllers.Metrics)
+	monitoring.GET("/health/details", controllers.HealthDetails)
+}
+
+func init() {
+	providers.RegisterRouteGroup(Routes)
+}
@@ -0,0 +1,86 @@
+package system
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"base_lara_go_project/app/core"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fixedStats reports the same stats on every scrape
+type fixedStats map[string]interface{}
+
+func (s fixedStats) GetStats() map[string]interface{} { return s }
+
+func systemRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	Routes(router)
+	return router
+}
+
+func get(router *gin.Engine, target, bearer string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, target, nil)
+	if bearer != "" {
+		request.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	return response
+}
+
+func TestMetricsRequireMonitoringToken(t *testing.T) {
+	core.Set("monitoring", map[string]interface{}{"token": "scrape-secret"})
+	defer core.Set("monitoring", map[string]interface{}{})
+	core.RegisterStats("test_cache", fixedStats{"hits": 3, "addr": "10.0.0.5:6379"})
+	router := systemRouter()
+
+	for _, bearer := range []string{"", "wrong"} {
+		if response := get(router, "/metrics", bearer); response.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401 for token %q, got %d", bearer, response.Code)
+		}
+	}
+
+	response := get(router, "/metrics", "scrape-secret")
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected 200 with the token, got %d", response.Code)
+	}
+	body := response.Body.String()
+	if !strings.Contains(body, "app_test_cache_hits 3") || !strings.Contains(body, "app_runtime_goroutines") {
+		t.Fatalf("expected component and runtime metrics, got %q", body)
+	}
+	if strings.Contains(body, "10.0.0.5") {
+		t.Fatal("expected non-numeric stats such as addresses to be left out")
+	}
+}
+
+func TestMetricsRefusedWithoutConfiguredToken(t *testing.T) {
+	core.Set("monitoring", map[string]interface{}{"token": ""})
+	router := systemRouter()
+
+	for _, bearer := range []string{"", "anything"} {
+		if response := get(router, "/metrics", bearer); response.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401 while no token is configured, got %d", response.Code)
+		}
+	}
+}
+
+func TestHealthDetailsRequireMonitoringToken(t *testing.T) {
+	core.Set("monitoring", map[string]interface{}{"token": "scrape-secret"})
+	defer core.Set("monitoring", map[string]interface{}{})
+	router := systemRouter()
+
+	if response := get(router, "/health/details", ""); response.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without the token, got %d", response.Code)
+	}
+	if response := get(router, "/health/details", "scrape-secret"); !strings.Contains(response.Body.String(), `"components"`) {
+		t.Fatalf("expected the component report with the token, got %s", response.Body.String())
+	}
+	if response := get(router, "/health", ""); strings.Contains(response.Body.String(), "components") {
+		t.Fatalf("expected the public probe to show only a status, got %s", response.Body.String())
+	}
+}